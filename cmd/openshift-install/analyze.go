@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/gather/analyze"
+)
+
+func newAnalyzeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "analyze BUNDLE",
+		Short: "Look for known install failures in a gathered log bundle",
+		Long: `Look for known install failures in a gathered log bundle.
+
+BUNDLE is a log-bundle-<id>.tar.gz written by 'gather bootstrap', a
+cluster-bundle-<id>.tar.gz written by 'gather cluster', or a directory holding
+an unpacked bundle. Each failure found is printed with the files of the
+bundle that show it.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if err := runAnalyzeCmd(os.Stdout, args[0]); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+}
+
+func runAnalyzeCmd(w io.Writer, path string) error {
+	bundle, err := analyze.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open log bundle")
+	}
+	defer bundle.Close()
+
+	findings, err := analyze.Analyze(bundle)
+	if err != nil {
+		return errors.Wrap(err, "failed to analyze log bundle")
+	}
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No known failures found")
+		return nil
+	}
+	for i, finding := range findings {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, finding.Error())
+		for _, file := range finding.Files {
+			fmt.Fprintf(w, "  see %s\n", file)
+		}
+	}
+	return nil
+}
+
+// logBundleFindings analyzes a freshly gathered bundle and logs the known
+// failures found in it.
+func logBundleFindings(path string) {
+	bundle, err := analyze.Open(path)
+	if err != nil {
+		logrus.WithError(err).Debug("Failed to open log bundle for analysis")
+		return
+	}
+	defer bundle.Close()
+
+	findings, err := analyze.Analyze(bundle)
+	if err != nil {
+		logrus.WithError(err).Debug("Failed to analyze log bundle")
+	}
+	for _, finding := range findings {
+		logrus.WithField("files", finding.Files).Error(finding.Error())
+	}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"text/tabwriter"
+	"time"
+
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/machines"
+	assetquota "github.com/openshift/installer/pkg/asset/quota"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/quota"
+)
+
+func newCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the prerequisites of an install",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newCheckQuotaCmd())
+	cmd.AddCommand(newCheckMirrorsCmd())
+	return cmd
+}
+
+var (
+	checkQuotaOpts struct {
+		policyFile     string
+		minFreePercent float64
+		minFree        int64
+		unknown        string
+		scale          []string
+	}
+)
+
+func newCheckQuotaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Check the cloud quotas against the resources the cluster needs",
+		Long: `Check the cloud quotas against the resources the cluster needs.
+
+The machines of the install config are compared with the quotas, or on vSphere
+and oVirt with the capacity, of the platform. Each constraint is printed with
+the quota it was checked against and the command fails when one of them is not
+available.
+
+The constraints are checked against the quota policy of quota-policy.yaml in
+the asset directory, of the file named by --policy or by the
+OPENSHIFT_INSTALL_QUOTA_POLICY environment variable. The other flags replace
+the settings of the policy.
+
+With --scale, the quotas of an installed cluster, found by the metadata.json and
+the install config or state file of the asset directory, are checked for the machines added to its compute machine
+sets instead, e.g. --scale workers=12 to grow the cluster to 12 compute
+machines. The target of --scale is 'workers' for all the compute machine sets,
+the name of a compute pool, or the name of a machine set, and +N adds N
+machines to it. Later --scale flags override earlier ones for the machine sets
+they target. The sizes are compared with those of the install; the resources
+of the installed cluster are expected to already count against the quotas.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runCheckQuotaCmd(context.TODO(), os.Stdout, rootOpts.dir, cmd.Flags()); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&checkQuotaOpts.policyFile, "policy", "", "Path to a quota policy file")
+	cmd.Flags().Float64Var(&checkQuotaOpts.minFreePercent, "min-free-percent", 0, "Percentage of each quota that must be left once the cluster is created")
+	cmd.Flags().Int64Var(&checkQuotaOpts.minFree, "min-free", 0, "Amount of each quota that must be left once the cluster is created")
+	cmd.Flags().StringArrayVar(&checkQuotaOpts.scale, "scale", nil, "Planned size of the compute machine sets of the installed cluster, as TARGET=N or TARGET=+N (can be repeated)")
+	cmd.Flags().StringVar(&checkQuotaOpts.unknown, "unknown", "", fmt.Sprintf("What to do with the constraints whose quota is unknown (%s or %s)", quota.UnknownWarn, quota.UnknownFail))
+	return cmd
+}
+
+func runCheckQuotaCmd(ctx context.Context, w io.Writer, directory string, flags *pflag.FlagSet) error {
+	assetStore, err := assetstore.NewStore(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+
+	ic := &installconfig.InstallConfig{}
+	mastersAsset := &machines.Master{}
+	workersAsset := &machines.Worker{}
+	policyAsset := &assetquota.Policy{}
+	if len(checkQuotaOpts.scale) > 0 {
+		// The sizes of the machine sets at install time are generated from
+		// the install config, which must not be asked for again.
+		found, err := assetStore.Load(ic)
+		if err != nil {
+			return err
+		}
+		if found == nil {
+			return errors.Errorf("--scale checks an installed cluster from its asset directory, but %s has neither its install-config.yaml nor its state file", directory)
+		}
+	}
+	if err := fetchForCheck(ctx, assetStore, ic, mastersAsset, workersAsset, policyAsset); err != nil {
+		return err
+	}
+	policy, err := checkQuotaPolicy(policyAsset.Policy, flags)
+	if err != nil {
+		return err
+	}
+
+	masters, err := mastersAsset.Machines()
+	if err != nil {
+		return err
+	}
+	workers, err := workersAsset.MachineSets()
+	if err != nil {
+		return err
+	}
+
+	var reports []quota.ConstraintReport
+	if len(checkQuotaOpts.scale) > 0 {
+		added, err := checkQuotaScaleOut(directory, workers)
+		if err != nil {
+			return err
+		}
+		if len(added) == 0 {
+			logrus.Info("The planned scale adds no compute machines")
+			return nil
+		}
+		reports, err = assetquota.ScaleOutReports(ctx, ic, added, policy)
+		if err != nil {
+			return err
+		}
+	} else {
+		reports, err = assetquota.Reports(ctx, ic, masters, workers, policy)
+		if err != nil {
+			return err
+		}
+	}
+	if len(reports) == 0 {
+		logrus.Infof("No quotas to check on %s", ic.Config.Platform.Name())
+		return nil
+	}
+	if err := writeQuotaReports(w, reports); err != nil {
+		return err
+	}
+	return assetquota.Verify(reports, policy)
+}
+
+// fetchForCheck fetches the assets without consuming the files of the asset
+// directory they, or their dependencies, were loaded from: a check leaves the
+// directory as it found it.
+func fetchForCheck(ctx context.Context, assetStore asset.Store, assets ...asset.Asset) error {
+	var preserved []asset.WritableAsset
+	seen := map[reflect.Type]bool{}
+	queue := append([]asset.Asset{}, assets...)
+	for len(queue) > 0 {
+		a := queue[0]
+		queue = queue[1:]
+		if seen[reflect.TypeOf(a)] {
+			continue
+		}
+		seen[reflect.TypeOf(a)] = true
+		if wa, ok := a.(asset.WritableAsset); ok {
+			preserved = append(preserved, wa)
+		}
+		queue = append(queue, a.Dependencies()...)
+	}
+
+	for _, a := range assets {
+		if err := assetStore.Fetch(ctx, a, preserved...); err != nil {
+			return errors.Wrapf(err, "failed to fetch %s", a.Name())
+		}
+	}
+	return nil
+}
+
+// checkQuotaScaleOut returns the compute machine sets of the machines the
+// --scale flags add to the installed cluster.
+func checkQuotaScaleOut(directory string, workers []machineapi.MachineSet) ([]machineapi.MachineSet, error) {
+	metadata, err := cluster.LoadMetadata(directory)
+	if err != nil {
+		return nil, errors.Wrap(err, "--scale checks an installed cluster, failed to load its metadata")
+	}
+	scales := make([]assetquota.Scale, 0, len(checkQuotaOpts.scale))
+	for _, s := range checkQuotaOpts.scale {
+		scale, err := assetquota.ParseScale(s)
+		if err != nil {
+			return nil, err
+		}
+		scales = append(scales, scale)
+	}
+	added, err := assetquota.ScaleOut(metadata.InfraID, workers, scales)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range added {
+		logrus.Infof("Checking quotas for %d more machines in %s", *w.Spec.Replicas, w.Name)
+	}
+	return added, nil
+}
+
+// checkQuotaPolicy returns the policy of the asset store with the settings
+// of the flags applied.
+func checkQuotaPolicy(policy *quota.Policy, flags *pflag.FlagSet) (*quota.Policy, error) {
+	if checkQuotaOpts.policyFile != "" {
+		data, err := ioutil.ReadFile(checkQuotaOpts.policyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read quota policy")
+		}
+		if policy, err = assetquota.ParsePolicy(data); err != nil {
+			return nil, errors.Wrapf(err, "invalid quota policy %s", checkQuotaOpts.policyFile)
+		}
+	}
+	if policy == nil {
+		policy = &quota.Policy{}
+	}
+	if flags.Changed("min-free-percent") {
+		policy.MinFreePercent = checkQuotaOpts.minFreePercent
+	}
+	if flags.Changed("min-free") {
+		policy.MinFree = checkQuotaOpts.minFree
+	}
+	if flags.Changed("unknown") {
+		policy.Unknown = quota.UnknownAction(checkQuotaOpts.unknown)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid quota policy")
+	}
+	return policy, nil
+}
+
+// writeQuotaReports writes a table of the constraints and the quotas they
+// were checked against.
+func writeQuotaReports(w io.Writer, reports []quota.ConstraintReport) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONSTRAINT\tREGION\tREQUIRED\tIN USE\tLIMIT\tHEADROOM\tRESULT")
+	for i := range reports {
+		report := &reports[i]
+		region := report.For.Region
+		if region == "" {
+			region = "-"
+		}
+		inUse, limit, headroom := "-", "-", "-"
+		if report.Quota != nil {
+			inUse = fmt.Sprint(report.Quota.InUse)
+			if report.Quota.Unlimited {
+				limit, headroom = "unlimited", "unlimited"
+			} else {
+				limit = fmt.Sprint(report.Quota.Limit)
+				headroom = fmt.Sprint(report.Headroom())
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n", report.For.Name, region, report.For.Count, inUse, limit, headroom, report.Result)
+	}
+	return tw.Flush()
+}
+
+func newCheckMirrorsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mirrors",
+		Short: "Check that the mirrors hold the content of the release image",
+		Long: `Check that the mirrors hold the content of the release image.
+
+The release image and the images it references are looked up, by digest, in
+the mirrors of the image content sources of the install config, with the
+credentials of the pull secret and trusting the additional trust bundle. Each
+mirror is printed with the number of images of the release in its source and
+the number it is missing, followed by the missing images, and the command
+fails when images of a source are in none of its mirrors.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+			defer cancel()
+			if err := runCheckMirrorsCmd(ctx, os.Stdout, rootOpts.dir); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+}
+
+func runCheckMirrorsCmd(ctx context.Context, w io.Writer, directory string) error {
+	assetStore, err := assetstore.NewStore(directory)
+	if err != nil {
+		return errors.Wrap(err, "failed to create asset store")
+	}
+
+	ic := &installconfig.InstallConfig{}
+	image := &releaseimage.Image{}
+	if err := assetStore.Fetch(ctx, ic); err != nil {
+		return errors.Wrapf(err, "failed to fetch %s", ic.Name())
+	}
+	if err := assetStore.Fetch(ctx, image); err != nil {
+		return errors.Wrapf(err, "failed to fetch %s", image.Name())
+	}
+	if len(ic.Config.ImageContentSources) == 0 {
+		logrus.Info("No image content sources to check")
+		return nil
+	}
+
+	reports, err := releaseimage.MirrorReports(ctx, ic.Config, image.PullSpec)
+	if err != nil {
+		return errors.Wrap(err, "failed to check the mirrors of the release image")
+	}
+	if err := writeMirrorReports(w, reports); err != nil {
+		return err
+	}
+	return releaseimage.VerifyMirrors(reports)
+}
+
+// writeMirrorReports writes a table of the mirrors and the images of the
+// release they are missing, followed by the missing images.
+func writeMirrorReports(w io.Writer, reports []releaseimage.MirrorReport) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tMIRROR\tIMAGES\tMISSING\tRESULT")
+	for i := range reports {
+		report := &reports[i]
+		mirror, missing := report.Mirror, fmt.Sprint(len(report.Missing))
+		switch report.Result() {
+		case releaseimage.NotMirrored:
+			mirror, missing = "-", "-"
+		case releaseimage.MirrorUnreachable:
+			missing = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", report.Source, mirror, len(report.Images), missing, report.Result())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for i := range reports {
+		report := &reports[i]
+		if len(report.Missing) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\nMissing from %s:\n", report.Mirror)
+		for _, image := range report.Missing {
+			fmt.Fprintf(w, "  %s\n", image)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const noneInstallConfig = `apiVersion: v1
+baseDomain: example.com
+metadata:
+  name: test-cluster
+controlPlane:
+  name: master
+  replicas: 3
+compute:
+- name: worker
+  replicas: 0
+networking:
+  machineNetwork:
+  - cidr: 10.0.0.0/16
+platform:
+  none: {}
+pullSecret: '{"auths":{"example.com":{"auth":"authorization value"}}}'
+`
+
+func TestCheckQuotaKeepsAssets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check-quota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "install-config.yaml"), []byte(noneInstallConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = runCheckQuotaCmd(context.Background(), &out, dir, newCheckQuotaCmd().Flags())
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "install-config.yaml"))
+	if assert.NoError(t, err, "check quota consumed the install config") {
+		assert.Equal(t, noneInstallConfig, string(data))
+	}
+}
+
+func TestCheckQuotaScaleNeedsInstallConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check-quota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "metadata.json"), []byte(`{"clusterName":"test-cluster","infraID":"test-cluster-x7k2p","none":{}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := newCheckQuotaCmd().Flags()
+	if err := flags.Set("scale", "workers=3"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { checkQuotaOpts.scale = nil }()
+	var out bytes.Buffer
+	err = runCheckQuotaCmd(context.Background(), &out, dir, flags)
+	assert.EqualError(t, err, "--scale checks an installed cluster from its asset directory, but "+dir+" has neither its install-config.yaml nor its state file")
+}
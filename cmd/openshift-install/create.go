@@ -27,12 +27,15 @@ import (
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/logging"
 	assetstore "github.com/openshift/installer/pkg/asset/store"
 	targetassets "github.com/openshift/installer/pkg/asset/targets"
 	destroybootstrap "github.com/openshift/installer/pkg/destroy/bootstrap"
+	"github.com/openshift/installer/pkg/metrics/gatherer"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
+	"github.com/openshift/installer/pkg/terraform"
 	"github.com/openshift/installer/pkg/types/baremetal"
 	cov1helpers "github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 	"github.com/openshift/library-go/pkg/route/routeapihelpers"
@@ -44,6 +47,12 @@ type target struct {
 	assets  []asset.WritableAsset
 }
 
+var (
+	createOpts struct {
+		infrastructureTimeout time.Duration
+	}
+)
+
 // each target is a variable to preserve the order when creating subcommands and still
 // allow other functions to directly access each target individually.
 var (
@@ -58,6 +67,21 @@ var (
 		assets: targetassets.InstallConfig,
 	}
 
+	signingRequestsTarget = target{
+		name: "Signing Requests",
+		command: &cobra.Command{
+			Use:   "signing-requests",
+			Short: "Generates the certificate signing requests of the signers for the external CA",
+			Long: `Generates the certificate signing requests of the signers for the external CA.
+
+With the certificate of an external CA in tls/external-ca.crt but not its key,
+the signers of the installer are signed out of band. The signing requests are
+written to tls/<signer>.csr next to their private keys. The certificates signed
+from them are placed in tls/<signer>.crt before creating the ignition configs.`,
+		},
+		assets: targetassets.SigningRequests,
+	}
+
 	manifestsTarget = target{
 		name: "Manifests",
 		command: &cobra.Command{
@@ -88,7 +112,8 @@ var (
 			// FIXME: add longer descriptions for our commands with examples for better UX.
 			// Long:  "",
 			PostRun: func(_ *cobra.Command, _ []string) {
-				ctx := context.Background()
+				ctx, cancel := interruptContext(context.Background())
+				defer cancel()
 
 				cleanup := setupFileHook(rootOpts.dir)
 				defer cleanup()
@@ -109,7 +134,7 @@ var (
 					if err2 := runGatherBootstrapCmd(rootOpts.dir); err2 != nil {
 						logrus.Error("Attempted to gather debug logs after installation failure: ", err2)
 					}
-					logrus.Fatal("Bootstrap failed to complete: ", err)
+					fatalInstall("create cluster", "BootstrapFailed", errors.Wrap(err, "Bootstrap failed to complete"))
 				}
 				timer.StopTimer("Bootstrap Complete")
 				timer.StartTimer("Bootstrap Destroy")
@@ -119,9 +144,9 @@ var (
 						"Warning: this should only be used for debugging purposes, and poses a risk to cluster stability.")
 				} else {
 					logrus.Info("Destroying the bootstrap resources...")
-					err = destroybootstrap.Destroy(rootOpts.dir)
+					err = destroybootstrap.Destroy(ctx, rootOpts.dir)
 					if err != nil {
-						logrus.Fatal(err)
+						fatalInstall("create cluster", "BootstrapDestroyFailed", err)
 					}
 				}
 				timer.StopTimer("Bootstrap Destroy")
@@ -131,17 +156,23 @@ var (
 					if err2 := logClusterOperatorConditions(ctx, config); err2 != nil {
 						logrus.Error("Attempted to gather ClusterOperator status after installation failure: ", err2)
 					}
+					// The nodes are often not reachable over SSH from here, so
+					// their journals are left to 'gather cluster'.
+					if err2 := runGatherClusterCmd(ctx, rootOpts.dir, false); err2 != nil {
+						logrus.Error("Attempted to gather debug data after installation failure: ", err2)
+					}
 					logTroubleshootingLink()
-					logrus.Fatal(err)
+					fatalInstall("create cluster", "InstallFailed", err)
 				}
 				timer.StopTimer(timer.TotalTimeElapsed)
 				timer.LogSummary()
+				exportMetrics("create cluster", gatherer.Success, "")
 			},
 		},
 		assets: targetassets.Cluster,
 	}
 
-	targets = []target{installConfigTarget, manifestsTarget, ignitionConfigsTarget, clusterTarget}
+	targets = []target{installConfigTarget, signingRequestsTarget, manifestsTarget, ignitionConfigsTarget, clusterTarget}
 )
 
 func newCreateCmd() *cobra.Command {
@@ -158,19 +189,21 @@ func newCreateCmd() *cobra.Command {
 		t.command.Run = runTargetCmd(t.assets...)
 		cmd.AddCommand(t.command)
 	}
+	clusterTarget.command.Flags().DurationVar(&createOpts.infrastructureTimeout, "infrastructure-timeout", 0, "Maximum time to spend creating infrastructure resources before stopping Terraform (0 means no limit)")
 
 	return cmd
 }
 
 func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args []string) {
-	runner := func(directory string) error {
+	runner := func(ctx context.Context, directory string) error {
 		assetStore, err := assetstore.NewStore(directory)
 		if err != nil {
 			return errors.Wrap(err, "failed to create asset store")
 		}
+		defer recordPlatform(assetStore)
 
 		for _, a := range targets {
-			err := assetStore.Fetch(a, targets...)
+			err := assetStore.Fetch(ctx, a, targets...)
 			if err != nil {
 				err = errors.Wrapf(err, "failed to fetch %s", a.Name())
 			}
@@ -190,24 +223,47 @@ func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args
 		}
 		return nil
 	}
-
 	return func(cmd *cobra.Command, args []string) {
 		timer.StartTimer(timer.TotalTimeElapsed)
+		command := "create " + cmd.Name()
+		trackMetrics(command)
 
 		cleanup := setupFileHook(rootOpts.dir)
 		defer cleanup()
 
-		err := runner(rootOpts.dir)
+		ctx, cancel := interruptContext(context.Background())
+		defer cancel()
+		if cmd.Name() == "cluster" {
+			ctx = cluster.WithInfrastructureTimeout(ctx, createOpts.infrastructureTimeout)
+		}
+
+		err := runner(ctx, rootOpts.dir)
 		if err != nil {
-			logrus.Fatal(err)
+			if terraform.IsCancelled(err) {
+				logrus.Error("Infrastructure creation was stopped before it completed. The Terraform state was saved, use 'destroy cluster' to remove any resources that were created.")
+			}
+			fatalInstall(command, "CreateFailed", err)
 		}
 		if cmd.Name() != "cluster" {
 			logrus.Infof(logging.LogCreatedFiles(cmd.Name(), rootOpts.dir, targets))
+			exportMetrics(command, gatherer.Success, "")
 		}
 
 	}
 }
 
+// recordPlatform records the platform of the install config for the install
+// metrics, if the install config was loaded or generated.
+func recordPlatform(assetStore asset.Store) {
+	a, err := assetStore.Load(&installconfig.InstallConfig{})
+	if err != nil || a == nil {
+		return
+	}
+	if config := a.(*installconfig.InstallConfig).Config; config != nil {
+		gatherer.SetPlatform(config.Platform.Name())
+	}
+}
+
 // addRouterCAToClusterCA adds router CA to cluster CA in kubeconfig
 func addRouterCAToClusterCA(ctx context.Context, config *rest.Config, directory string) (err error) {
 	client, err := kubernetes.NewForConfig(config)
@@ -479,12 +535,17 @@ func logComplete(directory, consoleURL string) error {
 	kubeconfig := filepath.Join(absDir, "auth", "kubeconfig")
 	pwFile := filepath.Join(absDir, "auth", "kubeadmin-password")
 	pw, err := ioutil.ReadFile(pwFile)
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 	logrus.Info("Install complete!")
 	logrus.Infof("To access the cluster as the system:admin user when using 'oc', run 'export KUBECONFIG=%s'", kubeconfig)
 	logrus.Infof("Access the OpenShift web-console here: %s", consoleURL)
+	if err != nil {
+		// The cluster has no kubeadmin user with authentication in the install config.
+		logrus.Info("Login to the console with the identity providers of the install config")
+		return nil
+	}
 	logrus.Infof("Login to the console with user: %q, and password: %q", "kubeadmin", pw)
 	return nil
 }
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -19,6 +21,7 @@ import (
 	_ "github.com/openshift/installer/pkg/destroy/libvirt"
 	_ "github.com/openshift/installer/pkg/destroy/openstack"
 	_ "github.com/openshift/installer/pkg/destroy/ovirt"
+	"github.com/openshift/installer/pkg/destroy/providers"
 	_ "github.com/openshift/installer/pkg/destroy/vsphere"
 	timer "github.com/openshift/installer/pkg/metrics/timer"
 	"github.com/openshift/installer/pkg/terraform"
@@ -35,35 +38,170 @@ func newDestroyCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newDestroyBootstrapCmd())
 	cmd.AddCommand(newDestroyClusterCmd())
+	cmd.AddCommand(newDestroyOrphansCmd())
 	return cmd
 }
 
+var (
+	destroyClusterOpts struct {
+		dryRun   bool
+		output   string
+		metadata destroy.MetadataOptions
+		run      runOptions
+	}
+)
+
+// runOptions holds the flags that bound a destroy run.
+type runOptions struct {
+	timeout     time.Duration
+	concurrency int
+	exclusions  []string
+}
+
+func (o *runOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 0, "Stop destroying after this duration and report the resources left behind, e.g. 30m (0 waits indefinitely)")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", 0, "Maximum number of resources deleted in parallel (0 selects the platform default)")
+	cmd.Flags().StringArrayVar(&o.exclusions, "exclude", []string{}, "Resource to leave in place, by ID, ARN, name pattern with * and ? wildcards, or tag:key[=value]")
+}
+
+// context returns a context that is cancelled on interrupt and, when a
+// timeout was given, once it expires.
+func (o *runOptions) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := interruptContext(context.Background())
+	if o.timeout <= 0 {
+		return ctx, cancel
+	}
+	ctx, cancelTimeout := context.WithTimeout(ctx, o.timeout)
+	return ctx, func() {
+		cancelTimeout()
+		cancel()
+	}
+}
+
+func (o *runOptions) providerOptions() (providers.RunOptions, error) {
+	exclusions, err := providers.ParseExclusions(o.exclusions)
+	if err != nil {
+		return providers.RunOptions{}, err
+	}
+	return providers.RunOptions{Concurrency: o.concurrency, Exclusions: exclusions}, nil
+}
+
 func newDestroyClusterCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "cluster",
 		Short: "Destroy an OpenShift cluster",
-		Args:  cobra.ExactArgs(0),
+		Long: `Destroy an OpenShift cluster.
+
+The cluster is described by the metadata.json in the asset directory. When
+that file has been lost, pass --platform, --infra-id and the platform's
+location flags instead, for example:
+
+  openshift-install destroy cluster --platform=aws --region=us-east-1 --infra-id=mycluster-x7k2p`,
+		Args: cobra.ExactArgs(0),
 		Run: func(_ *cobra.Command, _ []string) {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
-			err := runDestroyCmd(rootOpts.dir)
+			var err error
+			if destroyClusterOpts.dryRun {
+				err = runDestroyInventoryCmd(rootOpts.dir, destroyClusterOpts.output)
+			} else {
+				err = runDestroyCmd(rootOpts.dir)
+			}
 			if err != nil {
 				logrus.Fatal(err)
 			}
 		},
 	}
+	cmd.Flags().BoolVar(&destroyClusterOpts.dryRun, "dry-run", false, "List the resources that would be destroyed without deleting them")
+	cmd.Flags().StringVarP(&destroyClusterOpts.output, "output", "o", destroy.InventoryFormatText, "Output format for --dry-run (text or json)")
+	destroyClusterOpts.run.addFlags(cmd)
+
+	metadata := &destroyClusterOpts.metadata
+	cmd.Flags().StringVar(&metadata.Platform, "platform", "", "Platform of the cluster, used instead of metadata.json")
+	cmd.Flags().StringVar(&metadata.InfraID, "infra-id", "", "Infra ID of the cluster, used to find its resources")
+	cmd.Flags().StringVar(&metadata.ClusterID, "cluster-id", "", "Cluster ID of the cluster (AWS)")
+	cmd.Flags().StringVar(&metadata.Region, "region", "", "Region of the cluster (AWS, Azure, GCP)")
+	cmd.Flags().StringVar(&metadata.Cloud, "cloud", "", "Cloud name from clouds.yaml (OpenStack) or cloud environment (Azure)")
+	cmd.Flags().StringVar(&metadata.ProjectID, "project-id", "", "Project of the cluster (GCP)")
+	cmd.Flags().StringVar(&metadata.ResourceGroup, "resource-group", "", "Resource group of the cluster, defaults to <infra-id>-rg (Azure)")
+	cmd.Flags().StringVar(&metadata.URI, "uri", "", "Libvirt connection URI (libvirt, bare metal)")
+	cmd.Flags().StringVar(&metadata.VCenter, "vcenter", "", "vCenter of the cluster (vSphere)")
+	cmd.Flags().StringVar(&metadata.Username, "username", "", "vCenter user name (vSphere)")
+	cmd.Flags().StringVar(&metadata.PasswordFile, "password-file", "", "File holding the vCenter password, read from "+destroy.VSpherePasswordEnvVar+" when not given (vSphere)")
+	cmd.Flags().StringVar(&metadata.Namespace, "namespace", "", "Namespace of the cluster in the infra cluster (KubeVirt)")
+	cmd.Flags().StringArrayVar(&metadata.Filters, "filter", []string{}, "Additional key=value tag that the cluster resources must also carry (AWS, OpenStack)")
+	return cmd
+}
+
+// newClusterDestroyer returns a destroyer for the cluster described by the
+// metadata flags, or by metadata.json in directory when no platform flag was
+// given. The second return is true when metadata.json was used.
+func newClusterDestroyer(directory string) (providers.Destroyer, bool, error) {
+	logger := logrus.StandardLogger()
+	if destroyClusterOpts.metadata.Platform == "" {
+		destroyer, err := destroy.New(logger, directory)
+		return destroyer, true, err
+	}
+	metadata, err := destroyClusterOpts.metadata.Metadata()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "invalid cluster flags")
+	}
+	destroyer, err := destroy.NewFromMetadata(logger, metadata)
+	return destroyer, false, err
+}
+
+func runDestroyInventoryCmd(directory string, format string) error {
+	destroyer, _, err := newClusterDestroyer(directory)
+	if err != nil {
+		return errors.Wrap(err, "Failed while preparing to destroy cluster")
+	}
+	options, err := destroyClusterOpts.run.providerOptions()
+	if err != nil {
+		return err
+	}
+	resources, err := destroyer.Inventory()
+	if err != nil {
+		return errors.Wrap(err, "Failed to list cluster resources")
+	}
+	resources = destroy.FilterInventory(resources, options.Exclusions)
+	logrus.Infof("Found %d resources that would be destroyed", len(resources))
+	return destroy.WriteInventory(os.Stdout, resources, format)
 }
 
 func runDestroyCmd(directory string) error {
 	timer.StartTimer(timer.TotalTimeElapsed)
-	destroyer, err := destroy.New(logrus.StandardLogger(), directory)
+	options, err := destroyClusterOpts.run.providerOptions()
+	if err != nil {
+		return err
+	}
+	destroyer, fromAssets, err := newClusterDestroyer(directory)
 	if err != nil {
 		return errors.Wrap(err, "Failed while preparing to destroy cluster")
 	}
-	if err := destroyer.Run(); err != nil {
+	ctx, cancel := destroyClusterOpts.run.context()
+	defer cancel()
+	report, err := destroyer.Run(ctx, options)
+	if report != nil {
+		destroy.LogReport(logrus.StandardLogger(), report)
+		if err := destroy.WriteReport(directory, report); err != nil {
+			logrus.Error(err)
+		}
+	}
+	if err != nil {
 		return errors.Wrap(err, "Failed to destroy cluster")
 	}
+	// Keep metadata.json so that destroy can be run again for the leftovers.
+	if report != nil && report.Leftovers() {
+		return errors.Errorf("Failed to destroy cluster: %d resources were left behind, see %s", len(report.Failed), filepath.Join(directory, destroy.ReportFileName))
+	}
+
+	// The asset directory does not belong to a cluster destroyed from flags.
+	if !fromAssets {
+		timer.StopTimer(timer.TotalTimeElapsed)
+		timer.LogSummary()
+		return nil
+	}
 
 	store, err := assetstore.NewStore(directory)
 	if err != nil {
@@ -91,6 +229,84 @@ func runDestroyCmd(directory string) error {
 	return nil
 }
 
+var (
+	destroyOrphansOpts struct {
+		platform string
+		scope    providers.Scope
+		options  destroy.OrphanOptions
+		dryRun   bool
+		output   string
+		run      runOptions
+	}
+)
+
+func newDestroyOrphansCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "orphans",
+		Short: "Destroy clusters left behind in an account",
+		Long: `Destroy clusters left behind in an account.
+
+Lists the clusters whose installer-created resources are found in the given
+region, project or cloud, with their age and an estimate of their resource
+count. A cluster is destroyed when it is older than --older-than or, if
+--allow is given, when its infra ID is not listed. Clusters of unknown age
+are never considered older than --older-than. For example:
+
+  openshift-install destroy orphans --platform=aws --region=us-east-1 --older-than=6h`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runDestroyOrphansCmd(); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&destroyOrphansOpts.platform, "platform", "", "Platform to search (aws, azure, gcp or openstack)")
+	cmd.Flags().StringVar(&destroyOrphansOpts.scope.Region, "region", "", "Region to search (AWS, Azure, GCP)")
+	cmd.Flags().StringVar(&destroyOrphansOpts.scope.Cloud, "cloud", "", "Cloud name from clouds.yaml (OpenStack) or cloud environment (Azure)")
+	cmd.Flags().StringVar(&destroyOrphansOpts.scope.ProjectID, "project-id", "", "Project to search (GCP)")
+	cmd.Flags().DurationVar(&destroyOrphansOpts.options.OlderThan, "older-than", 0, "Destroy clusters older than this age, e.g. 6h")
+	cmd.Flags().StringSliceVar(&destroyOrphansOpts.options.Allowed, "allow", []string{}, "Infra ID of a cluster in use; when given, unlisted clusters are destroyed")
+	cmd.Flags().BoolVar(&destroyOrphansOpts.dryRun, "dry-run", false, "Report the clusters that would be destroyed without deleting them")
+	cmd.Flags().StringVarP(&destroyOrphansOpts.output, "output", "o", destroy.InventoryFormatText, "Output format for the report (text or json)")
+	destroyOrphansOpts.run.addFlags(cmd)
+	return cmd
+}
+
+func runDestroyOrphansCmd() error {
+	if destroyOrphansOpts.platform == "" {
+		return errors.New("--platform is required")
+	}
+	options, err := destroyOrphansOpts.run.providerOptions()
+	if err != nil {
+		return err
+	}
+	logger := logrus.StandardLogger()
+	orphans, err := destroy.FindOrphans(logger, destroyOrphansOpts.platform, &destroyOrphansOpts.scope)
+	if err != nil {
+		return errors.Wrap(err, "Failed to search for orphaned clusters")
+	}
+	report := destroyOrphansOpts.options.Report(orphans, time.Now())
+	if err := destroy.WriteOrphanReport(os.Stdout, report, destroyOrphansOpts.output); err != nil {
+		return err
+	}
+	if destroyOrphansOpts.dryRun {
+		return nil
+	}
+
+	ctx, cancel := destroyOrphansOpts.run.context()
+	defer cancel()
+	timer.StartTimer(timer.TotalTimeElapsed)
+	if err := destroy.DestroyOrphans(ctx, logger, report, options); err != nil {
+		return errors.Wrap(err, "Failed to destroy orphaned clusters")
+	}
+	timer.StopTimer(timer.TotalTimeElapsed)
+	timer.LogSummary()
+	return nil
+}
+
 func newDestroyBootstrapCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "bootstrap",
@@ -100,8 +316,11 @@ func newDestroyBootstrapCmd() *cobra.Command {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
 
+			ctx, cancel := interruptContext(context.Background())
+			defer cancel()
+
 			timer.StartTimer(timer.TotalTimeElapsed)
-			err := bootstrap.Destroy(rootOpts.dir)
+			err := bootstrap.Destroy(ctx, rootOpts.dir)
 			if err != nil {
 				logrus.Fatal(err)
 			}
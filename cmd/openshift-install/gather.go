@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -17,18 +18,27 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	cryptossh "golang.org/x/crypto/ssh"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/openshift/installer/data"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	assetstore "github.com/openshift/installer/pkg/asset/store"
 	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/gather"
+	gatheraws "github.com/openshift/installer/pkg/gather/aws"
+	gathercluster "github.com/openshift/installer/pkg/gather/cluster"
+	gathergcp "github.com/openshift/installer/pkg/gather/gcp"
 	"github.com/openshift/installer/pkg/gather/ssh"
 	"github.com/openshift/installer/pkg/terraform"
-	gatheraws "github.com/openshift/installer/pkg/terraform/gather/aws"
+	tfgatheraws "github.com/openshift/installer/pkg/terraform/gather/aws"
 	gatherazure "github.com/openshift/installer/pkg/terraform/gather/azure"
 	gatherbaremetal "github.com/openshift/installer/pkg/terraform/gather/baremetal"
-	gathergcp "github.com/openshift/installer/pkg/terraform/gather/gcp"
+	tfgathergcp "github.com/openshift/installer/pkg/terraform/gather/gcp"
 	gatherkubevirt "github.com/openshift/installer/pkg/terraform/gather/kubevirt"
 	gatherlibvirt "github.com/openshift/installer/pkg/terraform/gather/libvirt"
 	gatheropenstack "github.com/openshift/installer/pkg/terraform/gather/openstack"
@@ -60,6 +70,7 @@ to debug the installation failures`,
 		},
 	}
 	cmd.AddCommand(newGatherBootstrapCmd())
+	cmd.AddCommand(newGatherClusterCmd())
 	return cmd
 }
 
@@ -67,7 +78,12 @@ var (
 	gatherBootstrapOpts struct {
 		bootstrap string
 		masters   []string
+		hostsFile string
 		sshKeys   []string
+
+		bastion     string
+		knownHosts  string
+		hostTimeout time.Duration
 	}
 )
 
@@ -75,7 +91,18 @@ func newGatherBootstrapCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "bootstrap",
 		Short: "Gather debugging data for a failing-to-bootstrap control plane",
-		Args:  cobra.ExactArgs(0),
+		Long: `Gather debugging data for a failing-to-bootstrap control plane.
+
+The bootstrap and control plane addresses are taken, in this order, from the
+--bootstrap and --master flags, the --hosts file, the Terraform state, the
+platform API for the cluster in metadata.json (AWS and GCP) and the control
+plane nodes known to the API server. When the bootstrap host cannot be
+reached at one address, the next one found is tried.
+
+The control plane hosts are gathered in parallel, through the bootstrap host
+unless --bastion is given, and the hosts that could not be gathered are
+reported.`,
+		Args: cobra.ExactArgs(0),
 		Run: func(_ *cobra.Command, _ []string) {
 			cleanup := setupFileHook(rootOpts.dir)
 			defer cleanup()
@@ -87,7 +114,11 @@ func newGatherBootstrapCmd() *cobra.Command {
 	}
 	cmd.PersistentFlags().StringVar(&gatherBootstrapOpts.bootstrap, "bootstrap", "", "Hostname or IP of the bootstrap host")
 	cmd.PersistentFlags().StringArrayVar(&gatherBootstrapOpts.masters, "master", []string{}, "Hostnames or IPs of all control plane hosts")
+	cmd.PersistentFlags().StringVar(&gatherBootstrapOpts.hostsFile, "hosts", "", "Path to a YAML file listing the bootstrap host and the control plane hosts under 'bootstrap' and 'masters'")
 	cmd.PersistentFlags().StringArrayVar(&gatherBootstrapOpts.sshKeys, "key", []string{}, "Path to SSH private keys that should be used for authentication. If no key was provided, SSH private keys from user's environment will be used")
+	cmd.PersistentFlags().StringVar(&gatherBootstrapOpts.bastion, "bastion", "", "Jump host used to reach the control plane hosts, as host[:port] (defaults to the bootstrap host)")
+	cmd.PersistentFlags().StringVar(&gatherBootstrapOpts.knownHosts, "known-hosts", "", "Path to a known_hosts file used to verify the host keys (host keys are not verified by default)")
+	cmd.PersistentFlags().DurationVar(&gatherBootstrapOpts.hostTimeout, "host-timeout", 10*time.Minute, "Maximum time spent gathering each control plane host")
 	return cmd
 }
 
@@ -98,7 +129,7 @@ func runGatherBootstrapCmd(directory string) error {
 	}
 	// add the default bootstrap key pair to the sshKeys list
 	bootstrapSSHKeyPair := &tls.BootstrapSSHKeyPair{}
-	if err := assetStore.Fetch(bootstrapSSHKeyPair); err != nil {
+	if err := assetStore.Fetch(context.TODO(), bootstrapSSHKeyPair); err != nil {
 		return errors.Wrapf(err, "failed to fetch %s", bootstrapSSHKeyPair.Name())
 	}
 	tmpfile, err := ioutil.TempFile("", "bootstrap-ssh")
@@ -114,59 +145,313 @@ func runGatherBootstrapCmd(directory string) error {
 	}
 	gatherBootstrapOpts.sshKeys = append(gatherBootstrapOpts.sshKeys, tmpfile.Name())
 
+	discovery, err := gather.Discover(context.TODO(), logrus.StandardLogger(), gatherSources(directory, assetStore))
+	if err != nil {
+		return errors.Wrap(err, "failed to find the bootstrap and control plane hosts, pass them with --bootstrap and --master or --hosts")
+	}
+	if len(discovery.Bootstraps) == 0 {
+		return errors.New("failed to find the bootstrap host, pass it with --bootstrap or --hosts")
+	}
+	if len(discovery.Masters) == 0 {
+		logrus.Warn("No control plane host found, only the bootstrap host will be gathered")
+	}
+
+	return logGatherBootstrap(discovery.Bootstraps, gatherSSHPort, discovery.Masters, directory)
+}
+
+// gatherSSHPort is the SSH port of the cluster hosts.
+const gatherSSHPort = 22
+
+// gatherSources returns the sources of host addresses available for the
+// cluster in directory, most trusted first.
+func gatherSources(directory string, assetStore asset.Store) []gather.Source {
+	var sources []gather.Source
+	if gatherBootstrapOpts.bootstrap != "" || len(gatherBootstrapOpts.masters) > 0 {
+		sources = append(sources, gather.StaticSource("the command line", gatherBootstrapOpts.bootstrap, gatherBootstrapOpts.masters))
+	}
+	if gatherBootstrapOpts.hostsFile != "" {
+		sources = append(sources, gather.FileSource(gatherBootstrapOpts.hostsFile))
+	}
+
 	tfStateFilePath := filepath.Join(directory, terraform.StateFileName)
-	_, err = os.Stat(tfStateFilePath)
-	if os.IsNotExist(err) {
-		return unSupportedPlatformGather(directory)
+	if _, err := os.Stat(tfStateFilePath); err == nil {
+		sources = append(sources, gather.Source{
+			Name: tfStateFilePath,
+			Hosts: func(context.Context) (*gather.Hosts, error) {
+				return terraformHosts(assetStore, tfStateFilePath)
+			},
+		})
 	}
-	if err != nil {
-		return err
+
+	if metadata, err := cluster.LoadMetadata(directory); err == nil {
+		switch metadata.Platform() {
+		case awstypes.Name:
+			sources = append(sources, gatheraws.Source(metadata))
+		case gcptypes.Name:
+			sources = append(sources, gathergcp.Source(metadata))
+		}
+	} else if !os.IsNotExist(errors.Cause(err)) {
+		logrus.WithError(err).Warn("Failed to load the cluster metadata")
 	}
 
+	kubeconfig := filepath.Join(directory, "auth", "kubeconfig")
+	if _, err := os.Stat(kubeconfig); err == nil {
+		sources = append(sources, gather.KubeconfigSource(kubeconfig))
+	}
+	return sources
+}
+
+// terraformHosts reads the host addresses from the Terraform state.
+func terraformHosts(assetStore asset.Store, tfStateFilePath string) (*gather.Hosts, error) {
 	config := &installconfig.InstallConfig{}
-	if err := assetStore.Fetch(config); err != nil {
-		return errors.Wrapf(err, "failed to fetch %s", config.Name())
+	if err := assetStore.Fetch(context.TODO(), config); err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch %s", config.Name())
 	}
 
 	tfstate, err := terraform.ReadState(tfStateFilePath)
 	if err != nil {
-		return errors.Wrapf(err, "failed to read state from %q", tfStateFilePath)
+		return nil, errors.Wrapf(err, "failed to read state from %q", tfStateFilePath)
 	}
-	bootstrap, port, masters, err := extractHostAddresses(config.Config, tfstate)
+	bootstrap, _, masters, err := extractHostAddresses(config.Config, tfstate)
 	if err != nil {
-		if err2, ok := err.(errUnSupportedGatherPlatform); ok {
-			logrus.Error(err2)
-			return unSupportedPlatformGather(directory)
-		}
-		return errors.Wrapf(err, "failed to get bootstrap and control plane host addresses from %q", tfStateFilePath)
+		return nil, err
+	}
+	return &gather.Hosts{Bootstrap: bootstrap, Masters: masters}, nil
+}
+
+// gatherClientOptions returns the SSH options for the cluster hosts.
+func gatherClientOptions() ssh.ClientOptions {
+	return ssh.ClientOptions{
+		User:       "core",
+		Keys:       gatherBootstrapOpts.sshKeys,
+		KnownHosts: gatherBootstrapOpts.knownHosts,
 	}
+}
 
-	return logGatherBootstrap(bootstrap, port, masters, directory)
+// connectBootstrap connects to the first reachable address of the
+// bootstrap host and returns the address used.
+func connectBootstrap(bootstraps []string, port int) (*cryptossh.Client, string, error) {
+	options := gatherClientOptions()
+	var err error
+	for _, bootstrap := range bootstraps {
+		address := net.JoinHostPort(bootstrap, strconv.Itoa(port))
+		var client *cryptossh.Client
+		client, err = ssh.NewClientWithOptions(address, &options)
+		if err == nil {
+			return client, address, nil
+		}
+		logrus.WithError(err).Warnf("Failed to connect to the bootstrap host at %s", bootstrap)
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return nil, "", errors.Wrap(err, "failed to connect to the bootstrap machine")
+	}
+	return nil, "", errors.Wrap(err, "failed to create SSH client")
 }
 
-func logGatherBootstrap(bootstrap string, port int, masters []string, directory string) error {
+func logGatherBootstrap(bootstraps []string, port int, masters []string, directory string) error {
+	redactor, err := newRedactor()
+	if err != nil {
+		return err
+	}
+
 	logrus.Info("Pulling debug logs from the bootstrap machine")
-	client, err := ssh.NewClient("core", net.JoinHostPort(bootstrap, strconv.Itoa(port)), gatherBootstrapOpts.sshKeys)
+	client, bootstrap, err := connectBootstrap(bootstraps, port)
 	if err != nil {
-		if errors.Is(err, syscall.ECONNREFUSED) {
-			return errors.Wrap(err, "failed to connect to the bootstrap machine")
-		}
-		return errors.Wrap(err, "failed to create SSH client")
+		return err
 	}
 
+	// The control plane hosts are gathered by the installer when it knows
+	// them, otherwise the bootstrap host looks them up in the cluster. The
+	// scripts of older bootstrap hosts take the control plane hosts as
+	// arguments and gather them themselves.
 	gatherID := time.Now().Format("20060102150405")
-	if err := ssh.Run(client, fmt.Sprintf("/usr/local/bin/installer-gather.sh --id %s %s", gatherID, strings.Join(masters, " "))); err != nil {
+	command := fmt.Sprintf("/usr/local/bin/installer-gather.sh --id %s", gatherID)
+	if len(masters) > 0 {
+		if skipMastersSupported(client) {
+			command += " --skip-masters"
+		} else {
+			logrus.Debug("The gather script of the bootstrap host does not support --skip-masters, it gathers the control plane hosts")
+			command += " " + strings.Join(masters, " ")
+			masters = nil
+		}
+	}
+	if err := ssh.Run(client, command); err != nil {
 		return errors.Wrap(err, "failed to run remote command")
 	}
-	file := filepath.Join(directory, fmt.Sprintf("log-bundle-%s.tar.gz", gatherID))
-	if err := ssh.PullFileTo(client, fmt.Sprintf("/home/core/log-bundle-%s.tar.gz", gatherID), file); err != nil {
+
+	// The bundle is assembled in a temporary directory and only written to
+	// the asset directory once its secrets are redacted.
+	tmpDir, err := ioutil.TempDir("", "log-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	name := fmt.Sprintf("log-bundle-%s.tar.gz", gatherID)
+	rawFile := filepath.Join(tmpDir, name)
+	if err := ssh.PullFileTo(client, path.Join("/home/core", name), rawFile); err != nil {
 		return errors.Wrap(err, "failed to pull log file from remote")
 	}
+	client.Close()
+
+	if len(masters) > 0 {
+		bastion := gatherBootstrapOpts.bastion
+		if bastion == "" {
+			bastion = bootstrap
+		} else if _, _, err := net.SplitHostPort(bastion); err != nil {
+			bastion = net.JoinHostPort(bastion, strconv.Itoa(port))
+		}
+		if err := gatherControlPlane(rawFile, gatherID, masters, bastion, port); err != nil {
+			logrus.WithError(err).Error("Failed to gather the control plane hosts")
+		}
+	}
+
+	file := filepath.Join(directory, name)
+	if err := gather.RedactBundle(rawFile, file, redactor); err != nil {
+		return errors.Wrap(err, "failed to redact log bundle")
+	}
+	absPath, err := filepath.Abs(file)
+	if err != nil {
+		return errors.Wrap(err, "failed to stat log file")
+	}
+	logrus.Infof("Bootstrap gather logs captured here %q", absPath)
+	logBundleFindings(absPath)
+	return nil
+}
+
+// skipMastersSupported returns whether the gather script of the bootstrap
+// host supports --skip-masters.
+func skipMastersSupported(client *cryptossh.Client) bool {
+	return ssh.Run(client, "grep -q -e --skip-masters /usr/local/bin/installer-gather.sh") == nil
+}
+
+// gatherControlPlane pulls the logs of the control plane hosts in parallel
+// through bastion and adds them to the log bundle at file.
+func gatherControlPlane(file string, gatherID string, masters []string, bastion string, port int) error {
+	logrus.Infof("Pulling debug logs from %d control plane hosts", len(masters))
+	script, err := bootstrapFile("installer-masters-gather.sh")
+	if err != nil {
+		return err
+	}
+	dir, err := ioutil.TempDir("", "control-plane")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	options := gatherClientOptions()
+	options.Bastion = bastion
+	gatherer := &gather.ControlPlaneGatherer{
+		Logger: logrus.StandardLogger(),
+		Runner: &ssh.Runner{
+			ClientOptions: options,
+			Port:          port,
+			HostTimeout:   gatherBootstrapOpts.hostTimeout,
+		},
+		Script: script,
+	}
+	results := gatherer.Gather(context.TODO(), masters, gatherID, dir)
+
+	additions := map[string]string{}
+	for _, result := range results {
+		if result.Err == nil {
+			additions[path.Join("control-plane", result.Host)] = gather.HostBundlePath(dir, result.Host)
+		}
+	}
+	logrus.Infof("Gathered logs from %d of %d control plane hosts", len(additions), len(results))
+	if len(additions) == 0 {
+		return nil
+	}
+	return gather.AddToBundle(file, additions)
+}
+
+// bootstrapFile returns the content of a script installed on the bootstrap
+// host.
+func bootstrapFile(name string) ([]byte, error) {
+	file, err := data.Assets.Open(path.Join("bootstrap/files/usr/local/bin", name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ioutil.ReadAll(file)
+}
+
+var (
+	gatherClusterOpts struct {
+		journals    bool
+		sshKeys     []string
+		hostTimeout time.Duration
+	}
+)
+
+func newGatherClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Gather debugging data for a cluster that failed to complete installation",
+		Long: `Gather debugging data for a cluster that failed to complete installation.
+
+Uses the admin kubeconfig in the asset directory to collect the cluster version,
+cluster operators, machine config pools, machines, machine sets, nodes, events
+and the logs of failing pods, and pulls the kubelet and CRI-O journals from
+the nodes over SSH, a few at a time. The gather run when 'create cluster'
+fails skips the journals.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			ctx, cancel := interruptContext(context.Background())
+			defer cancel()
+			if err := runGatherClusterCmd(ctx, rootOpts.dir, gatherClusterOpts.journals); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.PersistentFlags().BoolVar(&gatherClusterOpts.journals, "journals", true, "Pull the node journals over SSH")
+	cmd.PersistentFlags().DurationVar(&gatherClusterOpts.hostTimeout, "host-timeout", 2*time.Minute, "Maximum time spent pulling the journals of each node")
+	cmd.PersistentFlags().StringArrayVar(&gatherClusterOpts.sshKeys, "key", []string{}, "Path to SSH private keys that should be used for authentication. If no key was provided, SSH private keys from user's environment will be used")
+	return cmd
+}
+
+// runGatherClusterCmd gathers the cluster state into a log bundle, with the
+// node journals when journals is set.
+func runGatherClusterCmd(ctx context.Context, directory string, journals bool) error {
+	redactor, err := newRedactor()
+	if err != nil {
+		return err
+	}
+	config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(directory, "auth", "kubeconfig"))
+	if err != nil {
+		return errors.Wrap(err, "loading kubeconfig")
+	}
+
+	logrus.Info("Pulling debug data from the cluster")
+	gatherID := time.Now().Format("20060102150405")
+	file := filepath.Join(directory, fmt.Sprintf("cluster-bundle-%s.tar.gz", gatherID))
+	gatherer := &gathercluster.Gatherer{
+		Logger:   logrus.StandardLogger(),
+		Config:   config,
+		Redactor: redactor,
+	}
+	if journals {
+		gatherer.Runner = &ssh.Runner{
+			ClientOptions: ssh.ClientOptions{User: "core", Keys: gatherClusterOpts.sshKeys},
+			Port:          22,
+			HostTimeout:   gatherClusterOpts.hostTimeout,
+		}
+	}
+	gatherErr := gatherer.Gather(ctx, file, gatherID)
+	if _, err := os.Stat(file); err != nil {
+		return errors.Wrap(gatherErr, "failed to gather cluster data")
+	}
+	if gatherErr != nil {
+		logrus.WithError(gatherErr).Warn("Some cluster data could not be gathered")
+	}
 	path, err := filepath.Abs(file)
 	if err != nil {
 		return errors.Wrap(err, "failed to stat log file")
 	}
-	logrus.Infof("Bootstrap gather logs captured here %q", path)
+	logrus.Infof("Cluster gather logs captured here %q", path)
+	logBundleFindings(path)
 	return nil
 }
 
@@ -174,11 +459,11 @@ func extractHostAddresses(config *types.InstallConfig, tfstate *terraform.State)
 	port = 22
 	switch config.Platform.Name() {
 	case awstypes.Name:
-		bootstrap, err = gatheraws.BootstrapIP(tfstate)
+		bootstrap, err = tfgatheraws.BootstrapIP(tfstate)
 		if err != nil {
 			return bootstrap, port, masters, err
 		}
-		masters, err = gatheraws.ControlPlaneIPs(tfstate)
+		masters, err = tfgatheraws.ControlPlaneIPs(tfstate)
 		if err != nil {
 			logrus.Error(err)
 		}
@@ -198,11 +483,11 @@ func extractHostAddresses(config *types.InstallConfig, tfstate *terraform.State)
 			return bootstrap, port, masters, err
 		}
 	case gcptypes.Name:
-		bootstrap, err = gathergcp.BootstrapIP(tfstate)
+		bootstrap, err = tfgathergcp.BootstrapIP(tfstate)
 		if err != nil {
 			return bootstrap, port, masters, err
 		}
-		masters, err = gathergcp.ControlPlaneIPs(tfstate)
+		masters, err = tfgathergcp.ControlPlaneIPs(tfstate)
 		if err != nil {
 			logrus.Error(err)
 		}
@@ -259,14 +544,6 @@ func (e errUnSupportedGatherPlatform) Error() string {
 	return e.Message
 }
 
-func unSupportedPlatformGather(directory string) error {
-	if gatherBootstrapOpts.bootstrap == "" || len(gatherBootstrapOpts.masters) == 0 {
-		return errors.New("bootstrap host address and at least one control plane host address must be provided")
-	}
-
-	return logGatherBootstrap(gatherBootstrapOpts.bootstrap, 22, gatherBootstrapOpts.masters, directory)
-}
-
 func logClusterOperatorConditions(ctx context.Context, config *rest.Config) error {
 	client, err := configclient.NewForConfig(config)
 	if err != nil {
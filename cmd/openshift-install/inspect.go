@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/inspect"
+)
+
+func newInspectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect the assets of the asset directory",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(newInspectCertificatesCmd())
+	return cmd
+}
+
+var (
+	inspectCertificatesOpts struct {
+		output         string
+		expiringWithin time.Duration
+	}
+)
+
+func newInspectCertificatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certificates",
+		Short: "List the certificates of the asset directory",
+		Long: `List the certificates of the asset directory.
+
+The certificates of the state file, of the tls directory and embedded in the
+ignition configs are printed with their subject, issuer, subject alternative
+names, key type and validity. Each certificate is verified against the
+certificate authorities found with it, and the certificates expiring within
+--expiring-within, such as the certificates of the bootstrap valid for a day,
+are flagged as Expiring.`,
+		Args: cobra.ExactArgs(0),
+		Run: func(_ *cobra.Command, _ []string) {
+			cleanup := setupFileHook(rootOpts.dir)
+			defer cleanup()
+
+			if err := runInspectCertificatesCmd(os.Stdout, rootOpts.dir, time.Now()); err != nil {
+				logrus.Fatal(err)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&inspectCertificatesOpts.output, "output", "o", inspect.FormatText, "Output format (text or json)")
+	cmd.Flags().DurationVar(&inspectCertificatesOpts.expiringWithin, "expiring-within", 30*24*time.Hour, "Flag the certificates expiring within this duration")
+	return cmd
+}
+
+func runInspectCertificatesCmd(w io.Writer, directory string, now time.Time) error {
+	certs, err := inspect.Certificates(directory, now, inspectCertificatesOpts.expiringWithin)
+	if err != nil {
+		return errors.Wrap(err, "failed to inspect certificates")
+	}
+	if len(certs) == 0 {
+		logrus.Infof("No certificates found in %s", directory)
+		return nil
+	}
+	if err := inspect.WriteCertificates(w, certs, inspectCertificatesOpts.output); err != nil {
+		return err
+	}
+
+	counts := map[inspect.Status]int{}
+	for _, c := range certs {
+		counts[c.Status]++
+	}
+	if n := counts[inspect.StatusExpired]; n > 0 {
+		logrus.Warnf("%d certificates expired", n)
+	}
+	if n := counts[inspect.StatusExpiring]; n > 0 {
+		logrus.Warnf("%d certificates expire within %s", n, inspectCertificatesOpts.expiringWithin)
+	}
+	return nil
+}
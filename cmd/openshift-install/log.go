@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/installer/pkg/redact"
 	"github.com/openshift/installer/pkg/version"
 )
 
@@ -18,6 +19,9 @@ type fileHook struct {
 	level     logrus.Level
 
 	truncateAtNewLine bool
+
+	// redactor replaces the secrets in the lines written, if set.
+	redactor *redact.Redactor
 }
 
 func newFileHook(file io.Writer, level logrus.Level, formatter logrus.Formatter) *fileHook {
@@ -51,9 +55,11 @@ func (h *fileHook) Fire(entry *logrus.Entry) error {
 	orig := entry.Message
 	defer func() { entry.Message = orig }()
 
-	msgs := []string{orig}
+	// Secrets may span several lines, so the message is redacted before it
+	// is split.
+	msgs := []string{h.redactor.String(orig)}
 	if h.truncateAtNewLine {
-		msgs = strings.Split(orig, "\n")
+		msgs = strings.Split(msgs[0], "\n")
 	}
 
 	for _, msg := range msgs {
@@ -64,6 +70,7 @@ func (h *fileHook) Fire(entry *logrus.Entry) error {
 		if err != nil {
 			return err
 		}
+		line = h.redactor.Bytes(line)
 
 		if _, err := h.file.Write(line); err != nil {
 			return err
@@ -73,7 +80,18 @@ func (h *fileHook) Fire(entry *logrus.Entry) error {
 	return nil
 }
 
+// newRedactor returns the redactor for the built-in patterns and the
+// --redact patterns.
+func newRedactor() (*redact.Redactor, error) {
+	return redact.New(rootOpts.redactPatterns)
+}
+
 func setupFileHook(baseDir string) func() {
+	redactor, err := newRedactor()
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		logrus.Fatal(errors.Wrap(err, "failed to create base directory for logs"))
 	}
@@ -87,12 +105,14 @@ func setupFileHook(baseDir string) func() {
 	for k, v := range logrus.StandardLogger().Hooks {
 		originalHooks[k] = v
 	}
-	logrus.AddHook(newFileHook(logfile, logrus.TraceLevel, &logrus.TextFormatter{
+	hook := newFileHook(logfile, logrus.TraceLevel, &logrus.TextFormatter{
 		DisableColors:          true,
 		DisableTimestamp:       false,
 		FullTimestamp:          true,
 		DisableLevelTruncation: false,
-	}))
+	})
+	hook.redactor = redactor
+	logrus.AddHook(hook)
 
 	versionString, err := version.String()
 	if err != nil {
@@ -19,8 +19,9 @@ import (
 
 var (
 	rootOpts struct {
-		dir      string
-		logLevel string
+		dir            string
+		logLevel       string
+		redactPatterns []string
 	}
 )
 
@@ -58,11 +59,14 @@ func installerMain() {
 		newDestroyCmd(),
 		newWaitForCmd(),
 		newGatherCmd(),
+		newAnalyzeCmd(),
 		newVersionCmd(),
 		newGraphCmd(),
 		newCompletionCmd(),
 		newMigrateCmd(),
 		newExplainCmd(),
+		newCheckCmd(),
+		newInspectCmd(),
 	} {
 		rootCmd.AddCommand(subCmd)
 	}
@@ -83,6 +87,8 @@ func newRootCmd() *cobra.Command {
 	}
 	cmd.PersistentFlags().StringVar(&rootOpts.dir, "dir", ".", "assets directory")
 	cmd.PersistentFlags().StringVar(&rootOpts.logLevel, "log-level", "info", "log level (e.g. \"debug | info | warn | error\")")
+	addMetricsFlags(cmd)
+	cmd.PersistentFlags().StringArrayVar(&rootOpts.redactPatterns, "redact", []string{}, "Regular expression matching secrets to remove from the log file and log bundles, in addition to the built-in patterns")
 	return cmd
 }
 
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/diagnostics"
+	"github.com/openshift/installer/pkg/metrics/gatherer"
+	timer "github.com/openshift/installer/pkg/metrics/timer"
+	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/types"
+)
+
+var (
+	metricsOpts struct {
+		pushgateway string
+		file        string
+	}
+
+	// metricsRun tracks the export of the metrics of the running command,
+	// which happens at most once.
+	metricsRun struct {
+		id       string
+		command  string
+		reason   string
+		exported bool
+	}
+)
+
+// addMetricsFlags adds the flags enabling the metrics export. Their defaults
+// are taken from the environment so that the export can be enabled for a
+// whole fleet of installs.
+func addMetricsFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&metricsOpts.pushgateway, "metrics-pushgateway", os.Getenv("OPENSHIFT_INSTALL_METRICS_PUSHGATEWAY"), "URL of a Prometheus Pushgateway the install metrics are pushed to")
+	cmd.PersistentFlags().StringVar(&metricsOpts.file, "metrics-file", os.Getenv("OPENSHIFT_INSTALL_METRICS_FILE"), "Path of a file the install metrics are written to in the OpenMetrics text format")
+}
+
+// trackMetrics registers an exit handler exporting the metrics of command as a
+// failure, so that the paths calling logrus.Fatal directly are exported too.
+func trackMetrics(command string) {
+	if metricsRun.command == "" {
+		logrus.RegisterExitHandler(func() {
+			reason := metricsRun.reason
+			if reason == "" {
+				reason = "Failed"
+			}
+			exportMetrics(metricsRun.command, gatherer.Failure, reason)
+		})
+	}
+	metricsRun.command = command
+}
+
+// metricsGrouping returns the labels the metrics are pushed under. They are
+// grouped by the infra ID of the cluster in dir, or by an ID of the run
+// before the cluster metadata is written, so that installs do not replace
+// each other's metrics.
+func metricsGrouping(dir string) (map[string]string, *types.ClusterMetadata) {
+	if metadata, err := cluster.LoadMetadata(dir); err == nil && metadata.InfraID != "" {
+		return map[string]string{"cluster_id": metadata.InfraID}, metadata
+	}
+	if metricsRun.id == "" {
+		metricsRun.id = uuid.New()
+	}
+	return map[string]string{"run_id": metricsRun.id}, nil
+}
+
+// exportMetrics pushes or writes the metrics of the command, when enabled.
+// Failing to export the metrics does not fail the command.
+func exportMetrics(command string, result gatherer.Result, reason string) {
+	if metricsOpts.pushgateway == "" && metricsOpts.file == "" || metricsRun.exported {
+		return
+	}
+	metricsRun.exported = true
+
+	timer.StopTimer(timer.TotalTimeElapsed)
+	invocation := gatherer.Current()
+	invocation.Command = command
+	invocation.Result = result
+	invocation.Reason = reason
+	invocation.Stages = timer.StageDurations()
+	invocation.Duration = invocation.Stages[timer.TotalTimeElapsed]
+	delete(invocation.Stages, timer.TotalTimeElapsed)
+
+	grouping, metadata := metricsGrouping(rootOpts.dir)
+	if metadata != nil && invocation.Platform == "" {
+		invocation.Platform = metadata.Platform()
+	}
+
+	if metricsOpts.pushgateway != "" {
+		if err := invocation.Push(metricsOpts.pushgateway, grouping); err != nil {
+			logrus.WithError(err).Warn("Failed to push the install metrics")
+		} else {
+			logrus.Debugf("Pushed the install metrics to %s", metricsOpts.pushgateway)
+		}
+	}
+	if metricsOpts.file != "" {
+		if err := invocation.WriteFile(metricsOpts.file); err != nil {
+			logrus.WithError(err).Warn("Failed to write the install metrics")
+		} else {
+			logrus.Debugf("Wrote the install metrics to %s", metricsOpts.file)
+		}
+	}
+}
+
+// fatalInstall exits with the metrics of a failed command exported, with
+// reason unless err carries a more precise one.
+func fatalInstall(command string, reason string, err error) {
+	var diagnosed *diagnostics.Err
+	switch {
+	case errors.As(err, &diagnosed) && diagnosed.Reason != "":
+		reason = diagnosed.Reason
+	case terraform.IsCancelled(err):
+		reason = "Cancelled"
+	}
+	metricsRun.command = command
+	metricsRun.reason = reason
+	logrus.Fatal(err)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/metrics/gatherer"
+)
+
+func TestMetricsGrouping(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metrics-grouping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	grouping, metadata := metricsGrouping(dir)
+	assert.Nil(t, metadata)
+	assert.NotEmpty(t, grouping["run_id"])
+	again, _ := metricsGrouping(dir)
+	assert.Equal(t, grouping, again, "the run ID should not change within a run")
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "metadata.json"), []byte(`{"clusterName":"test","infraID":"test-abc12"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	grouping, metadata = metricsGrouping(dir)
+	assert.NotNil(t, metadata)
+	assert.Equal(t, map[string]string{"cluster_id": "test-abc12"}, grouping)
+}
+
+func TestExportMetricsOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metrics-export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	savedOpts, savedRun, savedDir := metricsOpts, metricsRun, rootOpts.dir
+	defer func() { metricsOpts, metricsRun, rootOpts.dir = savedOpts, savedRun, savedDir }()
+
+	rootOpts.dir = dir
+	metricsOpts.pushgateway = ""
+	metricsOpts.file = filepath.Join(dir, "metrics.txt")
+	exportMetrics("create cluster", gatherer.Failure, "InstallFailed")
+	first, err := ioutil.ReadFile(metricsOpts.file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exportMetrics("create cluster", gatherer.Success, "")
+	second, err := ioutil.ReadFile(metricsOpts.file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(first), string(second), "the metrics should be exported only once")
+}
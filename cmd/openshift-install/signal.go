@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+// interruptContext returns a context that is cancelled the first time
+// the installer receives an interrupt or termination signal.  Long
+// running operations like Terraform use it to stop gracefully and save
+// their state.  Subsequent signals are handled as usual.
+func interruptContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case sig := <-signalCh:
+			logrus.Warnf("Received %s, stopping gracefully; send it again to abort immediately", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signalCh)
+	}()
+	return ctx, cancel
+}
@@ -6,6 +6,12 @@ then
 	shift 2
 fi
 
+if test "x${1}" = 'x--skip-masters'
+then
+	SKIP_MASTERS=1
+	shift
+fi
+
 ARTIFACTS="/tmp/artifacts-${GATHER_ID}"
 mkdir -p "${ARTIFACTS}"
 
@@ -117,7 +123,9 @@ wait
 
 echo "Gather remote logs"
 export MASTERS=()
-if [ "$#" -ne 0 ]; then
+if test -n "${SKIP_MASTERS}"; then
+    echo "Skipping control plane hosts, gathered by the installer"
+elif [ "$#" -ne 0 ]; then
     MASTERS=( "$@" )
 elif test -s "${ARTIFACTS}/resources/masters.list"; then
     mapfile -t MASTERS < "${ARTIFACTS}/resources/masters.list"
@@ -1,6 +1,7 @@
 package asset
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
@@ -23,6 +24,16 @@ type Asset interface {
 	Name() string
 }
 
+// ContextAsset is an Asset whose generation is long-running and can be
+// cancelled through a context.
+type ContextAsset interface {
+	Asset
+
+	// GenerateWithContext generates this asset given the states of its
+	// parent assets, stopping early if the context is done.
+	GenerateWithContext(context.Context, Parents) error
+}
+
 // WritableAsset is an Asset that has files that can be written to disk.
 // It can also be loaded from disk.
 type WritableAsset interface {
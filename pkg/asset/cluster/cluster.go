@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -16,19 +17,42 @@ import (
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/password"
 	"github.com/openshift/installer/pkg/asset/quota"
+	"github.com/openshift/installer/pkg/asset/releaseimage"
 	"github.com/openshift/installer/pkg/metrics/timer"
 	"github.com/openshift/installer/pkg/terraform"
 	typesaws "github.com/openshift/installer/pkg/types/aws"
 	typesazure "github.com/openshift/installer/pkg/types/azure"
 )
 
+// infrastructureTimeoutKey is the context key of the infrastructure timeout.
+type infrastructureTimeoutKey struct{}
+
+// WithInfrastructureTimeout returns a copy of ctx bounding the creation of
+// the infrastructure resources by Cluster, the run of 'terraform apply', to
+// timeout. A timeout of zero or less does not bound it.
+func WithInfrastructureTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, infrastructureTimeoutKey{}, timeout)
+}
+
+// infrastructureContext returns the context of 'terraform apply', bounded by
+// the infrastructure timeout of ctx.
+func infrastructureContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout, ok := ctx.Value(infrastructureTimeoutKey{}).(time.Duration); ok && timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
 // Cluster uses the terraform executable to launch a cluster
 // with the given terraform tfvar and generated templates.
 type Cluster struct {
 	FileList []*asset.File
 }
 
-var _ asset.WritableAsset = (*Cluster)(nil)
+var (
+	_ asset.WritableAsset = (*Cluster)(nil)
+	_ asset.ContextAsset  = (*Cluster)(nil)
+)
 
 // Name returns the human-friendly name of the asset.
 func (c *Cluster) Name() string {
@@ -49,17 +73,27 @@ func (c *Cluster) Dependencies() []asset.Asset {
 		&installconfig.PlatformPermsCheck{},
 		&installconfig.PlatformProvisionCheck{},
 		&quota.PlatformQuotaCheck{},
+		&releaseimage.MirrorCheck{},
 		&TerraformVariables{},
+		&TerraformOverrides{},
 		&password.KubeadminPassword{},
 	}
 }
 
 // Generate launches the cluster and generates the terraform state file on disk.
 func (c *Cluster) Generate(parents asset.Parents) (err error) {
+	return c.GenerateWithContext(context.Background(), parents)
+}
+
+// GenerateWithContext launches the cluster and generates the terraform
+// state file on disk.  If ctx is done before the infrastructure is
+// created, Terraform is stopped and the partial state file is kept.
+func (c *Cluster) GenerateWithContext(ctx context.Context, parents asset.Parents) (err error) {
 	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
 	terraformVariables := &TerraformVariables{}
-	parents.Get(clusterID, installConfig, terraformVariables)
+	terraformOverrides := &TerraformOverrides{}
+	parents.Get(clusterID, installConfig, terraformVariables, terraformOverrides)
 
 	if installConfig.Config.Platform.None != nil {
 		return errors.New("cluster cannot be created with platform set to 'none'")
@@ -79,22 +113,34 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 		}
 		extraArgs = append(extraArgs, fmt.Sprintf("-var-file=%s", filepath.Join(tmpDir, file.Filename)))
 	}
+	// The overrides are copied next to the platform modules when they are unpacked.
+	for _, file := range terraformOverrides.Files() {
+		path := filepath.Join(tmpDir, file.Filename)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, file.Data, 0600); err != nil {
+			return err
+		}
+	}
 
 	logrus.Infof("Creating infrastructure resources...")
 	switch installConfig.Config.Platform.Name() {
 	case typesaws.Name:
-		if err := aws.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
+		if err := aws.PreTerraform(ctx, clusterID.InfraID, installConfig); err != nil {
 			return err
 		}
 	case typesazure.Name:
-		if err := azure.PreTerraform(context.TODO(), clusterID.InfraID, installConfig); err != nil {
+		if err := azure.PreTerraform(ctx, clusterID.InfraID, installConfig); err != nil {
 			return err
 		}
 	}
 
 	timer.StartTimer("Infrastructure")
 
-	stateFile, err := terraform.Apply(tmpDir, installConfig.Config.Platform.Name(), extraArgs...)
+	applyCtx, cancel := infrastructureContext(ctx)
+	defer cancel()
+	stateFile, err := terraform.Apply(applyCtx, tmpDir, installConfig.Config.Platform.Name(), extraArgs...)
 	if err != nil {
 		err = errors.Wrap(err, "failed to create cluster")
 		if stateFile == "" {
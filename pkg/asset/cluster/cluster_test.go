@@ -0,0 +1,31 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfrastructureContext(t *testing.T) {
+	ctx, cancel := infrastructureContext(context.Background())
+	defer cancel()
+	_, ok := ctx.Deadline()
+	assert.False(t, ok, "no timeout")
+
+	ctx, cancel = infrastructureContext(WithInfrastructureTimeout(context.Background(), 0))
+	defer cancel()
+	_, ok = ctx.Deadline()
+	assert.False(t, ok, "zero timeout")
+
+	parent := WithInfrastructureTimeout(context.Background(), time.Hour)
+	ctx, cancel = infrastructureContext(parent)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if assert.True(t, ok, "timeout") {
+		assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute)
+	}
+	_, ok = parent.Deadline()
+	assert.False(t, ok, "the timeout only bounds terraform apply")
+}
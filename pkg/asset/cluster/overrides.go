@@ -0,0 +1,64 @@
+package cluster
+
+import (
+	"path/filepath"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/terraform"
+)
+
+// TerraformOverrides holds the user-supplied Terraform files that extend
+// the installer-managed infrastructure, e.g. *_override.tf files adding
+// tags or extra modules.
+type TerraformOverrides struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*TerraformOverrides)(nil)
+
+// overridePatterns are the files loaded from the overrides directory,
+// either for the root module or for a module one level below.
+var overridePatterns = []string{
+	filepath.Join(terraform.OverridesDirName, "*.tf"),
+	filepath.Join(terraform.OverridesDirName, "*.tf.json"),
+	filepath.Join(terraform.OverridesDirName, "*", "*.tf"),
+	filepath.Join(terraform.OverridesDirName, "*", "*.tf.json"),
+}
+
+// Name returns the human-friendly name of the asset.
+func (o *TerraformOverrides) Name() string {
+	return "Terraform Overrides"
+}
+
+// Dependencies returns no dependencies.
+func (o *TerraformOverrides) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate does nothing, the overrides can only be supplied by the user.
+func (o *TerraformOverrides) Generate(asset.Parents) error {
+	return nil
+}
+
+// Files returns the FileList generated by the asset.
+func (o *TerraformOverrides) Files() []*asset.File {
+	return o.FileList
+}
+
+// Load reads the user-supplied Terraform files from disk.  They are
+// validated against the platform modules when Terraform runs.
+func (o *TerraformOverrides) Load(f asset.FileFetcher) (found bool, err error) {
+	var fileList []*asset.File
+	for _, pattern := range overridePatterns {
+		files, err := f.FetchByPattern(pattern)
+		if err != nil {
+			return false, err
+		}
+		fileList = append(fileList, files...)
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+	o.FileList = fileList
+	return true, nil
+}
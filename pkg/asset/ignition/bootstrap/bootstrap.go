@@ -134,6 +134,7 @@ func (a *Bootstrap) Dependencies() []asset.Asset {
 		&tls.MCSCertKey{},
 		&tls.RootCA{},
 		&tls.ServiceAccountKeyPair{},
+		&tls.SignerChains{},
 		&releaseimage.Image{},
 		new(rhcos.Image),
 	}
@@ -618,6 +619,6 @@ func warnIfCertificatesExpired(config *igntypes.Config) {
 	}
 
 	if expiredCerts > 0 {
-		logrus.Warnf("Bootstrap Ignition-Config: %d certificates expired. Installation attempts with the created Ignition-Configs will possibly fail.", expiredCerts)
+		logrus.Warnf("Bootstrap Ignition-Config: %d certificates expired. Installation attempts with the created Ignition-Configs will possibly fail. Run 'openshift-install inspect certificates' for details.", expiredCerts)
 	}
 }
@@ -48,8 +48,10 @@ func TestMasterIgnitionCustomizationsGenerate(t *testing.T) {
 				},
 			}
 
+			caParents := asset.Parents{}
+			caParents.Add(&tls.PKIPolicy{}, &tls.ExternalCA{})
 			rootCA := &tls.RootCA{}
-			err := rootCA.Generate(nil)
+			err := rootCA.Generate(caParents)
 			assert.NoError(t, err, "unexpected error generating root CA")
 
 			parents := asset.Parents{}
@@ -0,0 +1,157 @@
+package manifests
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	identityProviderNamespace = "openshift-config"
+	clusterAdminsBindingName  = "installer-cluster-admins"
+)
+
+// authenticationManifests returns the manifests of the OAuth cluster config
+// with the identity providers of the install config, of the secrets and
+// config maps they refer to, and of the cluster-admin binding of the cluster
+// admins, by file name.
+func authenticationManifests(auth *types.Authentication) (map[string][]byte, error) {
+	objects := map[string]interface{}{}
+
+	oauth := &configv1.OAuth{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "OAuth",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+	}
+	for i, idp := range auth.IdentityProviders {
+		prefix := fmt.Sprintf("idp-%d", i)
+		provider := configv1.IdentityProvider{
+			Name:          idp.Name,
+			MappingMethod: configv1.MappingMethodClaim,
+		}
+		switch {
+		case idp.OpenID != nil:
+			secretName := prefix + "-client-secret"
+			objects[fmt.Sprintf("99_oauth-%s-secret.yaml", secretName)] = identityProviderSecret(secretName, "clientSecret", []byte(idp.OpenID.ClientSecret))
+
+			claims := configv1.OpenIDClaims{
+				PreferredUsername: []string{"preferred_username"},
+				Name:              []string{"name"},
+				Email:             []string{"email"},
+			}
+			if c := idp.OpenID.Claims; c != nil {
+				if len(c.PreferredUsername) > 0 {
+					claims.PreferredUsername = c.PreferredUsername
+				}
+				if len(c.Name) > 0 {
+					claims.Name = c.Name
+				}
+				if len(c.Email) > 0 {
+					claims.Email = c.Email
+				}
+			}
+			provider.Type = configv1.IdentityProviderTypeOpenID
+			provider.OpenID = &configv1.OpenIDIdentityProvider{
+				Issuer:       idp.OpenID.Issuer,
+				ClientID:     idp.OpenID.ClientID,
+				ClientSecret: configv1.SecretNameReference{Name: secretName},
+				ExtraScopes:  idp.OpenID.ExtraScopes,
+				Claims:       claims,
+			}
+
+			if idp.OpenID.CA != "" {
+				caName := prefix + "-ca"
+				objects[fmt.Sprintf("99_oauth-%s-configmap.yaml", caName)] = &corev1.ConfigMap{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: corev1.SchemeGroupVersion.String(),
+						Kind:       "ConfigMap",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: identityProviderNamespace,
+						Name:      caName,
+					},
+					Data: map[string]string{"ca.crt": idp.OpenID.CA},
+				}
+				provider.OpenID.CA = configv1.ConfigMapNameReference{Name: caName}
+			}
+		case idp.HTPasswd != nil:
+			data, err := ioutil.ReadFile(idp.HTPasswd.File)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read the htpasswd file of identity provider %q", idp.Name)
+			}
+			if len(data) == 0 {
+				return nil, errors.Errorf("the htpasswd file %s of identity provider %q is empty", idp.HTPasswd.File, idp.Name)
+			}
+
+			secretName := prefix + "-htpasswd"
+			objects[fmt.Sprintf("99_oauth-%s-secret.yaml", secretName)] = identityProviderSecret(secretName, "htpasswd", data)
+			provider.Type = configv1.IdentityProviderTypeHTPasswd
+			provider.HTPasswd = &configv1.HTPasswdIdentityProvider{
+				FileData: configv1.SecretNameReference{Name: secretName},
+			}
+		}
+		oauth.Spec.IdentityProviders = append(oauth.Spec.IdentityProviders, provider)
+	}
+	objects["99_oauth-cluster-config.yaml"] = oauth
+
+	binding := &rbacv1.ClusterRoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterAdminsBindingName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+	}
+	for _, admin := range auth.ClusterAdmins {
+		subject := rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: admin.User}
+		if admin.Group != "" {
+			subject = rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: admin.Group}
+		}
+		binding.Subjects = append(binding.Subjects, subject)
+	}
+	objects["99_cluster-admins-clusterrolebinding.yaml"] = binding
+
+	manifests := make(map[string][]byte, len(objects))
+	for name, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s", name)
+		}
+		manifests[name] = data
+	}
+	return manifests, nil
+}
+
+func identityProviderSecret(name, key string, data []byte) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: identityProviderNamespace,
+			Name:      name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{key: data},
+	}
+}
@@ -0,0 +1,131 @@
+package manifests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestAuthenticationManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "authentication")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	htpasswd := filepath.Join(dir, "users.htpasswd")
+	if err := ioutil.WriteFile(htpasswd, []byte("alice:$2y$05$hash\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auth := &types.Authentication{
+		IdentityProviders: []types.IdentityProvider{{
+			Name: "corp",
+			OpenID: &types.OpenIDIdentityProvider{
+				Issuer:       "https://sso.example.com",
+				ClientID:     "openshift",
+				ClientSecret: "secret",
+				CA:           "test-ca",
+				Claims:       &types.OpenIDClaims{PreferredUsername: []string{"upn"}},
+			},
+		}, {
+			Name:     "local",
+			HTPasswd: &types.HTPasswdIdentityProvider{File: htpasswd},
+		}},
+		ClusterAdmins: []types.ClusterAdmin{{User: "alice"}, {Group: "platform-admins"}},
+	}
+	manifests, err := authenticationManifests(auth)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var names []string
+	for name := range manifests {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{
+		"99_oauth-cluster-config.yaml",
+		"99_oauth-idp-0-client-secret-secret.yaml",
+		"99_oauth-idp-0-ca-configmap.yaml",
+		"99_oauth-idp-1-htpasswd-secret.yaml",
+		"99_cluster-admins-clusterrolebinding.yaml",
+	}, names)
+
+	assert.Equal(t, `apiVersion: config.openshift.io/v1
+kind: OAuth
+metadata:
+  creationTimestamp: null
+  name: cluster
+spec:
+  identityProviders:
+  - mappingMethod: claim
+    name: corp
+    openID:
+      ca:
+        name: idp-0-ca
+      claims:
+        email:
+        - email
+        name:
+        - name
+        preferredUsername:
+        - upn
+      clientID: openshift
+      clientSecret:
+        name: idp-0-client-secret
+      issuer: https://sso.example.com
+    type: OpenID
+  - htpasswd:
+      fileData:
+        name: idp-1-htpasswd
+    mappingMethod: claim
+    name: local
+    type: HTPasswd
+  templates:
+    error:
+      name: ""
+    login:
+      name: ""
+    providerSelection:
+      name: ""
+  tokenConfig: {}
+status: {}
+`, string(manifests["99_oauth-cluster-config.yaml"]))
+
+	assert.Equal(t, `apiVersion: v1
+data:
+  htpasswd: YWxpY2U6JDJ5JDA1JGhhc2gK
+kind: Secret
+metadata:
+  creationTimestamp: null
+  name: idp-1-htpasswd
+  namespace: openshift-config
+type: Opaque
+`, string(manifests["99_oauth-idp-1-htpasswd-secret.yaml"]))
+
+	assert.Equal(t, `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  creationTimestamp: null
+  name: installer-cluster-admins
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: cluster-admin
+subjects:
+- apiGroup: rbac.authorization.k8s.io
+  kind: User
+  name: alice
+- apiGroup: rbac.authorization.k8s.io
+  kind: Group
+  name: platform-admins
+`, string(manifests["99_cluster-admins-clusterrolebinding.yaml"]))
+
+	auth.IdentityProviders[1].HTPasswd.File = filepath.Join(dir, "missing")
+	_, err = authenticationManifests(auth)
+	assert.Regexp(t, `^failed to read the htpasswd file of identity provider "local": open .*/missing: no such file or directory$`, err)
+}
@@ -214,8 +214,17 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 		baremetalConfig,
 		rhcosImage)
 
-	assetData := map[string][]byte{
-		"99_kubeadmin-password-secret.yaml": applyTemplateData(kubeadminPasswordSecret.Files()[0].Data, templateData),
+	assetData := map[string][]byte{}
+	if auth := installConfig.Config.Authentication; auth != nil {
+		authData, err := authenticationManifests(auth)
+		if err != nil {
+			return errors.Wrap(err, "failed to create the authentication manifests")
+		}
+		for name, data := range authData {
+			assetData[name] = data
+		}
+	} else {
+		assetData["99_kubeadmin-password-secret.yaml"] = applyTemplateData(kubeadminPasswordSecret.Files()[0].Data, templateData)
 	}
 
 	switch platform {
@@ -273,6 +273,19 @@ func redactedInstallConfig(config types.InstallConfig) ([]byte, error) {
 		p.Password = ""
 		config.Platform.VSphere = &p
 	}
+	if config.Authentication != nil {
+		a := *config.Authentication
+		a.IdentityProviders = make([]types.IdentityProvider, len(config.Authentication.IdentityProviders))
+		for i, idp := range config.Authentication.IdentityProviders {
+			if idp.OpenID != nil {
+				p := *idp.OpenID
+				p.ClientSecret = ""
+				idp.OpenID = &p
+			}
+			a.IdentityProviders[i] = idp
+		}
+		config.Authentication = &a
+	}
 	return yaml.Marshal(config)
 }
 
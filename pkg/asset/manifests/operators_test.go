@@ -56,10 +56,30 @@ func TestRedactedInstallConfig(t *testing.T) {
 				},
 			},
 			PullSecret: "test-pull-secret",
+			Authentication: &types.Authentication{
+				IdentityProviders: []types.IdentityProvider{{
+					Name: "corp",
+					OpenID: &types.OpenIDIdentityProvider{
+						Issuer:       "https://sso.example.com",
+						ClientID:     "openshift",
+						ClientSecret: "test-client-secret",
+					},
+				}},
+				ClusterAdmins: []types.ClusterAdmin{{User: "alice"}},
+			},
 		}
 	}
 	expectedConfig := createInstallConfig()
-	expectedYaml := `baseDomain: test-domain
+	expectedYaml := `authentication:
+  clusterAdmins:
+  - user: alice
+  identityProviders:
+  - name: corp
+    openID:
+      clientID: openshift
+      clientSecret: ""
+      issuer: https://sso.example.com
+baseDomain: test-domain
 compute:
 - architecture: amd64
   name: compute
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -23,13 +24,22 @@ type KubeadminPassword struct {
 
 var _ asset.WritableAsset = (*KubeadminPassword)(nil)
 
-// Dependencies returns no dependencies.
+// Dependencies returns the dependencies for generating the kubeadmin password.
 func (a *KubeadminPassword) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
 }
 
-// Generate the kubeadmin password
-func (a *KubeadminPassword) Generate(asset.Parents) error {
+// Generate the kubeadmin password, unless the users log in with the
+// identity providers of the install config.
+func (a *KubeadminPassword) Generate(parents asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	parents.Get(installConfig)
+	if installConfig.Config.Authentication != nil {
+		return nil
+	}
+
 	err := a.generateRandomPasswordHash(23)
 	if err != nil {
 		return err
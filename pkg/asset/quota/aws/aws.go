@@ -38,6 +38,19 @@ func Constraints(config *types.InstallConfig, controlPlanes []machineapi.Machine
 	return aggregate(ret)
 }
 
+// ComputeConstraints returns the quota constraints of the compute machines
+// added to an existing cluster, whose network already counts against the
+// quotas.
+func ComputeConstraints(config *types.InstallConfig, computes []machineapi.MachineSet, instanceTypes map[string]InstanceTypeInfo) []quota.Constraint {
+	computeReplicas := make([]int64, len(computes))
+	computeConfigs := make([]*awsprovider.AWSMachineProviderConfig, len(computes))
+	for i, w := range computes {
+		computeReplicas[i] = int64(*w.Spec.Replicas)
+		computeConfigs[i] = w.Spec.Template.Spec.ProviderSpec.Value.Object.(*awsprovider.AWSMachineProviderConfig)
+	}
+	return aggregate(compute(config, computeReplicas, computeConfigs, instanceTypes)())
+}
+
 func aggregate(quotas []quota.Constraint) []quota.Constraint {
 	sort.SliceStable(quotas, func(i, j int) bool {
 		return quotas[i].Name < quotas[j].Name
@@ -0,0 +1,165 @@
+package azure
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+	azureprovider "sigs.k8s.io/cluster-api-provider-azure/pkg/apis/azureprovider/v1beta1"
+
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	"github.com/openshift/installer/pkg/quota"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/azure"
+)
+
+// VMSize holds the details of a virtual machine size that count against the
+// compute quotas.
+type VMSize struct {
+	// Family is the name of the family quota, e.g. standardDSv3Family.
+	Family string
+
+	// VCPUs is the number of vCPUs of the size.
+	VCPUs int64
+}
+
+// VMSizes looks up the sizes used by the machines in the region.
+func VMSizes(ctx context.Context, client azureconfig.API, region string, controlPlanes []machineapi.Machine, computes []machineapi.MachineSet) (map[string]VMSize, error) {
+	names := map[string]bool{}
+	for _, m := range controlPlanes {
+		names[m.Spec.ProviderSpec.Value.Object.(*azureprovider.AzureMachineProviderSpec).VMSize] = true
+	}
+	for _, w := range computes {
+		names[w.Spec.Template.Spec.ProviderSpec.Value.Object.(*azureprovider.AzureMachineProviderSpec).VMSize] = true
+	}
+
+	sizes := make(map[string]VMSize, len(names))
+	for name := range names {
+		sku, err := client.GetVirtualMachineSku(ctx, name, region)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the details of %s", name)
+		}
+		if sku == nil {
+			return nil, errors.Errorf("%s is not available in %s", name, region)
+		}
+		size := VMSize{}
+		if sku.Family != nil {
+			size.Family = *sku.Family
+		}
+		if sku.Capabilities != nil {
+			for _, capability := range *sku.Capabilities {
+				if capability.Name == nil || capability.Value == nil || !strings.EqualFold(*capability.Name, "vCPUs") {
+					continue
+				}
+				vcpus, err := strconv.ParseInt(*capability.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid vCPUs for %s", name)
+				}
+				size.VCPUs = vcpus
+			}
+		}
+		sizes[name] = size
+	}
+	return sizes, nil
+}
+
+// Constraints returns a list of quota constraints based on the InstallConfig.
+// These constraints can be used to check if there is enough quota for creating a cluster
+// for the install config.
+func Constraints(config *types.InstallConfig, controlPlanes []machineapi.Machine, computes []machineapi.MachineSet, sizes map[string]VMSize) []quota.Constraint {
+	region := config.Platform.Azure.Region
+
+	var ret []quota.Constraint
+	for _, m := range controlPlanes {
+		spec := m.Spec.ProviderSpec.Value.Object.(*azureprovider.AzureMachineProviderSpec)
+		ret = append(ret, vmConstraints(region, sizes[spec.VMSize], 1)...)
+	}
+	for _, w := range computes {
+		spec := w.Spec.Template.Spec.ProviderSpec.Value.Object.(*azureprovider.AzureMachineProviderSpec)
+		ret = append(ret, vmConstraints(region, sizes[spec.VMSize], int64(*w.Spec.Replicas))...)
+	}
+	ret = append(ret, publicIPs(config)...)
+	return aggregate(ret)
+}
+
+// ComputeConstraints returns the quota constraints of the compute machines
+// added to an existing cluster, whose public IP addresses already count
+// against the quotas.
+func ComputeConstraints(config *types.InstallConfig, computes []machineapi.MachineSet, sizes map[string]VMSize) []quota.Constraint {
+	region := config.Platform.Azure.Region
+
+	var ret []quota.Constraint
+	for _, w := range computes {
+		spec := w.Spec.Template.Spec.ProviderSpec.Value.Object.(*azureprovider.AzureMachineProviderSpec)
+		ret = append(ret, vmConstraints(region, sizes[spec.VMSize], int64(*w.Spec.Replicas))...)
+	}
+	return aggregate(ret)
+}
+
+// vmConstraints returns the regional and family vCPUs used by count
+// virtual machines of size.
+func vmConstraints(region string, size VMSize, count int64) []quota.Constraint {
+	ret := []quota.Constraint{{
+		Name:   "compute/cores",
+		Region: region,
+		Count:  size.VCPUs * count,
+	}, {
+		Name:   "compute/virtualMachines",
+		Region: region,
+		Count:  count,
+	}}
+	if size.Family != "" {
+		ret = append(ret, quota.Constraint{
+			Name:   "compute/" + size.Family,
+			Region: region,
+			Count:  size.VCPUs * count,
+		})
+	}
+	return ret
+}
+
+// publicIPs returns the public IP addresses of the load balancers and the
+// bootstrap host.
+func publicIPs(config *types.InstallConfig) []quota.Constraint {
+	private := config.Publish == types.InternalPublishingStrategy
+	var count int64
+	if !private || config.Platform.Azure.OutboundType != azure.UserDefinedRoutingOutboundType {
+		count++
+	}
+	if !private {
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	return []quota.Constraint{{
+		Name:   "network/PublicIPAddresses",
+		Region: config.Platform.Azure.Region,
+		Count:  count,
+	}}
+}
+
+func aggregate(quotas []quota.Constraint) []quota.Constraint {
+	if len(quotas) == 0 {
+		return quotas
+	}
+	sort.SliceStable(quotas, func(i, j int) bool {
+		return quotas[i].Name < quotas[j].Name
+	})
+
+	i := 0
+	for j := 1; j < len(quotas); j++ {
+		if quotas[i].Name == quotas[j].Name && quotas[i].Region == quotas[j].Region {
+			quotas[i].Count += quotas[j].Count
+		} else {
+			i++
+			if i != j {
+				quotas[i] = quotas[j]
+			}
+		}
+	}
+	return quotas[:i+1]
+}
@@ -0,0 +1,113 @@
+package azure
+
+import (
+	"testing"
+
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	azureprovider "sigs.k8s.io/cluster-api-provider-azure/pkg/apis/azureprovider/v1beta1"
+
+	"github.com/openshift/installer/pkg/quota"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/azure"
+)
+
+func machine(size string) machineapi.Machine {
+	m := machineapi.Machine{}
+	m.Spec.ProviderSpec.Value = &runtime.RawExtension{Object: &azureprovider.AzureMachineProviderSpec{VMSize: size}}
+	return m
+}
+
+func machineSet(size string, replicas int32) machineapi.MachineSet {
+	ms := machineapi.MachineSet{}
+	ms.Spec.Replicas = &replicas
+	ms.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{Object: &azureprovider.AzureMachineProviderSpec{VMSize: size}}
+	return ms
+}
+
+func TestConstraints(t *testing.T) {
+	sizes := map[string]VMSize{
+		"Standard_D8s_v3": {Family: "standardDSv3Family", VCPUs: 8},
+		"Standard_D4s_v3": {Family: "standardDSv3Family", VCPUs: 4},
+		"Standard_F4s":    {Family: "standardFSFamily", VCPUs: 4},
+	}
+	masters := []machineapi.Machine{machine("Standard_D8s_v3"), machine("Standard_D8s_v3"), machine("Standard_D8s_v3")}
+
+	cases := []struct {
+		name         string
+		publish      types.PublishingStrategy
+		outboundType azure.OutboundType
+		workers      []machineapi.MachineSet
+
+		expected []quota.Constraint
+	}{{
+		name:    "external",
+		publish: types.ExternalPublishingStrategy,
+		workers: []machineapi.MachineSet{machineSet("Standard_D4s_v3", 2), machineSet("Standard_D4s_v3", 1)},
+		expected: []quota.Constraint{
+			{Name: "compute/cores", Region: "centralus", Count: 36},
+			{Name: "compute/standardDSv3Family", Region: "centralus", Count: 36},
+			{Name: "compute/virtualMachines", Region: "centralus", Count: 6},
+			{Name: "network/PublicIPAddresses", Region: "centralus", Count: 2},
+		},
+	}, {
+		name:    "several families",
+		publish: types.ExternalPublishingStrategy,
+		workers: []machineapi.MachineSet{machineSet("Standard_F4s", 3)},
+		expected: []quota.Constraint{
+			{Name: "compute/cores", Region: "centralus", Count: 36},
+			{Name: "compute/standardDSv3Family", Region: "centralus", Count: 24},
+			{Name: "compute/standardFSFamily", Region: "centralus", Count: 12},
+			{Name: "compute/virtualMachines", Region: "centralus", Count: 6},
+			{Name: "network/PublicIPAddresses", Region: "centralus", Count: 2},
+		},
+	}, {
+		name:    "internal",
+		publish: types.InternalPublishingStrategy,
+		expected: []quota.Constraint{
+			{Name: "compute/cores", Region: "centralus", Count: 24},
+			{Name: "compute/standardDSv3Family", Region: "centralus", Count: 24},
+			{Name: "compute/virtualMachines", Region: "centralus", Count: 3},
+			{Name: "network/PublicIPAddresses", Region: "centralus", Count: 1},
+		},
+	}, {
+		name:         "internal with user defined routing",
+		publish:      types.InternalPublishingStrategy,
+		outboundType: azure.UserDefinedRoutingOutboundType,
+		expected: []quota.Constraint{
+			{Name: "compute/cores", Region: "centralus", Count: 24},
+			{Name: "compute/standardDSv3Family", Region: "centralus", Count: 24},
+			{Name: "compute/virtualMachines", Region: "centralus", Count: 3},
+		},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &types.InstallConfig{
+				Publish: tc.publish,
+				Platform: types.Platform{
+					Azure: &azure.Platform{Region: "centralus", OutboundType: tc.outboundType},
+				},
+			}
+			assert.Equal(t, tc.expected, Constraints(config, masters, tc.workers, sizes))
+		})
+	}
+}
+
+func TestComputeConstraints(t *testing.T) {
+	sizes := map[string]VMSize{
+		"Standard_D4s_v3": {Family: "standardDSv3Family", VCPUs: 4},
+	}
+	config := &types.InstallConfig{
+		Platform: types.Platform{
+			Azure: &azure.Platform{Region: "centralus"},
+		},
+	}
+	expected := []quota.Constraint{
+		{Name: "compute/cores", Region: "centralus", Count: 20},
+		{Name: "compute/standardDSv3Family", Region: "centralus", Count: 20},
+		{Name: "compute/virtualMachines", Region: "centralus", Count: 5},
+	}
+	workers := []machineapi.MachineSet{machineSet("Standard_D4s_v3", 2), machineSet("Standard_D4s_v3", 3)}
+	assert.Equal(t, expected, ComputeConstraints(config, workers, sizes))
+}
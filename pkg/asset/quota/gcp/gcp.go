@@ -42,6 +42,21 @@ func Constraints(client *Client, config *types.InstallConfig, controlPlanes []ma
 	return aggregate(ret)
 }
 
+// ComputeConstraints returns the quota constraints of the compute machines
+// added to an existing cluster, whose network, load balancers and service
+// accounts already count against the quotas.
+func ComputeConstraints(client *Client, config *types.InstallConfig, computes []machineapi.MachineSet) []quota.Constraint {
+	var ret []quota.Constraint
+	for _, w := range computes {
+		m := w.Spec.Template.Spec.ProviderSpec.Value.Object.(*gcpprovider.GCPMachineProviderSpec)
+		q := machineTypeToQuota(client, m.Zone, m.MachineType)
+		q.Count = q.Count * int64(*w.Spec.Replicas)
+		q.Region = config.Platform.GCP.Region
+		ret = append(ret, q)
+	}
+	return aggregate(ret)
+}
+
 func aggregate(quotas []quota.Constraint) []quota.Constraint {
 	sort.SliceStable(quotas, func(i, j int) bool {
 		return quotas[i].Name < quotas[j].Name
@@ -28,6 +28,18 @@ func Constraints(ci *validation.CloudInfo, controlPlanes []machineapi.Machine, c
 	return aggregate(ret)
 }
 
+// ComputeConstraints returns the quota constraints of the compute machines
+// added to an existing cluster.
+func ComputeConstraints(ci *validation.CloudInfo, computes []machineapi.MachineSet) []quota.Constraint {
+	computeReplicas := make([]int64, len(computes))
+	computeConfigs := make([]*openstackprovider.OpenstackProviderSpec, len(computes))
+	for i, m := range computes {
+		computeReplicas[i] = int64(*m.Spec.Replicas)
+		computeConfigs[i] = m.Spec.Template.Spec.ProviderSpec.Value.Object.(*openstackprovider.OpenstackProviderSpec)
+	}
+	return aggregate(compute(ci, computeReplicas, computeConfigs))
+}
+
 func controlPlane(ci *validation.CloudInfo, machines []*openstackprovider.OpenstackProviderSpec) []quota.Constraint {
 	var ret []quota.Constraint
 	for _, m := range machines {
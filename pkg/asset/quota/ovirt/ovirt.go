@@ -0,0 +1,39 @@
+package ovirt
+
+import (
+	ovirtprovider "github.com/openshift/cluster-api-provider-ovirt/pkg/apis/ovirtprovider/v1beta1"
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+
+	"github.com/openshift/installer/pkg/quota"
+	quotaovirt "github.com/openshift/installer/pkg/quota/ovirt"
+)
+
+// Constraints returns a list of quota constraints based on the machines.
+// These constraints can be used to check if there is enough capacity in the
+// oVirt cluster and storage domain for creating a cluster for the install
+// config.
+func Constraints(controlPlanes []machineapi.Machine, computes []machineapi.MachineSet) []quota.Constraint {
+	vcpus := quota.Constraint{Name: quotaovirt.VCPUs}
+	memory := quota.Constraint{Name: quotaovirt.Memory}
+	storage := quota.Constraint{Name: quotaovirt.Storage}
+	add := func(spec *ovirtprovider.OvirtMachineProviderSpec, count int64) {
+		if spec.CPU != nil {
+			threads := spec.CPU.Threads
+			if threads == 0 {
+				threads = 1
+			}
+			vcpus.Count += int64(spec.CPU.Sockets*spec.CPU.Cores*threads) * count
+		}
+		memory.Count += int64(spec.MemoryMB) * count
+		if spec.OSDisk != nil {
+			storage.Count += spec.OSDisk.SizeGB * count
+		}
+	}
+	for _, m := range controlPlanes {
+		add(m.Spec.ProviderSpec.Value.Object.(*ovirtprovider.OvirtMachineProviderSpec), 1)
+	}
+	for _, w := range computes {
+		add(w.Spec.Template.Spec.ProviderSpec.Value.Object.(*ovirtprovider.OvirtMachineProviderSpec), int64(*w.Spec.Replicas))
+	}
+	return []quota.Constraint{memory, storage, vcpus}
+}
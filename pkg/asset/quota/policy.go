@@ -0,0 +1,97 @@
+package quota
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/quota"
+)
+
+const (
+	policyFilename = "quota-policy.yaml"
+
+	// PolicyEnvVar names the environment variable holding the path of the
+	// quota policy used when the asset directory has none.
+	PolicyEnvVar = "OPENSHIFT_INSTALL_QUOTA_POLICY"
+)
+
+// Policy is the quota policy the platform quotas are checked against. It is
+// read from quota-policy.yaml in the asset directory, or else from the file
+// named by OPENSHIFT_INSTALL_QUOTA_POLICY.
+type Policy struct {
+	Policy *quota.Policy
+	File   *asset.File
+}
+
+var _ asset.WritableAsset = (*Policy)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (p *Policy) Name() string {
+	return "Quota Policy"
+}
+
+// Dependencies returns no dependencies.
+func (p *Policy) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate reads the policy named by OPENSHIFT_INSTALL_QUOTA_POLICY. Without
+// it, the default policy applies.
+func (p *Policy) Generate(asset.Parents) error {
+	p.Policy = &quota.Policy{}
+	path := os.Getenv(PolicyEnvVar)
+	if path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read quota policy")
+	}
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return errors.Wrapf(err, "invalid quota policy %s", path)
+	}
+	p.Policy = policy
+	return nil
+}
+
+// Files returns the policy file when it was read from the asset directory.
+func (p *Policy) Files() []*asset.File {
+	if p.File != nil {
+		return []*asset.File{p.File}
+	}
+	return []*asset.File{}
+}
+
+// Load reads the policy from the asset directory.
+func (p *Policy) Load(f asset.FileFetcher) (found bool, err error) {
+	file, err := f.FetchByName(policyFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	policy, err := ParsePolicy(file.Data)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid %q file", policyFilename)
+	}
+	p.Policy, p.File = policy, file
+	return true, nil
+}
+
+// ParsePolicy parses and validates a YAML quota policy.
+func ParsePolicy(data []byte) (*quota.Policy, error) {
+	policy := &quota.Policy{}
+	if err := yaml.UnmarshalStrict(data, policy, yaml.DisallowUnknownFields); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal")
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
@@ -12,24 +13,31 @@ import (
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	configgcp "github.com/openshift/installer/pkg/asset/installconfig/gcp"
 	openstackvalidation "github.com/openshift/installer/pkg/asset/installconfig/openstack/validation"
+	ovirtconfig "github.com/openshift/installer/pkg/asset/installconfig/ovirt"
 	"github.com/openshift/installer/pkg/asset/machines"
 	"github.com/openshift/installer/pkg/asset/quota/aws"
+	"github.com/openshift/installer/pkg/asset/quota/azure"
 	"github.com/openshift/installer/pkg/asset/quota/gcp"
 	"github.com/openshift/installer/pkg/asset/quota/openstack"
+	"github.com/openshift/installer/pkg/asset/quota/ovirt"
+	"github.com/openshift/installer/pkg/asset/quota/vsphere"
 	"github.com/openshift/installer/pkg/diagnostics"
 	"github.com/openshift/installer/pkg/quota"
 	quotaaws "github.com/openshift/installer/pkg/quota/aws"
+	quotaazure "github.com/openshift/installer/pkg/quota/azure"
 	quotagcp "github.com/openshift/installer/pkg/quota/gcp"
+	quotaovirt "github.com/openshift/installer/pkg/quota/ovirt"
+	quotavsphere "github.com/openshift/installer/pkg/quota/vsphere"
 	typesaws "github.com/openshift/installer/pkg/types/aws"
-	"github.com/openshift/installer/pkg/types/azure"
+	typesazure "github.com/openshift/installer/pkg/types/azure"
 	"github.com/openshift/installer/pkg/types/baremetal"
 	typesgcp "github.com/openshift/installer/pkg/types/gcp"
 	"github.com/openshift/installer/pkg/types/kubevirt"
 	"github.com/openshift/installer/pkg/types/libvirt"
 	"github.com/openshift/installer/pkg/types/none"
 	typesopenstack "github.com/openshift/installer/pkg/types/openstack"
-	"github.com/openshift/installer/pkg/types/ovirt"
-	"github.com/openshift/installer/pkg/types/vsphere"
+	typesovirt "github.com/openshift/installer/pkg/types/ovirt"
+	typesvsphere "github.com/openshift/installer/pkg/types/vsphere"
 )
 
 // PlatformQuotaCheck is an asset that validates the install-config platform for
@@ -45,6 +53,7 @@ func (a *PlatformQuotaCheck) Dependencies() []asset.Asset {
 		&installconfig.InstallConfig{},
 		&machines.Master{},
 		&machines.Worker{},
+		&Policy{},
 	}
 }
 
@@ -53,7 +62,8 @@ func (a *PlatformQuotaCheck) Generate(dependencies asset.Parents) error {
 	ic := &installconfig.InstallConfig{}
 	mastersAsset := &machines.Master{}
 	workersAsset := &machines.Worker{}
-	dependencies.Get(ic, mastersAsset, workersAsset)
+	policy := &Policy{}
+	dependencies.Get(ic, mastersAsset, workersAsset, policy)
 
 	masters, err := mastersAsset.Machines()
 	if err != nil {
@@ -65,78 +75,173 @@ func (a *PlatformQuotaCheck) Generate(dependencies asset.Parents) error {
 		return err
 	}
 
+	switch ic.Config.Platform.Name() {
+	case typesovirt.Name, typesvsphere.Name:
+		// The capacity of hypervisors is usually overcommitted, so it is
+		// only reported by 'check quota'.
+		return nil
+	}
+	reports, err := Reports(context.TODO(), ic, masters, workers, policy.Policy)
+	if err != nil {
+		return err
+	}
+	return Verify(reports, policy.Policy)
+}
+
+// Reports checks the constraints of the machines against the quotas of the
+// platform of the install config and the thresholds of policy. It returns no reports when the quotas
+// cannot be checked for the platform or region, or when the credentials are
+// not allowed to read them.
+func Reports(ctx context.Context, ic *installconfig.InstallConfig, masters []machineapi.Machine, workers []machineapi.MachineSet, policy *quota.Policy) ([]quota.ConstraintReport, error) {
+	return reports(ctx, ic, masters, workers, policy, false)
+}
+
+// ScaleOutReports checks the constraints of the compute machines added to an
+// existing cluster against the quotas of its platform, like Reports. The
+// resources the cluster already has, including its control plane, are
+// expected to count against the quotas.
+func ScaleOutReports(ctx context.Context, ic *installconfig.InstallConfig, workers []machineapi.MachineSet, policy *quota.Policy) ([]quota.ConstraintReport, error) {
+	return reports(ctx, ic, nil, workers, policy, true)
+}
+
+func reports(ctx context.Context, ic *installconfig.InstallConfig, masters []machineapi.Machine, workers []machineapi.MachineSet, policy *quota.Policy, scaleOut bool) ([]quota.ConstraintReport, error) {
+	var (
+		quotas      []quota.Quota
+		constraints []quota.Constraint
+	)
 	platform := ic.Config.Platform.Name()
 	switch platform {
 	case typesaws.Name:
 		if !quotaaws.SupportedRegions.Has(ic.AWS.Region) {
 			logrus.Debugf("%s does not support API for checking quotas, therefore skipping.", ic.AWS.Region)
-			return nil
+			return nil, nil
 		}
 		services := []string{"ec2", "vpc"}
-		session, err := ic.AWS.Session(context.TODO())
+		session, err := ic.AWS.Session(ctx)
 		if err != nil {
-			return errors.Wrap(err, "failed to load AWS session")
+			return nil, errors.Wrap(err, "failed to load AWS session")
 		}
-		q, err := quotaaws.Load(context.TODO(), session, ic.AWS.Region, services...)
+		q, err := quotaaws.Load(ctx, session, ic.AWS.Region, services...)
 		if quotaaws.IsUnauthorized(err) {
 			logrus.Warnf("Missing permissions to fetch Quotas and therefore will skip checking them: %v, make sure you have `servicequotas:ListAWSDefaultServiceQuotas` permission available to the user.", err)
-			return nil
+			return nil, nil
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
+			return nil, errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
 		}
-		instanceTypes, err := aws.InstanceTypes(context.TODO(), session, ic.AWS.Region)
+		instanceTypes, err := aws.InstanceTypes(ctx, session, ic.AWS.Region)
 		if quotaaws.IsUnauthorized(err) {
 			logrus.Warnf("Missing permissions to fetch instance types and therefore will skip checking Quotas: %v, make sure you have `ec2:DescribeInstanceTypes` permission available to the user.", err)
-			return nil
+			return nil, nil
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to load instance types for %s", ic.AWS.Region)
+			return nil, errors.Wrapf(err, "failed to load instance types for %s", ic.AWS.Region)
 		}
-		reports, err := quota.Check(q, aws.Constraints(ic.Config, masters, workers, instanceTypes))
-		if err != nil {
-			return summarizeFailingReport(reports)
+		quotas, constraints = q, aws.Constraints(ic.Config, masters, workers, instanceTypes)
+		if scaleOut {
+			constraints = aws.ComputeConstraints(ic.Config, workers, instanceTypes)
 		}
-		summarizeReport(reports)
 	case typesgcp.Name:
 		services := []string{"compute.googleapis.com", "iam.googleapis.com"}
-		q, err := quotagcp.Load(context.TODO(), ic.Config.Platform.GCP.ProjectID, services...)
+		q, err := quotagcp.Load(ctx, ic.Config.Platform.GCP.ProjectID, services...)
 		if quotagcp.IsUnauthorized(err) {
 			logrus.Warnf("Missing permissions to fetch Quotas and therefore will skip checking them: %v, make sure you have `roles/servicemanagement.quotaViewer` assigned to the user.", err)
-			return nil
+			return nil, nil
 		}
 		if err != nil {
-			return errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
+			return nil, errors.Wrapf(err, "failed to load Quota for services: %s", strings.Join(services, ", "))
 		}
-		session, err := configgcp.GetSession(context.TODO())
+		session, err := configgcp.GetSession(ctx)
 		if err != nil {
-			return errors.Wrap(err, "failed to load GCP session")
+			return nil, errors.Wrap(err, "failed to load GCP session")
 		}
-		client, err := gcp.NewClient(context.TODO(), session, ic.Config.Platform.GCP.ProjectID)
+		client, err := gcp.NewClient(ctx, session, ic.Config.Platform.GCP.ProjectID)
 		if err != nil {
-			return errors.Wrap(err, "failed to create client for quota constraints")
+			return nil, errors.Wrap(err, "failed to create client for quota constraints")
 		}
-		reports, err := quota.Check(q, gcp.Constraints(client, ic.Config, masters, workers))
-		if err != nil {
-			return summarizeFailingReport(reports)
+		quotas, constraints = q, gcp.Constraints(client, ic.Config, masters, workers)
+		if scaleOut {
+			constraints = gcp.ComputeConstraints(client, ic.Config, workers)
 		}
-		summarizeReport(reports)
 	case typesopenstack.Name:
 		ci, err := openstackvalidation.GetCloudInfo(ic.Config)
 		if err != nil {
-			return errors.Wrap(err, "failed to get cloud info")
+			return nil, errors.Wrap(err, "failed to get cloud info")
+		}
+		quotas, constraints = ci.Quotas, openstack.Constraints(ci, masters, workers)
+		if scaleOut {
+			constraints = openstack.ComputeConstraints(ci, workers)
 		}
-		reports, err := quota.Check(ci.Quotas, openstack.Constraints(ci, masters, workers))
+	case typesazure.Name:
+		session, err := ic.Azure.Session()
 		if err != nil {
-			return summarizeFailingReport(reports)
+			return nil, errors.Wrap(err, "failed to load Azure session")
 		}
-		summarizeReport(reports)
-	case azure.Name, baremetal.Name, libvirt.Name, none.Name, ovirt.Name, vsphere.Name, kubevirt.Name:
+		region := ic.Config.Platform.Azure.Region
+		q, err := quotaazure.Load(ctx, session, region)
+		if quotaazure.IsUnauthorized(err) {
+			logrus.Warnf("Missing permissions to fetch Quotas and therefore will skip checking them: %v, make sure you have the `Microsoft.Compute/locations/usages/read` and `Microsoft.Network/locations/usages/read` permissions available to the user.", err)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load Quota for %s", region)
+		}
+		client, err := ic.Azure.Client()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create Azure client")
+		}
+		sizes, err := azure.VMSizes(ctx, client, region, masters, workers)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load virtual machine sizes for %s", region)
+		}
+		quotas, constraints = q, azure.Constraints(ic.Config, masters, workers, sizes)
+		if scaleOut {
+			constraints = azure.ComputeConstraints(ic.Config, workers, sizes)
+		}
+	case typesvsphere.Name:
+		p := ic.Config.Platform.VSphere
+		client, _, err := typesvsphere.CreateVSphereClients(ctx, p.VCenter, p.Username, p.Password)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to connect to vCenter")
+		}
+		q, err := quotavsphere.Load(ctx, client, p)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the vSphere capacity")
+		}
+		quotas, constraints = q, vsphere.Constraints(masters, workers)
+	case typesovirt.Name:
+		con, err := ovirtconfig.NewConnection()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to connect to oVirt")
+		}
+		defer con.Close()
+		q, err := quotaovirt.Load(con, ic.Config.Platform.Ovirt)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the oVirt capacity")
+		}
+		quotas, constraints = q, ovirt.Constraints(masters, workers)
+	case baremetal.Name, libvirt.Name, none.Name, kubevirt.Name:
 		// no special provisioning requirements to check
+		return nil, nil
 	default:
-		err = fmt.Errorf("unknown platform type %q", platform)
+		return nil, fmt.Errorf("unknown platform type %q", platform)
+	}
+	// The result of each constraint is in the reports.
+	reports, _ := policy.Check(quotas, constraints)
+	return reports, nil
+}
+
+// Verify returns an error describing the constraints that are not available,
+// or whose quota is unknown when the policy fails them, and warns about the
+// constraints that leave little quota.
+func Verify(reports []quota.ConstraintReport, policy *quota.Policy) error {
+	for _, report := range reports {
+		if report.Result == quota.NotAvailable || report.Result == quota.Unknown {
+			return summarizeFailingReport(reports, policy)
+		}
 	}
-	return err
+	summarizeReport(reports)
+	return nil
 }
 
 // Name returns the human-friendly name of the asset.
@@ -145,7 +250,7 @@ func (a *PlatformQuotaCheck) Name() string {
 }
 
 // summarizeFailingReport summarizes a report when there are failing constraints.
-func summarizeFailingReport(reports []quota.ConstraintReport) error {
+func summarizeFailingReport(reports []quota.ConstraintReport, policy *quota.Policy) error {
 	var notavailable []string
 	var unknown []string
 	var regionMessage string
@@ -165,14 +270,17 @@ func summarizeFailingReport(reports []quota.ConstraintReport) error {
 		}
 	}
 
-	if len(notavailable) == 0 && len(unknown) > 0 {
+	if len(notavailable) == 0 && len(unknown) > 0 && !policy.FailsUnknown() {
 		// all quotas are missing information so warn and skip
 		logrus.Warnf("Failed to find information on quotas %s", strings.Join(unknown, ", "))
 		return nil
 	}
 
 	msg := strings.Join(notavailable, ", ")
-	if len(unknown) > 0 {
+	switch {
+	case len(unknown) > 0 && msg == "":
+		msg = fmt.Sprintf("could not find information on %s", strings.Join(unknown, ", "))
+	case len(unknown) > 0:
 		msg = fmt.Sprintf("%s, and could not find information on %s", msg, strings.Join(unknown, ", "))
 	}
 	return &diagnostics.Err{Reason: "MissingQuota", Message: msg}
@@ -0,0 +1,117 @@
+package quota
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"github.com/pkg/errors"
+)
+
+// AllWorkers is the Scale target of all the compute machine sets.
+const AllWorkers = "workers"
+
+// Scale is the planned size of the compute machine sets of a target: all of
+// them, those of a compute pool, or a single machine set.
+type Scale struct {
+	// Target is AllWorkers, the name of a compute pool or the name of a
+	// machine set.
+	Target string
+
+	// Replicas is the number of machines of the target once scaled. The
+	// machines of a pool are distributed across its machine sets the way
+	// the installer does.
+	Replicas int64
+
+	// Add is set when Replicas is the number of machines added to the
+	// target rather than its size.
+	Add bool
+}
+
+// ParseScale parses a Scale of the form TARGET=N, or TARGET=+N to add N
+// machines.
+func ParseScale(s string) (Scale, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return Scale{}, errors.Errorf("invalid scale %q, must be TARGET=N or TARGET=+N", s)
+	}
+	scale := Scale{Target: parts[0]}
+	value := parts[1]
+	if strings.HasPrefix(value, "+") {
+		scale.Add = true
+		value = value[1:]
+	}
+	replicas, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || replicas < 0 {
+		return Scale{}, errors.Errorf("invalid scale %q, the number of machines must be a non-negative integer", s)
+	}
+	scale.Replicas = replicas
+	return scale, nil
+}
+
+// ScaleOut returns the machine sets of the machines added to the compute
+// machine sets of the cluster with infraID by scales. The replicas of each
+// machine set returned are the number of machines added to it, machine sets
+// that do not grow are left out. Scales apply in order, so a later scale can
+// override part of an earlier one, e.g. a machine set of a pool.
+func ScaleOut(infraID string, workers []machineapi.MachineSet, scales []Scale) ([]machineapi.MachineSet, error) {
+	current := make(map[string]int64, len(workers))
+	for _, w := range workers {
+		current[w.Name] = int64(*w.Spec.Replicas)
+	}
+
+	planned := make(map[string]int64, len(workers))
+	for name, replicas := range current {
+		planned[name] = replicas
+	}
+	for _, scale := range scales {
+		targets := scaleTargets(infraID, workers, scale.Target)
+		if len(targets) == 0 {
+			return nil, errors.Errorf("no compute machine set matches %q", scale.Target)
+		}
+		var total int64
+		if scale.Add {
+			for _, name := range targets {
+				total += planned[name]
+			}
+		}
+		total += scale.Replicas
+		for idx, name := range targets {
+			replicas := total / int64(len(targets))
+			if int64(idx) < total%int64(len(targets)) {
+				replicas++
+			}
+			planned[name] = replicas
+		}
+	}
+
+	var ret []machineapi.MachineSet
+	for _, w := range workers {
+		added := planned[w.Name] - current[w.Name]
+		if added <= 0 {
+			continue
+		}
+		scaled := w.DeepCopy()
+		replicas := int32(added)
+		scaled.Spec.Replicas = &replicas
+		ret = append(ret, *scaled)
+	}
+	return ret, nil
+}
+
+// scaleTargets returns the names of the machine sets of target, in the order
+// of workers, which is the order the installer distributes machines across
+// them.
+func scaleTargets(infraID string, workers []machineapi.MachineSet, target string) []string {
+	var names []string
+	for _, w := range workers {
+		switch {
+		case target == AllWorkers,
+			w.Name == target,
+			strings.HasPrefix(w.Name, fmt.Sprintf("%s-%s-", infraID, target)):
+			names = append(names, w.Name)
+		}
+	}
+	return names
+}
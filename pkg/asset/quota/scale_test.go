@@ -0,0 +1,108 @@
+package quota
+
+import (
+	"testing"
+
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScale(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Scale
+		err      string
+	}{{
+		input:    "workers=12",
+		expected: Scale{Target: "workers", Replicas: 12},
+	}, {
+		input:    "infra-abc-worker-us-east-1a=+3",
+		expected: Scale{Target: "infra-abc-worker-us-east-1a", Replicas: 3, Add: true},
+	}, {
+		input: "workers",
+		err:   `invalid scale "workers", must be TARGET=N or TARGET=+N`,
+	}, {
+		input: "=3",
+		err:   `invalid scale "=3", must be TARGET=N or TARGET=+N`,
+	}, {
+		input: "workers=-1",
+		err:   `invalid scale "workers=-1", the number of machines must be a non-negative integer`,
+	}, {
+		input: "workers=many",
+		err:   `invalid scale "workers=many", the number of machines must be a non-negative integer`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			scale, err := ParseScale(tc.input)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, scale)
+		})
+	}
+}
+
+func machineSet(name string, replicas int32) machineapi.MachineSet {
+	ms := machineapi.MachineSet{}
+	ms.Name = name
+	ms.Spec.Replicas = &replicas
+	return ms
+}
+
+func TestScaleOut(t *testing.T) {
+	workers := []machineapi.MachineSet{
+		machineSet("infra-worker-a", 1),
+		machineSet("infra-worker-b", 1),
+		machineSet("infra-worker-c", 1),
+		machineSet("infra-gpu-a", 0),
+	}
+	cases := []struct {
+		name   string
+		scales []Scale
+
+		expected map[string]int32
+		err      string
+	}{{
+		name:     "all workers",
+		scales:   []Scale{{Target: AllWorkers, Replicas: 12}},
+		expected: map[string]int32{"infra-worker-a": 2, "infra-worker-b": 2, "infra-worker-c": 2, "infra-gpu-a": 3},
+	}, {
+		name:     "pool",
+		scales:   []Scale{{Target: "worker", Replicas: 8}},
+		expected: map[string]int32{"infra-worker-a": 2, "infra-worker-b": 2, "infra-worker-c": 1},
+	}, {
+		name:     "added machines",
+		scales:   []Scale{{Target: "worker", Replicas: 2, Add: true}},
+		expected: map[string]int32{"infra-worker-a": 1, "infra-worker-b": 1},
+	}, {
+		name:     "machine set override",
+		scales:   []Scale{{Target: "worker", Replicas: 6}, {Target: "infra-worker-c", Replicas: 1}, {Target: "gpu", Replicas: 2}},
+		expected: map[string]int32{"infra-worker-a": 1, "infra-worker-b": 1, "infra-gpu-a": 2},
+	}, {
+		name:     "scale down",
+		scales:   []Scale{{Target: "worker", Replicas: 1}},
+		expected: map[string]int32{},
+	}, {
+		name:   "unknown target",
+		scales: []Scale{{Target: "infra", Replicas: 1}},
+		err:    `no compute machine set matches "infra"`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			added, err := ScaleOut("infra", workers, tc.scales)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			actual := map[string]int32{}
+			for _, w := range added {
+				actual[w.Name] = *w.Spec.Replicas
+			}
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, int32(1), *workers[0].Spec.Replicas, "the machine sets of the cluster must not change")
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package vsphere
+
+import (
+	machineapi "github.com/openshift/machine-api-operator/pkg/apis/machine/v1beta1"
+	vsphereapis "github.com/openshift/machine-api-operator/pkg/apis/vsphereprovider/v1beta1"
+
+	"github.com/openshift/installer/pkg/quota"
+	quotavsphere "github.com/openshift/installer/pkg/quota/vsphere"
+)
+
+// Constraints returns a list of quota constraints based on the machines.
+// These constraints can be used to check if there is enough capacity in the
+// vSphere cluster and datastore for creating a cluster for the install config.
+func Constraints(controlPlanes []machineapi.Machine, computes []machineapi.MachineSet) []quota.Constraint {
+	vcpus := quota.Constraint{Name: quotavsphere.VCPUs}
+	memory := quota.Constraint{Name: quotavsphere.Memory}
+	datastore := quota.Constraint{Name: quotavsphere.Datastore}
+	add := func(spec *vsphereapis.VSphereMachineProviderSpec, count int64) {
+		vcpus.Count += int64(spec.NumCPUs) * count
+		memory.Count += spec.MemoryMiB * count
+		datastore.Count += int64(spec.DiskGiB) * count
+	}
+	for _, m := range controlPlanes {
+		add(m.Spec.ProviderSpec.Value.Object.(*vsphereapis.VSphereMachineProviderSpec), 1)
+	}
+	for _, w := range computes {
+		add(w.Spec.Template.Spec.ProviderSpec.Value.Object.(*vsphereapis.VSphereMachineProviderSpec), int64(*w.Spec.Replicas))
+	}
+	return []quota.Constraint{datastore, memory, vcpus}
+}
@@ -0,0 +1,419 @@
+package releaseimage
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	dockerref "github.com/containers/image/docker/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/diagnostics"
+	"github.com/openshift/installer/pkg/registry"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// imageReferencesPath is the path, in the release image, of the image stream
+// listing the images of the release.
+const imageReferencesPath = "release-manifests/image-references"
+
+// MirrorResult is the result of the check of a mirror.
+type MirrorResult string
+
+const (
+	// MirrorComplete is the result of the mirrors holding all the images of
+	// the release in their source.
+	MirrorComplete MirrorResult = "Complete"
+
+	// MirrorIncomplete is the result of the mirrors missing images of the
+	// release in their source.
+	MirrorIncomplete MirrorResult = "Incomplete"
+
+	// MirrorUnreachable is the result of the mirrors that could not be
+	// queried.
+	MirrorUnreachable MirrorResult = "Unreachable"
+
+	// NotMirrored is the result of the images of the release in no image
+	// content source, which are pulled from their repository.
+	NotMirrored MirrorResult = "NotMirrored"
+)
+
+// MirrorReport reports the images of the release found in a mirror of an
+// image content source.
+type MirrorReport struct {
+	// Source is the source of the mirror, or the repository of the images
+	// that are not mirrored.
+	Source string
+
+	// Mirror is the mirror, empty for the images that are not mirrored.
+	Mirror string
+
+	// Images are the images of the release in the source.
+	Images []string
+
+	// Missing are the images the mirror does not have.
+	Missing []string
+
+	// Err is the error that stopped the check of the mirror.
+	Err error
+}
+
+// Result returns the result of the check of the mirror.
+func (r *MirrorReport) Result() MirrorResult {
+	switch {
+	case r.Mirror == "":
+		return NotMirrored
+	case r.Err != nil:
+		return MirrorUnreachable
+	case len(r.Missing) > 0:
+		return MirrorIncomplete
+	}
+	return MirrorComplete
+}
+
+// MirrorCheck is an asset that checks that the mirrors of the image content
+// sources of the install config hold the release image and the images it
+// references.
+type MirrorCheck struct {
+}
+
+var _ asset.Asset = (*MirrorCheck)(nil)
+
+// Dependencies returns the dependencies of MirrorCheck.
+func (a *MirrorCheck) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&Image{},
+	}
+}
+
+// Generate checks the mirrors of the image content sources.
+func (a *MirrorCheck) Generate(dependencies asset.Parents) error {
+	ic := &installconfig.InstallConfig{}
+	image := &Image{}
+	dependencies.Get(ic, image)
+
+	if len(ic.Config.ImageContentSources) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Minute)
+	defer cancel()
+	reports, err := MirrorReports(ctx, ic.Config, image.PullSpec)
+	if errors.Cause(err) == registry.ErrNotFound {
+		return &diagnostics.Err{
+			Reason:  "MissingMirrorContent",
+			Message: err.Error(),
+		}
+	}
+	if err != nil {
+		// The mirrors may only be reachable from the cluster.
+		logrus.Warnf("Failed to check the mirrors of the release image: %v", err)
+		return nil
+	}
+	return VerifyMirrors(reports)
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *MirrorCheck) Name() string {
+	return "Mirror Content Check"
+}
+
+// MirrorReports looks up the release image of pullSpec, and the images it
+// references, in the mirrors of the image content sources of config. The
+// registries are queried with the pull secret and the additional trust
+// bundle of config. There is a report for every mirror, in order, followed
+// by the reports of the images in no source by repository.
+func MirrorReports(ctx context.Context, config *types.InstallConfig, pullSpec string) ([]MirrorReport, error) {
+	ref, err := dockerref.ParseNamed(pullSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse release-image pull spec")
+	}
+	client, err := registry.NewClient(config.PullSecret, config.AdditionalTrustBundle)
+	if err != nil {
+		return nil, err
+	}
+	architecture := types.ArchitectureAMD64
+	if config.ControlPlane != nil && config.ControlPlane.Architecture != "" {
+		architecture = string(config.ControlPlane.Architecture)
+	}
+	images, err := releaseImages(ctx, client, ref, config.ImageContentSources, architecture)
+	if err != nil {
+		return nil, err
+	}
+	return checkMirrors(ctx, client, images, config.ImageContentSources)
+}
+
+// VerifyMirrors returns an error describing the sources with images of the
+// release in none of their mirrors, and warns about the mirrors missing
+// images found in other mirrors, the mirrors that could not be checked and
+// the images that are not mirrored.
+func VerifyMirrors(reports []MirrorReport) error {
+	var sources []string
+	bySource := map[string][]*MirrorReport{}
+	for i := range reports {
+		report := &reports[i]
+		switch report.Result() {
+		case NotMirrored:
+			logrus.Warnf("%d images of the release are in no image content source, they are pulled from %s", len(report.Images), report.Source)
+			continue
+		case MirrorUnreachable:
+			logrus.Warnf("Failed to check the mirror %s of %s: %v", report.Mirror, report.Source, report.Err)
+		}
+		if _, ok := bySource[report.Source]; !ok {
+			sources = append(sources, report.Source)
+		}
+		bySource[report.Source] = append(bySource[report.Source], report)
+	}
+
+	var failures []string
+	for _, source := range sources {
+		mirrors := bySource[source]
+		found := map[string]bool{}
+		unreachable := false
+		for _, report := range mirrors {
+			if report.Err != nil {
+				unreachable = true
+				continue
+			}
+			missing := map[string]bool{}
+			for _, image := range report.Missing {
+				missing[image] = true
+			}
+			for _, image := range report.Images {
+				if !missing[image] {
+					found[image] = true
+				}
+			}
+		}
+		images := mirrors[0].Images
+		if lost := len(images) - len(found); lost > 0 {
+			// The unreachable mirrors may have the images.
+			if unreachable {
+				logrus.Warnf("%d of the %d images of the release from %s are missing from the mirrors that could be checked", lost, len(images), source)
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("%d of the %d images of the release from %s are missing from all of its mirrors", lost, len(images), source))
+			continue
+		}
+		for _, report := range mirrors {
+			if report.Result() == MirrorIncomplete {
+				logrus.Warnf("The mirror %s of %s is missing %d of the %d images of the release, they are pulled from its other mirrors", report.Mirror, source, len(report.Missing), len(images))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return &diagnostics.Err{
+			Reason:  "MissingMirrorContent",
+			Message: strings.Join(failures, "; ") + ", run 'openshift-install check mirrors' to list them",
+		}
+	}
+	return nil
+}
+
+// checkMirrors looks up the images in the mirrors of their sources.
+func checkMirrors(ctx context.Context, client *registry.Client, images []dockerref.Named, sources []types.ImageContentSource) ([]MirrorReport, error) {
+	var reports []MirrorReport
+	index := map[string]int{}
+	for _, source := range sources {
+		for _, mirror := range source.Mirrors {
+			key := source.Source + " " + mirror
+			if _, ok := index[key]; !ok {
+				index[key] = len(reports)
+				reports = append(reports, MirrorReport{Source: source.Source, Mirror: mirror})
+			}
+		}
+	}
+
+	var unmirrored []MirrorReport
+	unmirroredIndex := map[string]int{}
+	for _, image := range images {
+		mirrored := false
+		checked := map[int]bool{}
+		for _, source := range sources {
+			if !registry.InSource(image, source.Source) {
+				continue
+			}
+			mirrored = true
+			for _, mirror := range source.Mirrors {
+				i := index[source.Source+" "+mirror]
+				if checked[i] {
+					continue
+				}
+				checked[i] = true
+				report := &reports[i]
+				report.Images = append(report.Images, image.String())
+				if report.Err != nil {
+					continue
+				}
+				location, err := registry.MirrorOf(image, source.Source, mirror)
+				if err != nil {
+					return nil, err
+				}
+				exists, err := client.ManifestExists(ctx, location)
+				if err != nil {
+					report.Err = err
+					continue
+				}
+				if !exists {
+					report.Missing = append(report.Missing, image.String())
+				}
+			}
+		}
+		if !mirrored {
+			i, ok := unmirroredIndex[image.Name()]
+			if !ok {
+				i = len(unmirrored)
+				unmirroredIndex[image.Name()] = i
+				unmirrored = append(unmirrored, MirrorReport{Source: image.Name()})
+			}
+			unmirrored[i].Images = append(unmirrored[i].Images, image.String())
+		}
+	}
+	return append(reports, unmirrored...), nil
+}
+
+// manifestList is a manifest list or an image index.
+type manifestList struct {
+	Manifests []struct {
+		Digest   digest.Digest `json:"digest"`
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+// imageManifest is a schema 2 or OCI image manifest.
+type imageManifest struct {
+	Layers []struct {
+		Digest digest.Digest `json:"digest"`
+	} `json:"layers"`
+}
+
+// imageReferences is the image stream of the images of a release.
+type imageReferences struct {
+	Spec struct {
+		Tags []struct {
+			Name string `json:"name"`
+			From struct {
+				Name string `json:"name"`
+			} `json:"from"`
+		} `json:"tags"`
+	} `json:"spec"`
+}
+
+// releaseImages returns the release image of ref, pinned to its digest, and
+// the images it references. Manifest lists are read for the image of the
+// architecture.
+func releaseImages(ctx context.Context, client *registry.Client, ref dockerref.Named, sources []types.ImageContentSource, architecture string) ([]dockerref.Named, error) {
+	manifest, location, err := client.Resolve(ctx, ref, sources)
+	if err != nil {
+		return nil, err
+	}
+	release, err := dockerref.WithDigest(dockerref.TrimNamed(ref), manifest.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(manifest.MediaType, ".list.v2+json") || strings.HasSuffix(manifest.MediaType, ".index.v1+json") {
+		list := &manifestList{}
+		if err := json.Unmarshal(manifest.Data, list); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the manifest list of %s", release)
+		}
+		var image dockerref.Named
+		for _, m := range list.Manifests {
+			if m.Platform.OS == "linux" && m.Platform.Architecture == architecture {
+				if image, err = dockerref.WithDigest(dockerref.TrimNamed(location), m.Digest); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+		if image == nil {
+			return nil, errors.Errorf("the release image %s has no image for linux/%s", release, architecture)
+		}
+		if manifest, err = client.Manifest(ctx, image); err != nil {
+			return nil, err
+		}
+	}
+
+	m := &imageManifest{}
+	if err := json.Unmarshal(manifest.Data, m); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the manifest of %s", release)
+	}
+	// The payload of the release is in its top layer.
+	var references *imageReferences
+	for i := len(m.Layers) - 1; i >= 0 && references == nil; i-- {
+		if references, err = readImageReferences(ctx, client, location, m.Layers[i].Digest); err != nil {
+			return nil, errors.Wrapf(err, "failed to read the layer %s of %s", m.Layers[i].Digest, release)
+		}
+	}
+	if references == nil {
+		return nil, errors.Errorf("the release image %s has no %s", release, imageReferencesPath)
+	}
+
+	images := []dockerref.Named{release}
+	seen := map[string]bool{release.String(): true}
+	for _, tag := range references.Spec.Tags {
+		image, err := dockerref.ParseNormalizedNamed(tag.From.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid image of %s in the release image %s", tag.Name, release)
+		}
+		if !seen[image.String()] {
+			seen[image.String()] = true
+			images = append(images, image)
+		}
+	}
+	return images, nil
+}
+
+// readImageReferences returns the image references in the layer dgst of the
+// repository of ref, or nil when it does not hold them.
+func readImageReferences(ctx context.Context, client *registry.Client, ref dockerref.Named, dgst digest.Digest) (*imageReferences, error) {
+	blob, err := client.Blob(ctx, ref, dgst)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	br := bufio.NewReader(blob)
+	var layer io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		layer = gz
+	}
+
+	tr := tar.NewReader(layer)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if path.Clean(strings.TrimPrefix(header.Name, "./")) != imageReferencesPath {
+			continue
+		}
+		references := &imageReferences{}
+		if err := json.NewDecoder(tr).Decode(references); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", imageReferencesPath)
+		}
+		return references, nil
+	}
+}
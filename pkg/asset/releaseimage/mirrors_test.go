@@ -0,0 +1,172 @@
+package releaseimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/registry/registrytest"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// layer returns a gzipped tar layer holding the files.
+func layer(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestMirrorReports(t *testing.T) {
+	registry := registrytest.New()
+	defer registry.Close()
+
+	component := func(name string) []byte {
+		return []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json","annotations":{"name":%q}}`, name))
+	}
+	api := registry.AddManifest("mirror/art-dev", "", component("api"))
+	registry.AddManifest("backup/art-dev", "", component("api"))
+	etcd := registry.AddManifest("backup/art-dev", "", component("etcd"))
+
+	apiImage := registry.Host + "/ocp/art-dev@" + api.String()
+	etcdImage := registry.Host + "/ocp/art-dev@" + etcd.String()
+	toolImage := "quay.io/other/tool@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	references := fmt.Sprintf(`{"kind":"ImageStream","apiVersion":"image.openshift.io/v1","spec":{"tags":[{"name":"api","from":{"kind":"DockerImage","name":%q}},{"name":"etcd","from":{"kind":"DockerImage","name":%q}},{"name":"tool","from":{"kind":"DockerImage","name":%q}}]}}`, apiImage, etcdImage, toolImage)
+	base := registry.AddBlob("mirror/release", layer(t, map[string]string{"etc/os-release": "ID=rhel\n"}))
+	payload := registry.AddBlob("mirror/release", layer(t, map[string]string{
+		"release-manifests/release-metadata":   "{}",
+		"./release-manifests/image-references": references,
+	}))
+	release := registry.AddManifest("mirror/release", "4.7.0", []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json","layers":[{"digest":%q},{"digest":%q}]}`, base, payload)))
+	releaseImage := registry.Host + "/ocp/release@" + release.String()
+
+	releaseSource := types.ImageContentSource{
+		Source:  registry.Host + "/ocp/release",
+		Mirrors: []string{registry.Host + "/mirror/release"},
+	}
+	cases := []struct {
+		name    string
+		mirrors []string
+		reports []MirrorReport
+		results []MirrorResult
+		err     string
+	}{{
+		name:    "mirrored",
+		mirrors: []string{registry.Host + "/mirror/art-dev", registry.Host + "/backup/art-dev"},
+		reports: []MirrorReport{
+			{Source: registry.Host + "/ocp/release", Mirror: registry.Host + "/mirror/release", Images: []string{releaseImage}},
+			{Source: registry.Host + "/ocp/art-dev", Mirror: registry.Host + "/mirror/art-dev", Images: []string{apiImage, etcdImage}, Missing: []string{etcdImage}},
+			{Source: registry.Host + "/ocp/art-dev", Mirror: registry.Host + "/backup/art-dev", Images: []string{apiImage, etcdImage}},
+			{Source: "quay.io/other/tool", Images: []string{toolImage}},
+		},
+		results: []MirrorResult{MirrorComplete, MirrorIncomplete, MirrorComplete, NotMirrored},
+	}, {
+		name:    "missing",
+		mirrors: []string{registry.Host + "/mirror/art-dev"},
+		reports: []MirrorReport{
+			{Source: registry.Host + "/ocp/release", Mirror: registry.Host + "/mirror/release", Images: []string{releaseImage}},
+			{Source: registry.Host + "/ocp/art-dev", Mirror: registry.Host + "/mirror/art-dev", Images: []string{apiImage, etcdImage}, Missing: []string{etcdImage}},
+			{Source: "quay.io/other/tool", Images: []string{toolImage}},
+		},
+		results: []MirrorResult{MirrorComplete, MirrorIncomplete, NotMirrored},
+		err:     `^error\(MissingMirrorContent\): 1 of the 2 images of the release from .*/ocp/art-dev are missing from all of its mirrors, run 'openshift-install check mirrors' to list them$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &types.InstallConfig{
+				PullSecret:            registry.PullSecret,
+				AdditionalTrustBundle: registry.CA,
+				ImageContentSources: []types.ImageContentSource{releaseSource, {
+					Source:  registry.Host + "/ocp/art-dev",
+					Mirrors: tc.mirrors,
+				}},
+			}
+			reports, err := MirrorReports(context.Background(), config, registry.Host+"/ocp/release:4.7.0")
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.reports, reports)
+			var results []MirrorResult
+			for i := range reports {
+				results = append(results, reports[i].Result())
+			}
+			assert.Equal(t, tc.results, results)
+
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: config}, &Image{PullSpec: registry.Host + "/ocp/release:4.7.0"})
+			err = (&MirrorCheck{}).Generate(parents)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.err, err)
+			}
+		})
+	}
+
+	// The release image must be in a mirror, and the mirrors that cannot be
+	// reached may have it.
+	for _, tc := range []struct {
+		name     string
+		pullSpec string
+		mirror   string
+		err      string
+	}{{
+		name:     "empty mirror",
+		pullSpec: registry.Host + "/ocp/release:4.7.0",
+		mirror:   registry.Host + "/empty/release",
+		err:      `^error\(MissingMirrorContent\): failed to resolve .*/ocp/release:4.7.0: GET https://.*/v2/empty/release/manifests/4.7.0: not found; GET https://.*/v2/ocp/release/manifests/4.7.0: not found$`,
+	}, {
+		name:     "unreachable mirror",
+		pullSpec: "127.0.0.1:1/ocp/release:4.7.0",
+		mirror:   "127.0.0.1:1/mirror/release",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &types.InstallConfig{
+				PullSecret:            registry.PullSecret,
+				AdditionalTrustBundle: registry.CA,
+				ImageContentSources: []types.ImageContentSource{{
+					Source:  strings.TrimSuffix(tc.pullSpec, ":4.7.0"),
+					Mirrors: []string{tc.mirror},
+				}},
+			}
+			parents := asset.Parents{}
+			parents.Add(&installconfig.InstallConfig{Config: config}, &Image{PullSpec: tc.pullSpec})
+			err := (&MirrorCheck{}).Generate(parents)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.err, err)
+			}
+		})
+	}
+
+	// The mirrors that cannot be reached may have the images.
+	reports := []MirrorReport{
+		{Source: "quay.io/ocp/art-dev", Mirror: "mirror.example.com/art-dev", Images: []string{apiImage, etcdImage}, Missing: []string{etcdImage}},
+		{Source: "quay.io/ocp/art-dev", Mirror: "backup.example.com/art-dev", Images: []string{apiImage, etcdImage}, Err: fmt.Errorf("connection refused")},
+	}
+	assert.Equal(t, MirrorUnreachable, reports[1].Result())
+	assert.NoError(t, VerifyMirrors(reports))
+}
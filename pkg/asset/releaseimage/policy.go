@@ -0,0 +1,133 @@
+package releaseimage
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	policyFilename = "release-image-policy.yaml"
+
+	// PolicyEnvVar names the environment variable holding the path of the
+	// release image policy used when the asset directory has none.
+	PolicyEnvVar = "OPENSHIFT_INSTALL_RELEASE_IMAGE_POLICY"
+)
+
+// DefaultSignatureStores are the stores of the signatures of the OpenShift
+// releases.
+var DefaultSignatureStores = []string{
+	"https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release",
+	"https://storage.googleapis.com/openshift-release/official/signatures/openshift/release",
+}
+
+// VerificationConfig configures the verification of the release image.
+type VerificationConfig struct {
+	// KeyRing is the path of the OpenPGP key ring, armored or not, holding
+	// the keys trusted to sign the release image.
+	KeyRing string `json:"keyRing"`
+
+	// SignatureStores are the https:// or file:// URLs of the stores of the
+	// signatures, laid out as <store>/sha256=<digest>/signature-<n>. When
+	// unset, DefaultSignatureStores are used.
+	SignatureStores []string `json:"signatureStores,omitempty"`
+}
+
+// Validate returns an error when the configuration is not valid.
+func (c *VerificationConfig) Validate() error {
+	if c.KeyRing == "" {
+		return errors.New("keyRing is required")
+	}
+	for _, store := range c.SignatureStores {
+		u, err := url.Parse(store)
+		if err != nil {
+			return errors.Wrapf(err, "invalid signature store %q", store)
+		}
+		if u.Scheme != "https" && u.Scheme != "file" {
+			return errors.Errorf("invalid signature store %q, must be an https:// or file:// URL", store)
+		}
+	}
+	return nil
+}
+
+// Policy is the verification policy of the release image. It is read from
+// release-image-policy.yaml in the asset directory, or else from the file
+// named by OPENSHIFT_INSTALL_RELEASE_IMAGE_POLICY. Without it, the release
+// image is not verified.
+type Policy struct {
+	Config *VerificationConfig
+	File   *asset.File
+}
+
+var _ asset.WritableAsset = (*Policy)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (p *Policy) Name() string {
+	return "Release Image Policy"
+}
+
+// Dependencies returns no dependencies.
+func (p *Policy) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate reads the policy named by OPENSHIFT_INSTALL_RELEASE_IMAGE_POLICY.
+func (p *Policy) Generate(asset.Parents) error {
+	p.Config = nil
+	path := os.Getenv(PolicyEnvVar)
+	if path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read release image policy")
+	}
+	config, err := ParseVerificationConfig(data)
+	if err != nil {
+		return errors.Wrapf(err, "invalid release image policy %s", path)
+	}
+	p.Config = config
+	return nil
+}
+
+// Files returns the policy file when it was read from the asset directory.
+func (p *Policy) Files() []*asset.File {
+	if p.File != nil {
+		return []*asset.File{p.File}
+	}
+	return []*asset.File{}
+}
+
+// Load reads the policy from the asset directory.
+func (p *Policy) Load(f asset.FileFetcher) (found bool, err error) {
+	file, err := f.FetchByName(policyFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	config, err := ParseVerificationConfig(file.Data)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid %q file", policyFilename)
+	}
+	p.Config, p.File = config, file
+	return true, nil
+}
+
+// ParseVerificationConfig parses and validates a YAML release image policy.
+func ParseVerificationConfig(data []byte) (*VerificationConfig, error) {
+	config := &VerificationConfig{}
+	if err := yaml.UnmarshalStrict(data, config, yaml.DisallowUnknownFields); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -1,16 +1,22 @@
 package releaseimage
 
 import (
+	"context"
 	"os"
+	"time"
 
 	dockerref "github.com/containers/image/docker/reference"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/registry"
+	"github.com/openshift/installer/pkg/types"
 )
 
-// Image asset generates the release-image pullspec for the cluster
+// Image asset generates the release-image pullspec for the cluster. With a
+// release image policy, the pullspec is verified and pinned to its digest.
 type Image struct {
 	PullSpec   string
 	Repository string
@@ -20,11 +26,18 @@ var _ asset.Asset = (*Image)(nil)
 
 // Dependencies is the list of assets required to generate ReleaseImage.
 func (a *Image) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+		&Policy{},
+	}
 }
 
 // Generate creates the asset using the dependencies.
 func (a *Image) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	policy := &Policy{}
+	dependencies.Get(installConfig, policy)
+
 	var pullSpec string
 	if ri, ok := os.LookupEnv("OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE"); ok && ri != "" {
 		logrus.Warn("Found override for release image. Please be warned, this is not advised")
@@ -45,6 +58,46 @@ func (a *Image) Generate(dependencies asset.Parents) error {
 	}
 	a.Repository = ref.Name()
 
+	if policy.Config == nil {
+		return nil
+	}
+	return a.verify(installConfig.Config, policy.Config, ref)
+}
+
+// verify resolves the release image to a digest through the image content
+// sources, verifies the signatures of the digest with the key ring of the
+// policy and pins the pullspec to the digest.
+func (a *Image) verify(config *types.InstallConfig, policy *VerificationConfig, ref dockerref.Named) error {
+	keyRing, err := readKeyRing(policy.KeyRing)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the release image policy")
+	}
+	client, err := registry.NewClient(config.PullSecret, config.AdditionalTrustBundle)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Minute)
+	defer cancel()
+	manifest, location, err := client.Resolve(ctx, ref, config.ImageContentSources)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify the release image")
+	}
+	stores := policy.SignatureStores
+	if len(stores) == 0 {
+		stores = DefaultSignatureStores
+	}
+	identity, err := verifySignatures(ctx, client, stores, keyRing, ref, manifest.Digest)
+	if err != nil {
+		return errors.Wrapf(err, "failed to verify the release image %s", ref)
+	}
+
+	pinned, err := dockerref.WithDigest(dockerref.TrimNamed(ref), manifest.Digest)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Verified the release image %s from %s, signed as %s", pinned, location, identity)
+	a.PullSpec = pinned.String()
 	return nil
 }
 
@@ -0,0 +1,198 @@
+package releaseimage
+
+import (
+	"bytes"
+	"crypto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dockerref "github.com/containers/image/docker/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/registry/registrytest"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// sign returns an atomic container signature of dgst for identity by signer.
+func sign(t *testing.T, signer *openpgp.Entity, identity string, dgst digest.Digest) []byte {
+	var buf bytes.Buffer
+	w, err := openpgp.Sign(&buf, signer, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := `{"critical":{"type":"atomic container signature","image":{"docker-manifest-digest":"` + dgst.String() + `"},"identity":{"docker-reference":"` + identity + `"}},"optional":{}}`
+	if _, err := w.Write([]byte(payload)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// writeKeyRing writes the armored public key of entity to a file in dir.
+func writeKeyRing(t *testing.T, dir string, entity *openpgp.Entity) string {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	path := filepath.Join(dir, "pubring.asc")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImageVerification(t *testing.T) {
+	config := &packet.Config{DefaultHash: crypto.SHA256}
+	signer, err := openpgp.NewEntity("release", "", "release@example.com", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stranger, err := openpgp.NewEntity("stranger", "", "stranger@example.com", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "releaseimage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyRing := writeKeyRing(t, dir, signer)
+
+	registry := registrytest.New()
+	defer registry.Close()
+	dgst := registry.AddManifest("mirror/release", "4.7.0", []byte(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json"}`))
+	otherDigest := digest.FromString("other")
+	release := "quay.io/openshift-release-dev/ocp-release:4.7.0"
+	registry.AddFile("/signatures/sha256="+dgst.Hex()+"/signature-1", sign(t, stranger, release, dgst))
+	registry.AddFile("/other-tag/sha256="+dgst.Hex()+"/signature-1", sign(t, signer, "quay.io/openshift-release-dev/ocp-release:4.6.0", dgst))
+
+	fileStore := filepath.Join(dir, "signatures")
+	if err := os.MkdirAll(filepath.Join(fileStore, "sha256="+dgst.Hex()), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(fileStore, "sha256="+dgst.Hex(), "signature-1"), sign(t, signer, release, dgst), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	installConfig := &installconfig.InstallConfig{Config: &types.InstallConfig{
+		PullSecret:            registry.PullSecret,
+		AdditionalTrustBundle: registry.CA,
+		ImageContentSources: []types.ImageContentSource{{
+			Source:  "quay.io/openshift-release-dev/ocp-release",
+			Mirrors: []string{registry.Host + "/mirror/release"},
+		}},
+	}}
+	os.Setenv("OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE", "quay.io/openshift-release-dev/ocp-release:4.7.0")
+	defer os.Unsetenv("OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE")
+
+	cases := []struct {
+		name     string
+		policy   *VerificationConfig
+		pullSpec string
+		err      string
+	}{{
+		name:     "no policy",
+		pullSpec: "quay.io/openshift-release-dev/ocp-release:4.7.0",
+	}, {
+		name:     "signed",
+		policy:   &VerificationConfig{KeyRing: keyRing, SignatureStores: []string{registry.URL + "/signatures", "file://" + fileStore}},
+		pullSpec: "quay.io/openshift-release-dev/ocp-release@" + dgst.String(),
+	}, {
+		name:   "signed by an unknown key",
+		policy: &VerificationConfig{KeyRing: keyRing, SignatureStores: []string{registry.URL + "/signatures"}},
+		err:    `^failed to verify the release image quay.io/openshift-release-dev/ocp-release:4.7.0: no signature of sha256:[0-9a-f]{64} verified: https://.*/signature-1: signed by the unknown key [0-9A-F]+$`,
+	}, {
+		name:   "signed for another tag",
+		policy: &VerificationConfig{KeyRing: keyRing, SignatureStores: []string{registry.URL + "/other-tag"}},
+		err:    `^failed to verify the release image quay.io/openshift-release-dev/ocp-release:4.7.0: no signature of sha256:[0-9a-f]{64} verified: https://.*/signature-1: signed for quay.io/openshift-release-dev/ocp-release:4.6.0, not quay.io/openshift-release-dev/ocp-release:4.7.0$`,
+	}, {
+		name:   "no signatures",
+		policy: &VerificationConfig{KeyRing: keyRing, SignatureStores: []string{registry.URL + "/missing"}},
+		err:    `^failed to verify the release image quay.io/openshift-release-dev/ocp-release:4.7.0: no signature of sha256:[0-9a-f]{64} found in https://.*/missing$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			parents.Add(installConfig, &Policy{Config: tc.policy})
+			image := &Image{}
+			err := image.Generate(parents)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.pullSpec, image.PullSpec)
+				assert.Equal(t, "quay.io/openshift-release-dev/ocp-release", image.Repository)
+			}
+		})
+	}
+
+	// The signature must be for the digest of the release image.
+	ref, err := dockerref.ParseNamed(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = verifySignature(openpgp.EntityList{signer}, sign(t, signer, release, otherDigest), ref, dgst)
+	assert.EqualError(t, err, "the signature is for "+otherDigest.String())
+
+	// A release requested by digest may be signed for any tag of its
+	// repository.
+	pinned, err := dockerref.ParseNamed("quay.io/openshift-release-dev/ocp-release@" + dgst.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity, err := verifySignature(openpgp.EntityList{signer}, sign(t, signer, "quay.io/openshift-release-dev/ocp-release:4.6.0", dgst), pinned, dgst)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "quay.io/openshift-release-dev/ocp-release:4.6.0", identity)
+	}
+	_, err = verifySignature(openpgp.EntityList{signer}, sign(t, signer, "quay.io/other/release:4.7.0", dgst), pinned, dgst)
+	assert.EqualError(t, err, "signed for quay.io/other/release:4.7.0, not quay.io/openshift-release-dev/ocp-release@"+dgst.String())
+}
+
+func TestParseVerificationConfig(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		err  string
+	}{{
+		name: "valid",
+		data: "keyRing: pubring.gpg\nsignatureStores:\n- https://example.com/signatures\n- file:///srv/signatures\n",
+	}, {
+		name: "no key ring",
+		data: "signatureStores:\n- https://example.com/signatures\n",
+		err:  "keyRing is required",
+	}, {
+		name: "insecure store",
+		data: "keyRing: pubring.gpg\nsignatureStores:\n- http://example.com/signatures\n",
+		err:  `invalid signature store "http://example.com/signatures", must be an https:// or file:// URL`,
+	}, {
+		name: "unknown field",
+		data: "keyRing: pubring.gpg\nkeys: []\n",
+		err:  `failed to unmarshal: error unmarshaling JSON: while decoding JSON: json: unknown field "keys"`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseVerificationConfig([]byte(tc.data))
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package releaseimage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dockerref "github.com/containers/image/docker/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/openshift/installer/pkg/registry"
+)
+
+const (
+	// maxSignatures is the number of signatures looked up per store.
+	maxSignatures = 16
+
+	// signatureType is the type of the atomic container signatures of the
+	// releases.
+	signatureType = "atomic container signature"
+)
+
+// signature is the payload of an atomic container signature.
+type signature struct {
+	Critical struct {
+		Type  string `json:"type"`
+		Image struct {
+			DockerManifestDigest digest.Digest `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+	} `json:"critical"`
+}
+
+// readKeyRing reads an armored or binary OpenPGP key ring.
+func readKeyRing(path string) (openpgp.EntityList, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the key ring")
+	}
+	keyRing, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		keyRing, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the key ring %s", path)
+	}
+	if len(keyRing) == 0 {
+		return nil, errors.Errorf("the key ring %s has no keys", path)
+	}
+	return keyRing, nil
+}
+
+// verifySignatures returns the identity of the first signature of dgst in
+// the stores signed by a key of keyRing for ref.
+func verifySignatures(ctx context.Context, client *registry.Client, stores []string, keyRing openpgp.EntityList, ref dockerref.Named, dgst digest.Digest) (string, error) {
+	var failures []string
+	for _, store := range stores {
+		for i := 1; i <= maxSignatures; i++ {
+			location := fmt.Sprintf("%s/%s=%s/signature-%d", strings.TrimSuffix(store, "/"), dgst.Algorithm(), dgst.Hex(), i)
+			data, err := fetchSignature(ctx, client, location)
+			if err != nil {
+				if errors.Cause(err) != registry.ErrNotFound && !os.IsNotExist(err) {
+					failures = append(failures, err.Error())
+				}
+				break
+			}
+			identity, err := verifySignature(keyRing, data, ref, dgst)
+			if err == nil {
+				return identity, nil
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", location, err))
+		}
+	}
+	if len(failures) == 0 {
+		return "", errors.Errorf("no signature of %s found in %s", dgst, strings.Join(stores, ", "))
+	}
+	return "", errors.Errorf("no signature of %s verified: %s", dgst, strings.Join(failures, "; "))
+}
+
+// fetchSignature reads the signature at location, an https:// or file:// URL.
+func fetchSignature(ctx context.Context, client *registry.Client, location string) ([]byte, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "file" {
+		return ioutil.ReadFile(filepath.FromSlash(u.Path))
+	}
+	return client.Get(ctx, location)
+}
+
+// verifySignature returns the identity of the signature data when it is
+// signed by a key of keyRing for ref at dgst.
+func verifySignature(keyRing openpgp.EntityList, data []byte, ref dockerref.Named, dgst digest.Digest) (string, error) {
+	md, err := openpgp.ReadMessage(bytes.NewReader(data), keyRing, nil, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the signature")
+	}
+	if !md.IsSigned {
+		return "", errors.New("the message is not signed")
+	}
+	if md.SignedBy == nil {
+		return "", errors.Errorf("signed by the unknown key %X", md.SignedByKeyId)
+	}
+	payload, err := ioutil.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the signed payload")
+	}
+	// The signature is checked once the body is read.
+	if md.SignatureError != nil {
+		return "", errors.Wrap(md.SignatureError, "invalid signature")
+	}
+
+	s := &signature{}
+	if err := json.Unmarshal(payload, s); err != nil {
+		return "", errors.Wrap(err, "failed to parse the signed payload")
+	}
+	if s.Critical.Type != signatureType {
+		return "", errors.Errorf("unsupported signature type %q", s.Critical.Type)
+	}
+	if s.Critical.Image.DockerManifestDigest != dgst {
+		return "", errors.Errorf("the signature is for %s", s.Critical.Image.DockerManifestDigest)
+	}
+	if err := matchIdentity(s.Critical.Identity.DockerReference, ref); err != nil {
+		return "", err
+	}
+	return s.Critical.Identity.DockerReference, nil
+}
+
+// matchIdentity returns an error unless identity, the reference a signature
+// is for, is ref or, when ref is pinned to a digest, is in its repository.
+// This is the matchRepoDigestOrExact policy of containers/image: a release
+// requested by tag is only accepted when it was signed for that tag, so a
+// mirror cannot substitute another signed release.
+func matchIdentity(identity string, ref dockerref.Named) error {
+	signed, err := dockerref.ParseNormalizedNamed(identity)
+	if err != nil {
+		return errors.Wrapf(err, "invalid signed identity %q", identity)
+	}
+	if _, ok := ref.(dockerref.Digested); ok {
+		if signed.Name() == ref.Name() {
+			return nil
+		}
+	} else if dockerref.TagNameOnly(signed).String() == dockerref.TagNameOnly(ref).String() {
+		return nil
+	}
+	return errors.Errorf("signed for %s, not %s", identity, ref)
+}
@@ -1,11 +1,16 @@
 package asset
 
+import (
+	"context"
+)
+
 // Store is a store for the states of assets.
 type Store interface {
 	// Fetch retrieves the state of the given asset, generating it and its
 	// dependencies if necessary. When purging consumed assets, none of the
-	// assets in assetsToPreserve will be purged.
-	Fetch(assetToFetch Asset, assetsToPreserve ...WritableAsset) error
+	// assets in assetsToPreserve will be purged. Assets implementing
+	// ContextAsset are generated with the given context.
+	Fetch(ctx context.Context, assetToFetch Asset, assetsToPreserve ...WritableAsset) error
 
 	// Destroy removes the asset from all its internal state and also from
 	// disk if possible.
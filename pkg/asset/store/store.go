@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/metrics/gatherer"
 )
 
 const (
@@ -73,8 +75,8 @@ func newStore(dir string) (*storeImpl, error) {
 // Fetch retrieves the state of the given asset, generating it and its
 // dependencies if necessary. When purging consumed assets, none of the
 // assets in preserved will be purged.
-func (s *storeImpl) Fetch(a asset.Asset, preserved ...asset.WritableAsset) error {
-	if err := s.fetch(a, ""); err != nil {
+func (s *storeImpl) Fetch(ctx context.Context, a asset.Asset, preserved ...asset.WritableAsset) error {
+	if err := s.fetch(ctx, a, ""); err != nil {
 		return err
 	}
 	if err := s.saveStateFile(); err != nil {
@@ -128,21 +130,33 @@ func (s *storeImpl) DestroyState() error {
 // loadStateFile retrieves the state from the state file present in the given directory
 // and returns the assets map
 func (s *storeImpl) loadStateFile() error {
-	path := filepath.Join(s.directory, stateFileName)
+	assets, err := StateFileAssets(s.directory)
+	if err != nil {
+		return err
+	}
+	if assets != nil {
+		s.stateFileAssets = assets
+	}
+	return nil
+}
+
+// StateFileAssets returns the assets of the state file in the given directory,
+// by the type of the asset, or nil when there is no state file.
+func StateFileAssets(dir string) (map[string]json.RawMessage, error) {
+	path := filepath.Join(dir, stateFileName)
 	assets := map[string]json.RawMessage{}
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 	err = json.Unmarshal(data, &assets)
 	if err != nil {
-		return errors.Wrapf(err, "failed to unmarshal state file %q", path)
+		return nil, errors.Wrapf(err, "failed to unmarshal state file %q", path)
 	}
-	s.stateFileAssets = assets
-	return nil
+	return assets, nil
 }
 
 // loadAssetFromState renders the asset object arguments from the state file contents.
@@ -193,7 +207,7 @@ func (s *storeImpl) saveStateFile() error {
 // fetch populates the given asset, generating it and its dependencies if
 // necessary, and returns whether or not the asset had to be regenerated and
 // any errors.
-func (s *storeImpl) fetch(a asset.Asset, indent string) error {
+func (s *storeImpl) fetch(ctx context.Context, a asset.Asset, indent string) error {
 	logrus.Debugf("%sFetching %s...", indent, a.Name())
 
 	assetState, ok := s.assets[reflect.TypeOf(a)]
@@ -218,15 +232,22 @@ func (s *storeImpl) fetch(a asset.Asset, indent string) error {
 	dependencies := a.Dependencies()
 	parents := make(asset.Parents, len(dependencies))
 	for _, d := range dependencies {
-		if err := s.fetch(d, increaseIndent(indent)); err != nil {
+		if err := s.fetch(ctx, d, increaseIndent(indent)); err != nil {
 			return errors.Wrapf(err, "failed to fetch dependency of %q", a.Name())
 		}
 		parents.Add(d)
 	}
 	logrus.Debugf("%sGenerating %s...", indent, a.Name())
-	if err := a.Generate(parents); err != nil {
+	var err error
+	if ca, ok := a.(asset.ContextAsset); ok {
+		err = ca.GenerateWithContext(ctx, parents)
+	} else {
+		err = a.Generate(parents)
+	}
+	if err != nil {
 		return errors.Wrapf(err, "failed to generate asset %q", a.Name())
 	}
+	gatherer.AddAssetGenerated(assetState.presentOnDisk || s.isAssetInState(a))
 	assetState.asset = a
 	assetState.source = generatedSource
 	return nil
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -287,7 +288,7 @@ func TestStoreFetch(t *testing.T) {
 					source: generatedSource,
 				}
 			}
-			err = store.Fetch(assets[tc.target])
+			err = store.Fetch(context.Background(), assets[tc.target])
 			assert.NoError(t, err, "error fetching asset")
 			assert.EqualValues(t, tc.expectedGenerationLog, generationLog)
 		})
@@ -381,7 +382,7 @@ func TestStoreFetchOnDiskAssets(t *testing.T) {
 			for _, name := range tc.onDiskAssets {
 				onDiskAssets[reflect.TypeOf(assets[name])] = true
 			}
-			err := store.fetch(assets[tc.target], "")
+			err := store.fetch(context.Background(), assets[tc.target], "")
 			assert.NoError(t, err, "unexpected error")
 			assert.EqualValues(t, tc.expectedGenerationLog, generationLog)
 			assert.Equal(t, tc.expectedDirty, store.assets[reflect.TypeOf(assets[tc.target])].anyParentsDirty)
@@ -405,7 +406,7 @@ func TestStoreFetchIdempotency(t *testing.T) {
 		}
 		assets := []asset.WritableAsset{&testStoreAssetA{}, &testStoreAssetB{}}
 		for _, a := range assets {
-			err = store.Fetch(a, assets...)
+			err = store.Fetch(context.Background(), a, assets...)
 			if !assert.NoError(t, err, "(loop %d) unexpected error fetching asset %q", a.Name()) {
 				t.Fatal()
 			}
@@ -10,6 +10,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/machines"
 	"github.com/openshift/installer/pkg/asset/manifests"
 	"github.com/openshift/installer/pkg/asset/password"
+	"github.com/openshift/installer/pkg/asset/quota"
 	"github.com/openshift/installer/pkg/asset/templates/content/bootkube"
 	"github.com/openshift/installer/pkg/asset/templates/content/openshift"
 	"github.com/openshift/installer/pkg/asset/tls"
@@ -21,6 +22,11 @@ var (
 		&installconfig.InstallConfig{},
 	}
 
+	// SigningRequests are the signing-requests targeted assets.
+	SigningRequests = []asset.WritableAsset{
+		&tls.SigningRequests{},
+	}
+
 	// Manifests are the manifests targeted assets.
 	Manifests = []asset.WritableAsset{
 		&machines.Master{},
@@ -66,6 +72,8 @@ var (
 		&machine.MasterIgnitionCustomizations{},
 		&machine.WorkerIgnitionCustomizations{},
 		&cluster.TerraformVariables{},
+		&cluster.TerraformOverrides{},
+		&quota.Policy{},
 		&kubeconfig.AdminClient{},
 		&password.KubeadminPassword{},
 		&tls.JournalCertKey{},
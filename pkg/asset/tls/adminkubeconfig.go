@@ -16,11 +16,18 @@ var _ asset.WritableAsset = (*AdminKubeConfigSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *AdminKubeConfigSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *AdminKubeConfigSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "admin-kubeconfig-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -28,7 +35,13 @@ func (c *AdminKubeConfigSignerCertKey) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "admin-kubeconfig-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "admin-kubeconfig-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *AdminKubeConfigSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "admin-kubeconfig-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -79,13 +92,15 @@ var _ asset.WritableAsset = (*AdminKubeConfigClientCertKey)(nil)
 func (a *AdminKubeConfigClientCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&AdminKubeConfigSignerCertKey{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *AdminKubeConfigClientCertKey) Generate(dependencies asset.Parents) error {
 	ca := &AdminKubeConfigSignerCertKey{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:admin", Organization: []string{"system:masters"}},
@@ -94,7 +109,7 @@ func (a *AdminKubeConfigClientCertKey) Generate(dependencies asset.Parents) erro
 		Validity:     ValidityTenYears,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "admin-kubeconfig-client", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "admin-kubeconfig-client", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -19,11 +19,18 @@ var _ asset.Asset = (*AggregatorCA)(nil)
 // the parent CA, and install config if it depends on the install config for
 // DNS names, etc.
 func (a *AggregatorCA) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *AggregatorCA) Generate(dependencies asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	dependencies.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "aggregator", OrganizationalUnit: []string{"bootkube"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -31,7 +38,13 @@ func (a *AggregatorCA) Generate(dependencies asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return a.SelfSignedCertKey.Generate(cfg, "aggregator-ca")
+	return a.SelfSignedCertKey.Generate(cfg, policy, externalCA, "aggregator-ca")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (a *AggregatorCA) Load(f asset.FileFetcher) (bool, error) {
+	return a.SelfSignedCertKey.load(f, "aggregator-ca")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -53,13 +66,15 @@ var _ asset.Asset = (*APIServerProxyCertKey)(nil)
 func (a *APIServerProxyCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&AggregatorCA{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *APIServerProxyCertKey) Generate(dependencies asset.Parents) error {
 	aggregatorCA := &AggregatorCA{}
-	dependencies.Get(aggregatorCA)
+	policy := &PKIPolicy{}
+	dependencies.Get(aggregatorCA, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:kube-apiserver-proxy", Organization: []string{"kube-master"}},
@@ -68,7 +83,7 @@ func (a *APIServerProxyCertKey) Generate(dependencies asset.Parents) error {
 		Validity:     ValidityOneDay,
 	}
 
-	return a.SignedCertKey.Generate(cfg, aggregatorCA, "apiserver-proxy", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, aggregatorCA, "apiserver-proxy", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -85,11 +100,18 @@ var _ asset.WritableAsset = (*AggregatorSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *AggregatorSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *AggregatorSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "aggregator-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -97,7 +119,13 @@ func (c *AggregatorSignerCertKey) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "aggregator-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "aggregator-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *AggregatorSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "aggregator-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -146,13 +174,15 @@ var _ asset.Asset = (*AggregatorClientCertKey)(nil)
 func (a *AggregatorClientCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&AggregatorSignerCertKey{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *AggregatorClientCertKey) Generate(dependencies asset.Parents) error {
 	ca := &AggregatorSignerCertKey{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:kube-apiserver-proxy", Organization: []string{"kube-master"}},
@@ -161,7 +191,7 @@ func (a *AggregatorClientCertKey) Generate(dependencies asset.Parents) error {
 		Validity:     ValidityOneDay,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "aggregator-client", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "aggregator-client", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -19,11 +19,18 @@ var _ asset.WritableAsset = (*KubeAPIServerToKubeletSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *KubeAPIServerToKubeletSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeAPIServerToKubeletSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-apiserver-to-kubelet-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -31,7 +38,13 @@ func (c *KubeAPIServerToKubeletSignerCertKey) Generate(parents asset.Parents) er
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-apiserver-to-kubelet-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "kube-apiserver-to-kubelet-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *KubeAPIServerToKubeletSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "kube-apiserver-to-kubelet-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -80,13 +93,15 @@ var _ asset.Asset = (*KubeAPIServerToKubeletClientCertKey)(nil)
 func (a *KubeAPIServerToKubeletClientCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&KubeAPIServerToKubeletSignerCertKey{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *KubeAPIServerToKubeletClientCertKey) Generate(dependencies asset.Parents) error {
 	ca := &KubeAPIServerToKubeletSignerCertKey{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:kube-apiserver", Organization: []string{"kube-master"}},
@@ -95,7 +110,7 @@ func (a *KubeAPIServerToKubeletClientCertKey) Generate(dependencies asset.Parent
 		Validity:     ValidityOneYear,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "kube-apiserver-to-kubelet-client", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "kube-apiserver-to-kubelet-client", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -112,11 +127,18 @@ var _ asset.WritableAsset = (*KubeAPIServerLocalhostSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *KubeAPIServerLocalhostSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeAPIServerLocalhostSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-apiserver-localhost-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -124,7 +146,13 @@ func (c *KubeAPIServerLocalhostSignerCertKey) Generate(parents asset.Parents) er
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-apiserver-localhost-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "kube-apiserver-localhost-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *KubeAPIServerLocalhostSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "kube-apiserver-localhost-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -173,13 +201,15 @@ var _ asset.Asset = (*KubeAPIServerLocalhostServerCertKey)(nil)
 func (a *KubeAPIServerLocalhostServerCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&KubeAPIServerLocalhostSignerCertKey{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *KubeAPIServerLocalhostServerCertKey) Generate(dependencies asset.Parents) error {
 	ca := &KubeAPIServerLocalhostSignerCertKey{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:kube-apiserver", Organization: []string{"kube-master"}},
@@ -192,7 +222,7 @@ func (a *KubeAPIServerLocalhostServerCertKey) Generate(dependencies asset.Parent
 		IPAddresses: []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "kube-apiserver-localhost-server", AppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "kube-apiserver-localhost-server", AppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -209,11 +239,18 @@ var _ asset.WritableAsset = (*KubeAPIServerServiceNetworkSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *KubeAPIServerServiceNetworkSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeAPIServerServiceNetworkSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-apiserver-service-network-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -221,7 +258,13 @@ func (c *KubeAPIServerServiceNetworkSignerCertKey) Generate(parents asset.Parent
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-apiserver-service-network-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "kube-apiserver-service-network-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *KubeAPIServerServiceNetworkSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "kube-apiserver-service-network-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -271,6 +314,7 @@ func (a *KubeAPIServerServiceNetworkServerCertKey) Dependencies() []asset.Asset
 	return []asset.Asset{
 		&KubeAPIServerServiceNetworkSignerCertKey{},
 		&installconfig.InstallConfig{},
+		&PKIPolicy{},
 	}
 }
 
@@ -278,7 +322,8 @@ func (a *KubeAPIServerServiceNetworkServerCertKey) Dependencies() []asset.Asset
 func (a *KubeAPIServerServiceNetworkServerCertKey) Generate(dependencies asset.Parents) error {
 	ca := &KubeAPIServerServiceNetworkSignerCertKey{}
 	installConfig := &installconfig.InstallConfig{}
-	dependencies.Get(ca, installConfig)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, installConfig, policy)
 	serviceAddress, err := cidrhost(installConfig.Config.Networking.ServiceNetwork[0].IPNet, 1)
 	if err != nil {
 		return errors.Wrap(err, "failed to get service address for kube-apiserver from InstallConfig")
@@ -300,7 +345,7 @@ func (a *KubeAPIServerServiceNetworkServerCertKey) Generate(dependencies asset.P
 		IPAddresses: []net.IP{net.ParseIP(serviceAddress)},
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "kube-apiserver-service-network-server", AppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "kube-apiserver-service-network-server", AppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -317,11 +362,18 @@ var _ asset.WritableAsset = (*KubeAPIServerLBSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *KubeAPIServerLBSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeAPIServerLBSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-apiserver-lb-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -329,7 +381,13 @@ func (c *KubeAPIServerLBSignerCertKey) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-apiserver-lb-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "kube-apiserver-lb-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *KubeAPIServerLBSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "kube-apiserver-lb-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -379,6 +437,7 @@ func (a *KubeAPIServerExternalLBServerCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&KubeAPIServerLBSignerCertKey{},
 		&installconfig.InstallConfig{},
+		&PKIPolicy{},
 	}
 }
 
@@ -386,7 +445,8 @@ func (a *KubeAPIServerExternalLBServerCertKey) Dependencies() []asset.Asset {
 func (a *KubeAPIServerExternalLBServerCertKey) Generate(dependencies asset.Parents) error {
 	ca := &KubeAPIServerLBSignerCertKey{}
 	installConfig := &installconfig.InstallConfig{}
-	dependencies.Get(ca, installConfig)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, installConfig, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:kube-apiserver", Organization: []string{"kube-master"}},
@@ -398,7 +458,7 @@ func (a *KubeAPIServerExternalLBServerCertKey) Generate(dependencies asset.Paren
 		},
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "kube-apiserver-lb-server", AppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "kube-apiserver-lb-server", AppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -418,6 +478,7 @@ func (a *KubeAPIServerInternalLBServerCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&KubeAPIServerLBSignerCertKey{},
 		&installconfig.InstallConfig{},
+		&PKIPolicy{},
 	}
 }
 
@@ -425,7 +486,8 @@ func (a *KubeAPIServerInternalLBServerCertKey) Dependencies() []asset.Asset {
 func (a *KubeAPIServerInternalLBServerCertKey) Generate(dependencies asset.Parents) error {
 	ca := &KubeAPIServerLBSignerCertKey{}
 	installConfig := &installconfig.InstallConfig{}
-	dependencies.Get(ca, installConfig)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, installConfig, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:kube-apiserver", Organization: []string{"kube-master"}},
@@ -437,7 +499,7 @@ func (a *KubeAPIServerInternalLBServerCertKey) Generate(dependencies asset.Paren
 		},
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "kube-apiserver-internal-lb-server", AppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "kube-apiserver-internal-lb-server", AppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -2,8 +2,11 @@ package tls
 
 import (
 	"bytes"
-	"crypto/rsa"
+	"crypto"
+	"crypto/rand"
 	"crypto/x509"
+	"os"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -85,20 +88,26 @@ type SignedCertKey struct {
 	CertKey
 }
 
-// Generate generates a cert/key pair signed by the specified parent CA.
+// Generate generates a cert/key pair signed by the specified parent CA,
+// constrained by policy.
 func (c *SignedCertKey) Generate(
 	cfg *CertCfg,
+	policy *PKIPolicy,
 	parentCA CertKeyInterface,
 	filenameBase string,
 	appendParent AppendParentChoice,
 ) error {
-	var key *rsa.PrivateKey
+	var key crypto.Signer
 	var crt *x509.Certificate
 	var err error
 
-	caKey, err := PemToPrivateKey(parentCA.Key())
+	if len(parentCA.Cert()) == 0 {
+		return errors.Errorf("the signer of %s is waiting for its certificate signed by the external CA", filenameBase)
+	}
+
+	caKey, err := PemToSigner(parentCA.Key())
 	if err != nil {
-		return errors.Wrap(err, "failed to parse rsa private key")
+		return errors.Wrap(err, "failed to parse private key")
 	}
 
 	caCert, err := PemToCertificate(parentCA.Cert())
@@ -106,12 +115,30 @@ func (c *SignedCertKey) Generate(
 		return errors.Wrap(err, "failed to parse x509 certificate")
 	}
 
+	cfg, err = policy.apply(cfg, filenameBase)
+	if err != nil {
+		return err
+	}
+	// A certificate is not valid beyond its signer. The defaults of the
+	// installer are capped at the remaining validity of the signer, while a
+	// validity set for the certificate by the policy is kept or fails.
+	if signerValidity := caCert.NotAfter.Sub(caCert.NotBefore); cfg.Validity > signerValidity {
+		if !policy.setsValidity(filenameBase) {
+			cfg.Validity = time.Until(caCert.NotAfter)
+		} else {
+			return errors.Errorf("the validity of %s (%s) exceeds the validity of its signer %s (%s)", filenameBase, cfg.Validity, caCert.Subject.CommonName, signerValidity)
+		}
+	}
+
 	key, crt, err = GenerateSignedCertificate(caKey, caCert, cfg)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate signed cert/key pair")
 	}
 
-	c.KeyRaw = PrivateKeyToPem(key)
+	c.KeyRaw, err = SignerToPem(key)
+	if err != nil {
+		return err
+	}
 	c.CertRaw = CertToPem(crt)
 
 	if appendParent {
@@ -123,25 +150,130 @@ func (c *SignedCertKey) Generate(
 	return nil
 }
 
-// SelfSignedCertKey contains the private key and the cert that's self-signed.
+// SelfSignedCertKey contains the private key and the cert of a signer. The
+// cert is self-signed, unless the user supplied an external CA.
 type SelfSignedCertKey struct {
 	CertKey
+
+	// CSRRaw is the certificate signing request of the signer when the
+	// external CA signs it out of band.
+	CSRRaw []byte `json:",omitempty"`
 }
 
-// Generate generates a cert/key pair signed by the specified parent CA.
+// Generate generates a cert/key pair, constrained by policy. The cert is
+// signed by externalCA when it has a key, left to be signed from a
+// certificate signing request when it has none, and self-signed without an
+// external CA.
 func (c *SelfSignedCertKey) Generate(
 	cfg *CertCfg,
+	policy *PKIPolicy,
+	externalCA *ExternalCA,
 	filenameBase string,
 ) error {
-	key, crt, err := GenerateSelfSignedCertificate(cfg)
+	cfg, err := policy.apply(cfg, filenameBase)
 	if err != nil {
-		return errors.Wrap(err, "failed to generate self-signed cert/key pair")
+		return err
+	}
+
+	var key crypto.Signer
+	var crt *x509.Certificate
+	switch {
+	case externalCA.HasKey():
+		key, crt, err = externalCA.sign(cfg, filenameBase)
+		if err != nil {
+			return err
+		}
+	case externalCA.Configured():
+		return c.generateCSR(cfg, filenameBase)
+	default:
+		key, crt, err = GenerateSelfSignedCertificate(cfg)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate self-signed cert/key pair")
+		}
 	}
 
-	c.KeyRaw = PrivateKeyToPem(key)
+	c.KeyRaw, err = SignerToPem(key)
+	if err != nil {
+		return err
+	}
 	c.CertRaw = CertToPem(crt)
 
 	c.generateFiles(filenameBase)
 
 	return nil
 }
+
+// generateCSR generates the private key and the certificate signing request
+// of a signer to be signed by the external CA.
+func (c *SelfSignedCertKey) generateCSR(cfg *CertCfg, filenameBase string) error {
+	key, err := certPrivateKey(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate private key")
+	}
+	csrTmpl := x509.CertificateRequest{Subject: cfg.Subject}
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &csrTmpl, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to create certificate request")
+	}
+	csr, err := x509.ParseCertificateRequest(csrBytes)
+	if err != nil {
+		return errors.Wrap(err, "error parsing x509 certificate request")
+	}
+
+	c.KeyRaw, err = SignerToPem(key)
+	if err != nil {
+		return err
+	}
+	c.CertRaw = nil
+	c.CSRRaw = CSRToPem(csr)
+	c.FileList = []*asset.File{
+		{
+			Filename: assetFilePath(filenameBase + ".key"),
+			Data:     c.KeyRaw,
+		},
+		{
+			Filename: assetFilePath(filenameBase + ".csr"),
+			Data:     c.CSRRaw,
+		},
+	}
+	return nil
+}
+
+// load reads the cert of a signer and its key from the asset directory, for
+// signers signed out of band by the external CA.
+func (c *SelfSignedCertKey) load(f asset.FileFetcher, filenameBase string) (bool, error) {
+	certFile, err := f.FetchByName(assetFilePath(filenameBase + ".crt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	keyFile, err := f.FetchByName(assetFilePath(filenameBase + ".key"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, errors.Errorf("%s has no private key %s", certFile.Filename, assetFilePath(filenameBase+".key"))
+		}
+		return false, err
+	}
+
+	crt, err := PemToCertificate(certFile.Data)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s", certFile.Filename)
+	}
+	key, err := PemToSigner(keyFile.Data)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %s", keyFile.Filename)
+	}
+	if !crt.IsCA {
+		return false, errors.Errorf("%s is not a certificate authority", certFile.Filename)
+	}
+	if !publicKeysEqual(key.Public(), crt.PublicKey) {
+		return false, errors.Errorf("%s does not match the private key %s", certFile.Filename, keyFile.Filename)
+	}
+
+	// Keep the issuers of the external CA out of the CA bundles.
+	c.CertRaw, c.KeyRaw = CertToPem(crt), keyFile.Data
+	c.generateFiles(filenameBase)
+	return true, nil
+}
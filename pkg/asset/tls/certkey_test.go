@@ -1,12 +1,16 @@
 package tls
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
 )
 
 func TestSignedCertKeyGenerate(t *testing.T) {
@@ -44,12 +48,14 @@ func TestSignedCertKeyGenerate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			parents.Add(&PKIPolicy{}, &ExternalCA{})
 			rootCA := &RootCA{}
-			err := rootCA.Generate(nil)
+			err := rootCA.Generate(parents)
 			assert.NoError(t, err, "failed to generate root CA")
 
 			certKey := &SignedCertKey{}
-			err = certKey.Generate(tt.certCfg, rootCA, tt.filenameBase, tt.appendParent)
+			err = certKey.Generate(tt.certCfg, nil, rootCA, tt.filenameBase, tt.appendParent)
 			if err != nil {
 				assert.EqualErrorf(t, err, tt.errString, tt.name)
 				return
@@ -88,3 +94,82 @@ func TestSignedCertKeyGenerate(t *testing.T) {
 		})
 	}
 }
+
+func TestMixedKeyAlgorithms(t *testing.T) {
+	tests := []struct {
+		name   string
+		signer CertificatePolicy
+		leaf   CertificatePolicy
+		caKey  interface{}
+		key    interface{}
+	}{
+		{
+			name:   "ecdsa signer, rsa leaf",
+			signer: CertificatePolicy{KeyAlgorithm: KeyAlgorithmECDSA, KeySize: 384},
+			caKey:  &ecdsa.PrivateKey{},
+			key:    &rsa.PrivateKey{},
+		},
+		{
+			name:  "rsa signer, ecdsa leaf",
+			leaf:  CertificatePolicy{KeyAlgorithm: KeyAlgorithmECDSA},
+			caKey: &rsa.PrivateKey{},
+			key:   &ecdsa.PrivateKey{},
+		},
+		{
+			name:   "ecdsa signer, ecdsa leaf",
+			signer: CertificatePolicy{KeyAlgorithm: KeyAlgorithmECDSA},
+			leaf:   CertificatePolicy{KeyAlgorithm: KeyAlgorithmECDSA, KeySize: 384},
+			caKey:  &ecdsa.PrivateKey{},
+			key:    &ecdsa.PrivateKey{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &PKIPolicy{Config: &PKIConfig{Signers: tt.signer, Leaves: tt.leaf}}
+			parents := asset.Parents{}
+			parents.Add(policy, &ExternalCA{})
+			ca := &EtcdSignerCertKey{}
+			if !assert.NoError(t, ca.Generate(parents), "failed to generate signer") {
+				return
+			}
+
+			cfg := &CertCfg{
+				Subject:      pkix.Name{CommonName: "system:etcd-peer:etcd-client", Organization: []string{"system:etcd-peers"}},
+				KeyUsages:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+				ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+				Validity:     ValidityTenYears,
+				DNSNames:     []string{"localhost"},
+			}
+			certKey := &SignedCertKey{}
+			if !assert.NoError(t, certKey.Generate(cfg, policy, ca, "etcd-client", DoNotAppendParent), "failed to generate leaf") {
+				return
+			}
+
+			// Load both back the way they are read from disk.
+			caKey, err := PemToSigner(ca.Key())
+			assert.NoError(t, err, "failed to parse signer key")
+			assert.IsType(t, tt.caKey, caKey)
+			key, err := PemToSigner(certKey.Key())
+			assert.NoError(t, err, "failed to parse leaf key")
+			assert.IsType(t, tt.key, key)
+
+			caCert, err := PemToCertificate(ca.Cert())
+			assert.NoError(t, err, "failed to parse signer certificate")
+			cert, err := PemToCertificate(certKey.Cert())
+			assert.NoError(t, err, "failed to parse leaf certificate")
+			assert.Equal(t, key.Public(), cert.PublicKey, "leaf certificate does not match its key")
+			if _, ok := key.(*ecdsa.PrivateKey); ok {
+				assert.Zero(t, cert.KeyUsage&x509.KeyUsageKeyEncipherment, "ECDSA certificate allows key encipherment")
+			}
+
+			roots := x509.NewCertPool()
+			roots.AddCert(caCert)
+			_, err = cert.Verify(x509.VerifyOptions{
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			})
+			assert.NoError(t, err, "failed to verify the chain")
+		})
+	}
+}
@@ -16,11 +16,18 @@ var _ asset.WritableAsset = (*EtcdSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *EtcdSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *EtcdSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "etcd-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -28,7 +35,13 @@ func (c *EtcdSignerCertKey) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "etcd-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "etcd-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *EtcdSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "etcd-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -79,13 +92,15 @@ var _ asset.Asset = (*EtcdSignerClientCertKey)(nil)
 func (a *EtcdSignerClientCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&EtcdSignerCertKey{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *EtcdSignerClientCertKey) Generate(dependencies asset.Parents) error {
 	ca := &EtcdSignerCertKey{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "etcd", OrganizationalUnit: []string{"etcd"}},
@@ -94,7 +109,7 @@ func (a *EtcdSignerClientCertKey) Generate(dependencies asset.Parents) error {
 		Validity:     ValidityTenYears,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "etcd-client", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "etcd-client", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -16,11 +16,18 @@ var _ asset.WritableAsset = (*EtcdMetricSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *EtcdMetricSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *EtcdMetricSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "etcd-metric-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -28,7 +35,13 @@ func (c *EtcdMetricSignerCertKey) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "etcd-metric-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "etcd-metric-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *EtcdMetricSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "etcd-metric-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -79,13 +92,15 @@ var _ asset.Asset = (*EtcdMetricSignerClientCertKey)(nil)
 func (a *EtcdMetricSignerClientCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&EtcdMetricSignerCertKey{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *EtcdMetricSignerClientCertKey) Generate(dependencies asset.Parents) error {
 	ca := &EtcdMetricSignerCertKey{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "etcd-metric", OrganizationalUnit: []string{"etcd-metric"}},
@@ -94,7 +109,7 @@ func (a *EtcdMetricSignerClientCertKey) Generate(dependencies asset.Parents) err
 		Validity:     ValidityTenYears,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "etcd-metric-signer-client", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "etcd-metric-signer-client", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -0,0 +1,314 @@
+package tls
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	externalCACertFilename = "external-ca.crt"
+	externalCAKeyFilename  = "external-ca.key"
+)
+
+// ExternalCA is a certificate authority supplied by the user to sign the
+// signers of the installer instead of self-signing them. It is read from
+// tls/external-ca.crt, the certificate of the CA followed by the
+// certificates of its issuers, and tls/external-ca.key in the asset
+// directory. Without the key, the signers are left waiting for certificates
+// signed out of band from their certificate signing requests.
+type ExternalCA struct {
+	CertRaw  []byte
+	KeyRaw   []byte
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*ExternalCA)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (c *ExternalCA) Name() string {
+	return "External CA"
+}
+
+// Dependencies returns no dependencies.
+func (c *ExternalCA) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate leaves the external CA unset, so that the signers are
+// self-signed.
+func (c *ExternalCA) Generate(asset.Parents) error {
+	return nil
+}
+
+// Files returns the files of the external CA read from the asset directory.
+func (c *ExternalCA) Files() []*asset.File {
+	return c.FileList
+}
+
+// Load reads and validates the external CA from the asset directory.
+func (c *ExternalCA) Load(f asset.FileFetcher) (bool, error) {
+	certFile, err := f.FetchByName(filepath.Join(tlsDir, externalCACertFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	c.CertRaw = certFile.Data
+	c.FileList = []*asset.File{certFile}
+
+	keyFile, err := f.FetchByName(filepath.Join(tlsDir, externalCAKeyFilename))
+	switch {
+	case err == nil:
+		c.KeyRaw = keyFile.Data
+		c.FileList = append(c.FileList, keyFile)
+	case !os.IsNotExist(err):
+		return false, err
+	}
+
+	if err := c.validate(); err != nil {
+		return false, errors.Wrapf(err, "invalid external CA %s", filepath.Join(tlsDir, externalCACertFilename))
+	}
+	return true, nil
+}
+
+// Configured returns whether the user supplied an external CA.
+func (c *ExternalCA) Configured() bool {
+	return c != nil && len(c.CertRaw) > 0
+}
+
+// HasKey returns whether the installer can sign the signers with the
+// external CA. Without the key, they are signed out of band.
+func (c *ExternalCA) HasKey() bool {
+	return c.Configured() && len(c.KeyRaw) > 0
+}
+
+// validate checks that the external CA can sign certificates, that its
+// chain is valid and that the key, if any, is its own.
+func (c *ExternalCA) validate() error {
+	issuer, err := c.issuer()
+	if err != nil {
+		return err
+	}
+	if !issuer.IsCA || (issuer.KeyUsage != 0 && issuer.KeyUsage&x509.KeyUsageCertSign == 0) {
+		return errors.Errorf("%s is not a certificate authority", issuer.Subject)
+	}
+	if err := c.verify(issuer); err != nil {
+		return err
+	}
+	if len(c.KeyRaw) == 0 {
+		return nil
+	}
+	key, err := PemToSigner(c.KeyRaw)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the private key")
+	}
+	if !publicKeysEqual(key.Public(), issuer.PublicKey) {
+		return errors.Errorf("the private key does not match the certificate of %s", issuer.Subject)
+	}
+	return nil
+}
+
+// certificates returns the certificates of the chain of the external CA,
+// the CA itself first.
+func (c *ExternalCA) certificates() ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for rest := c.CertRaw; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse certificate")
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+	return certs, nil
+}
+
+// issuer returns the certificate of the external CA.
+func (c *ExternalCA) issuer() (*x509.Certificate, error) {
+	certs, err := c.certificates()
+	if err != nil {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
+// verify checks that cert chains up to a root of the external CA. The
+// self-signed certificates of the chain are its roots, and without them the
+// system roots are.
+func (c *ExternalCA) verify(cert *x509.Certificate) error {
+	certs, err := c.certificates()
+	if err != nil {
+		return err
+	}
+	opts := x509.VerifyOptions{
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	roots := x509.NewCertPool()
+	hasRoots := false
+	for _, ca := range certs {
+		if bytes.Equal(ca.RawIssuer, ca.RawSubject) && ca.CheckSignatureFrom(ca) == nil {
+			roots.AddCert(ca)
+			hasRoots = true
+		} else {
+			opts.Intermediates.AddCert(ca)
+		}
+	}
+	if hasRoots {
+		opts.Roots = roots
+	}
+	if _, err := cert.Verify(opts); err != nil {
+		return errors.Wrapf(err, "failed to verify the chain of %s", cert.Subject)
+	}
+	return nil
+}
+
+// sign signs the signer defined by cfg with the external CA.
+func (c *ExternalCA) sign(cfg *CertCfg, filenameBase string) (crypto.Signer, *x509.Certificate, error) {
+	issuer, err := c.issuer()
+	if err != nil {
+		return nil, nil, err
+	}
+	caKey, err := PemToSigner(c.KeyRaw)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse the private key of the external CA")
+	}
+	// A certificate is not valid beyond its signer.
+	if remaining := time.Until(issuer.NotAfter); cfg.Validity > remaining {
+		return nil, nil, errors.Errorf("the validity of %s (%s) exceeds the remaining validity of the external CA %s (%s), lower the validity of the signers in the PKI policy", filenameBase, cfg.Validity, issuer.Subject, remaining.Round(time.Hour))
+	}
+	key, crt, err := GenerateSignedCertificate(caKey, issuer, cfg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate cert/key pair signed by the external CA")
+	}
+	return key, crt, nil
+}
+
+// publicKeysEqual returns whether a and b are the same public key.
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	aBytes, err := x509.MarshalPKIXPublicKey(a)
+	if err != nil {
+		return false
+	}
+	bBytes, err := x509.MarshalPKIXPublicKey(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aBytes, bBytes)
+}
+
+// signer is a certificate authority generated by the installer.
+type signer interface {
+	asset.WritableAsset
+	CertKeyInterface
+}
+
+// signers returns the certificate authorities generated by the installer,
+// by the base name of their files.
+func signers() map[string]signer {
+	return map[string]signer{
+		"admin-kubeconfig-signer":               &AdminKubeConfigSignerCertKey{},
+		"aggregator-ca":                         &AggregatorCA{},
+		"aggregator-signer":                     &AggregatorSignerCertKey{},
+		"etcd-metric-signer":                    &EtcdMetricSignerCertKey{},
+		"etcd-signer":                           &EtcdSignerCertKey{},
+		"kube-apiserver-lb-signer":              &KubeAPIServerLBSignerCertKey{},
+		"kube-apiserver-localhost-signer":       &KubeAPIServerLocalhostSignerCertKey{},
+		"kube-apiserver-service-network-signer": &KubeAPIServerServiceNetworkSignerCertKey{},
+		"kube-apiserver-to-kubelet-signer":      &KubeAPIServerToKubeletSignerCertKey{},
+		"kube-control-plane-signer":             &KubeControlPlaneSignerCertKey{},
+		"kubelet-bootstrap-kubeconfig-signer":   &KubeletBootstrapCertSigner{},
+		"kubelet-signer":                        &KubeletCSRSignerCertKey{},
+		"root-ca":                               &RootCA{},
+	}
+}
+
+// sortedSignerNames returns the base names of the files of the signers, in
+// order.
+func sortedSignerNames(signers map[string]signer) []string {
+	names := make([]string, 0, len(signers))
+	for name := range signers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// signerDependencies returns the signers as dependencies, in order.
+func signerDependencies(signers map[string]signer) []asset.Asset {
+	deps := make([]asset.Asset, 0, len(signers))
+	for _, name := range sortedSignerNames(signers) {
+		deps = append(deps, signers[name])
+	}
+	return deps
+}
+
+// SignerChains validates, before the ignition configs are generated, that
+// the signers chain up to the external CA when there is one.
+type SignerChains struct {
+}
+
+var _ asset.Asset = (*SignerChains)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (c *SignerChains) Name() string {
+	return "Signer Chains"
+}
+
+// Dependencies returns the external CA and the signers.
+func (c *SignerChains) Dependencies() []asset.Asset {
+	return append([]asset.Asset{&ExternalCA{}}, signerDependencies(signers())...)
+}
+
+// Generate verifies the chains of the signers.
+func (c *SignerChains) Generate(parents asset.Parents) error {
+	externalCA := &ExternalCA{}
+	parents.Get(externalCA)
+	if !externalCA.Configured() {
+		return nil
+	}
+
+	all := signers()
+	var unsigned []string
+	for _, name := range sortedSignerNames(all) {
+		s := all[name]
+		parents.Get(s)
+		if len(s.Cert()) == 0 {
+			unsigned = append(unsigned, filepath.Join(tlsDir, name+".crt"))
+			continue
+		}
+		cert, err := PemToCertificate(s.Cert())
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse the certificate of %s", name)
+		}
+		if err := externalCA.verify(cert); err != nil {
+			return errors.Wrapf(err, "%s is not signed by the external CA", name)
+		}
+	}
+	if len(unsigned) > 0 {
+		return errors.Errorf("waiting for the certificates signed by the external CA from the signing requests: %s", strings.Join(unsigned, ", "))
+	}
+	return nil
+}
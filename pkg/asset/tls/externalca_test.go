@@ -0,0 +1,229 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/mock"
+)
+
+// externalChain returns the PEM chain and key of an intermediate defined by
+// cfg, signed by a self-signed root.
+func externalChain(t *testing.T, cfg CertCfg) ([]byte, []byte) {
+	rootKey, rootCert, err := GenerateSelfSignedCertificate(&CertCfg{
+		Subject:   pkix.Name{CommonName: "enterprise-root", OrganizationalUnit: []string{"pki"}},
+		KeyUsages: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  ValidityTenYears,
+		IsCA:      true,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate the root: %v", err)
+	}
+	cfg.Subject = pkix.Name{CommonName: "enterprise-intermediate", OrganizationalUnit: []string{"pki"}}
+	key, cert, err := GenerateSignedCertificate(rootKey, rootCert, &cfg)
+	if err != nil {
+		t.Fatalf("failed to generate the intermediate: %v", err)
+	}
+	keyPem, err := SignerToPem(key)
+	if err != nil {
+		t.Fatalf("failed to encode the intermediate key: %v", err)
+	}
+	return bytes.Join([][]byte{CertToPem(cert), CertToPem(rootCert)}, nil), keyPem
+}
+
+// fileFetcher returns a FileFetcher serving files.
+func fileFetcher(t *testing.T, files map[string][]byte) asset.FileFetcher {
+	mockCtrl := gomock.NewController(t)
+	fetcher := mock.NewMockFileFetcher(mockCtrl)
+	fetcher.EXPECT().FetchByName(gomock.Any()).DoAndReturn(func(name string) (*asset.File, error) {
+		data, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return &asset.File{Filename: name, Data: data}, nil
+	}).AnyTimes()
+	return fetcher
+}
+
+func TestExternalCALoad(t *testing.T) {
+	cert, key := externalChain(t, CertCfg{
+		KeyUsages: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  ValidityOneYear,
+		IsCA:      true,
+	})
+	_, otherKey := externalChain(t, CertCfg{
+		KeyUsages: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  ValidityOneYear,
+		IsCA:      true,
+	})
+	leaf, leafKey := externalChain(t, CertCfg{
+		KeyUsages: x509.KeyUsageDigitalSignature,
+		Validity:  ValidityOneYear,
+	})
+	cases := []struct {
+		name   string
+		files  map[string][]byte
+		found  bool
+		hasKey bool
+		err    string
+	}{{
+		name: "none",
+	}, {
+		name:   "certificate and key",
+		files:  map[string][]byte{"tls/external-ca.crt": cert, "tls/external-ca.key": key},
+		found:  true,
+		hasKey: true,
+	}, {
+		name:  "certificate only",
+		files: map[string][]byte{"tls/external-ca.crt": cert},
+		found: true,
+	}, {
+		name:  "mismatched key",
+		files: map[string][]byte{"tls/external-ca.crt": cert, "tls/external-ca.key": otherKey},
+		err:   "invalid external CA tls/external-ca.crt: the private key does not match the certificate of CN=enterprise-intermediate,OU=pki",
+	}, {
+		name:  "incomplete chain",
+		files: map[string][]byte{"tls/external-ca.crt": cert[:bytes.Index(cert, []byte("-----END CERTIFICATE-----"))+len("-----END CERTIFICATE-----\n")]},
+		err:   "invalid external CA tls/external-ca.crt: failed to verify the chain of CN=enterprise-intermediate,OU=pki: x509: certificate signed by unknown authority",
+	}, {
+		name:  "not a certificate authority",
+		files: map[string][]byte{"tls/external-ca.crt": leaf, "tls/external-ca.key": leafKey},
+		err:   "invalid external CA tls/external-ca.crt: CN=enterprise-intermediate,OU=pki is not a certificate authority",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			externalCA := &ExternalCA{}
+			found, err := externalCA.Load(fileFetcher(t, tc.files))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.found, externalCA.Configured())
+			assert.Equal(t, tc.hasKey, externalCA.HasKey())
+		})
+	}
+}
+
+// generateSigners generates all the signers with parents.
+func generateSigners(t *testing.T, parents asset.Parents) (map[string]signer, error) {
+	all := signers()
+	for _, name := range sortedSignerNames(all) {
+		if err := all[name].Generate(parents); err != nil {
+			return nil, err
+		}
+		parents.Add(all[name])
+	}
+	return all, nil
+}
+
+func TestExternalCASignsSigners(t *testing.T) {
+	cert, key := externalChain(t, CertCfg{
+		KeyUsages: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  2 * ValidityOneYear,
+		IsCA:      true,
+	})
+	externalCA := &ExternalCA{CertRaw: cert, KeyRaw: key}
+
+	parents := asset.Parents{}
+	parents.Add(&PKIPolicy{}, externalCA)
+	_, err := generateSigners(t, parents)
+	assert.Regexp(t, `^the validity of admin-kubeconfig-signer \(87600h0m0s\) exceeds the remaining validity of the external CA CN=enterprise-intermediate,OU=pki \(\d+h0m0s\), lower the validity of the signers in the PKI policy$`, err)
+
+	parents = asset.Parents{}
+	parents.Add(&PKIPolicy{Config: &PKIConfig{Signers: CertificatePolicy{MaxValidity: &Duration{ValidityOneYear}}}}, externalCA)
+	if _, err := generateSigners(t, parents); !assert.NoError(t, err) {
+		return
+	}
+	chains := &SignerChains{}
+	assert.NoError(t, chains.Generate(parents))
+
+	// A self-signed signer breaks the chain.
+	rootCA := &RootCA{}
+	noExternalCA := asset.Parents{}
+	noExternalCA.Add(&PKIPolicy{}, &ExternalCA{})
+	if !assert.NoError(t, rootCA.Generate(noExternalCA)) {
+		return
+	}
+	parents.Add(rootCA)
+	assert.EqualError(t, chains.Generate(parents), "root-ca is not signed by the external CA: failed to verify the chain of CN=root-ca,OU=openshift: x509: certificate signed by unknown authority")
+}
+
+func TestExternalCASigningRequests(t *testing.T) {
+	cert, key := externalChain(t, CertCfg{
+		KeyUsages: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  ValidityTenYears,
+		IsCA:      true,
+	})
+	externalCA := &ExternalCA{CertRaw: cert}
+
+	parents := asset.Parents{}
+	parents.Add(&PKIPolicy{}, externalCA)
+	all, err := generateSigners(t, parents)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	requests := &SigningRequests{}
+	if !assert.NoError(t, requests.Generate(parents)) {
+		return
+	}
+	files := map[string][]byte{}
+	for _, f := range requests.Files() {
+		files[f.Filename] = f.Data
+	}
+	assert.Len(t, files, 2*len(all))
+	assert.EqualError(t, (&SignerChains{}).Generate(parents), "waiting for the certificates signed by the external CA from the signing requests: tls/admin-kubeconfig-signer.crt, tls/aggregator-ca.crt, tls/aggregator-signer.crt, tls/etcd-metric-signer.crt, tls/etcd-signer.crt, tls/kube-apiserver-lb-signer.crt, tls/kube-apiserver-localhost-signer.crt, tls/kube-apiserver-service-network-signer.crt, tls/kube-apiserver-to-kubelet-signer.crt, tls/kube-control-plane-signer.crt, tls/kubelet-bootstrap-kubeconfig-signer.crt, tls/kubelet-signer.crt, tls/root-ca.crt")
+
+	// A leaf cannot be signed until its signer is.
+	adminClient := &AdminKubeConfigClientCertKey{}
+	assert.EqualError(t, adminClient.Generate(parents), "the signer of admin-kubeconfig-client is waiting for its certificate signed by the external CA")
+
+	// Sign the root CA request out of band and load it back.
+	caKey, err := PemToSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := PemToCertificate(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(files["tls/root-ca.csr"])
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootKey, err := PemToSigner(files["tls/root-ca.key"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := SignedCertificate(&CertCfg{
+		KeyUsages: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  ValidityOneYear,
+		IsCA:      true,
+	}, csr, rootKey, caCert, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["tls/root-ca.crt"] = bytes.Join([][]byte{CertToPem(signed), cert}, nil)
+
+	rootCA := &RootCA{}
+	found, err := rootCA.Load(fileFetcher(t, files))
+	if !assert.NoError(t, err) || !assert.True(t, found) {
+		return
+	}
+	assert.Equal(t, CertToPem(signed), rootCA.Cert(), "the issuers of the external CA are not dropped")
+	parents.Add(rootCA)
+	err = (&SignerChains{}).Generate(parents)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "tls/root-ca.crt")
+}
@@ -21,13 +21,15 @@ var _ asset.WritableAsset = (*JournalCertKey)(nil)
 func (a *JournalCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&RootCA{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *JournalCertKey) Generate(dependencies asset.Parents) error {
 	ca := &RootCA{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "journal-gatewayd", Organization: []string{"OpenShift Bootstrap"}},
@@ -36,7 +38,7 @@ func (a *JournalCertKey) Generate(dependencies asset.Parents) error {
 		Validity:     ValidityTenYears,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "journal-gatewayd", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "journal-gatewayd", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
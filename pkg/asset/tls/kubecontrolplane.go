@@ -16,11 +16,18 @@ var _ asset.WritableAsset = (*KubeControlPlaneSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *KubeControlPlaneSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeControlPlaneSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kube-control-plane-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -28,7 +35,13 @@ func (c *KubeControlPlaneSignerCertKey) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kube-control-plane-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "kube-control-plane-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *KubeControlPlaneSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "kube-control-plane-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -80,13 +93,15 @@ var _ asset.Asset = (*KubeControlPlaneKubeControllerManagerClientCertKey)(nil)
 func (a *KubeControlPlaneKubeControllerManagerClientCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&KubeControlPlaneSignerCertKey{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *KubeControlPlaneKubeControllerManagerClientCertKey) Generate(dependencies asset.Parents) error {
 	ca := &KubeControlPlaneSignerCertKey{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:admin", Organization: []string{"system:masters"}},
@@ -95,7 +110,7 @@ func (a *KubeControlPlaneKubeControllerManagerClientCertKey) Generate(dependenci
 		Validity:     ValidityOneYear,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "kube-control-plane-kube-controller-manager-client", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "kube-control-plane-kube-controller-manager-client", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -114,13 +129,15 @@ var _ asset.Asset = (*KubeControlPlaneKubeSchedulerClientCertKey)(nil)
 func (a *KubeControlPlaneKubeSchedulerClientCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&KubeControlPlaneSignerCertKey{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *KubeControlPlaneKubeSchedulerClientCertKey) Generate(dependencies asset.Parents) error {
 	ca := &KubeControlPlaneSignerCertKey{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:admin", Organization: []string{"system:masters"}},
@@ -129,7 +146,7 @@ func (a *KubeControlPlaneKubeSchedulerClientCertKey) Generate(dependencies asset
 		Validity:     ValidityOneYear,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "kube-control-plane-kube-scheduler-client", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "kube-control-plane-kube-scheduler-client", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
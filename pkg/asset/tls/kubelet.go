@@ -16,11 +16,18 @@ var _ asset.WritableAsset = (*KubeletCSRSignerCertKey)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *KubeletCSRSignerCertKey) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeletCSRSignerCertKey) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kubelet-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -28,7 +35,13 @@ func (c *KubeletCSRSignerCertKey) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kubelet-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "kubelet-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *KubeletCSRSignerCertKey) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "kubelet-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -106,11 +119,18 @@ var _ asset.WritableAsset = (*KubeletBootstrapCertSigner)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *KubeletBootstrapCertSigner) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *KubeletBootstrapCertSigner) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "kubelet-bootstrap-kubeconfig-signer", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -118,7 +138,13 @@ func (c *KubeletBootstrapCertSigner) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "kubelet-bootstrap-kubeconfig-signer")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "kubelet-bootstrap-kubeconfig-signer")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *KubeletBootstrapCertSigner) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "kubelet-bootstrap-kubeconfig-signer")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -170,13 +196,15 @@ var _ asset.Asset = (*KubeletClientCertKey)(nil)
 func (a *KubeletClientCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&KubeletBootstrapCertSigner{},
+		&PKIPolicy{},
 	}
 }
 
 // Generate generates the cert/key pair based on its dependencies.
 func (a *KubeletClientCertKey) Generate(dependencies asset.Parents) error {
 	ca := &KubeletBootstrapCertSigner{}
-	dependencies.Get(ca)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, policy)
 
 	cfg := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:serviceaccount:openshift-machine-config-operator:node-bootstrapper", Organization: []string{"system:serviceaccounts:openshift-machine-config-operator"}},
@@ -185,7 +213,7 @@ func (a *KubeletClientCertKey) Generate(dependencies asset.Parents) error {
 		Validity:     ValidityTenYears,
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "kubelet-client", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "kubelet-client", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -28,6 +28,7 @@ func (a *MCSCertKey) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&RootCA{},
 		&installconfig.InstallConfig{},
+		&PKIPolicy{},
 	}
 }
 
@@ -35,7 +36,8 @@ func (a *MCSCertKey) Dependencies() []asset.Asset {
 func (a *MCSCertKey) Generate(dependencies asset.Parents) error {
 	ca := &RootCA{}
 	installConfig := &installconfig.InstallConfig{}
-	dependencies.Get(ca, installConfig)
+	policy := &PKIPolicy{}
+	dependencies.Get(ca, installConfig, policy)
 
 	hostname := internalAPIAddress(installConfig.Config)
 
@@ -68,7 +70,7 @@ func (a *MCSCertKey) Generate(dependencies asset.Parents) error {
 		cfg.DNSNames = []string{hostname}
 	}
 
-	return a.SignedCertKey.Generate(cfg, ca, "machine-config-server", DoNotAppendParent)
+	return a.SignedCertKey.Generate(cfg, policy, ca, "machine-config-server", DoNotAppendParent)
 }
 
 // Name returns the human-friendly name of the asset.
@@ -0,0 +1,307 @@
+package tls
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const (
+	pkiPolicyFilename = "pki-policy.yaml"
+
+	// PKIPolicyEnvVar names the environment variable holding the path of the
+	// PKI policy used when the asset directory has none.
+	PKIPolicyEnvVar = "OPENSHIFT_INSTALL_PKI_POLICY"
+
+	// MinimumValidity is the shortest validity a policy can give a
+	// certificate. The short-lived certificates of the bootstrap must last
+	// until the operators of the cluster replace them.
+	MinimumValidity = ValidityOneDay
+)
+
+// KeyAlgorithm is the algorithm of the private key of a certificate.
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmRSA generates RSA keys.
+	KeyAlgorithmRSA KeyAlgorithm = "RSA"
+	// KeyAlgorithmECDSA generates ECDSA keys on the NIST P-256 or P-384
+	// curves.
+	KeyAlgorithmECDSA KeyAlgorithm = "ECDSA"
+)
+
+var (
+	// rsaKeySizes are the sizes allowed for RSA keys.
+	rsaKeySizes = []int{2048, 3072, 4096}
+
+	// ecdsaKeySizes are the sizes allowed for ECDSA keys.
+	ecdsaKeySizes = []int{256, 384}
+)
+
+// Duration is a time.Duration read from a string such as "8760h", "90d" or
+// "2y", where a day is 24 hours and a year is 365 days.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON reads the duration from a string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Errorf("invalid duration %s, must be a string", data)
+	}
+	for suffix, unit := range map[string]time.Duration{"d": ValidityOneDay, "y": ValidityOneYear} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSuffix(s, suffix), 10, 64)
+		if err != nil {
+			return errors.Errorf("invalid duration %q", s)
+		}
+		d.Duration = time.Duration(n) * unit
+		return nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.Errorf("invalid duration %q", s)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// MarshalJSON writes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Duration.String())
+}
+
+// CertificatePolicy constrains the certificates generated by the installer.
+// Unset fields keep the defaults of the installer.
+type CertificatePolicy struct {
+	// Validity replaces the validity of the certificates.
+	Validity *Duration `json:"validity,omitempty"`
+
+	// MaxValidity caps the validity of the certificates.
+	MaxValidity *Duration `json:"maxValidity,omitempty"`
+
+	// KeyAlgorithm is the algorithm of the private keys.
+	KeyAlgorithm KeyAlgorithm `json:"keyAlgorithm,omitempty"`
+
+	// KeySize is the size of the private keys, in bits.
+	KeySize int `json:"keySize,omitempty"`
+}
+
+// merge returns the policy with the fields set in override replaced.
+func (p CertificatePolicy) merge(override CertificatePolicy) CertificatePolicy {
+	if override.Validity != nil {
+		p.Validity = override.Validity
+	}
+	if override.MaxValidity != nil {
+		p.MaxValidity = override.MaxValidity
+	}
+	if override.KeyAlgorithm != "" && override.KeyAlgorithm != p.KeyAlgorithm {
+		// The size of one algorithm is meaningless for the other.
+		p.KeyAlgorithm = override.KeyAlgorithm
+		p.KeySize = 0
+	}
+	if override.KeySize != 0 {
+		p.KeySize = override.KeySize
+	}
+	return p
+}
+
+func (p CertificatePolicy) validate() error {
+	if p.Validity != nil && p.Validity.Duration < MinimumValidity {
+		return errors.Errorf("validity must be at least %s so that the cluster can bootstrap, got %s", MinimumValidity, p.Validity.Duration)
+	}
+	if p.MaxValidity != nil && p.MaxValidity.Duration < MinimumValidity {
+		return errors.Errorf("maxValidity must be at least %s so that the cluster can bootstrap, got %s", MinimumValidity, p.MaxValidity.Duration)
+	}
+	var sizes []int
+	switch p.KeyAlgorithm {
+	case "", KeyAlgorithmRSA:
+		sizes = rsaKeySizes
+	case KeyAlgorithmECDSA:
+		sizes = ecdsaKeySizes
+	default:
+		return errors.Errorf("unsupported key algorithm %q, must be %s or %s", p.KeyAlgorithm, KeyAlgorithmRSA, KeyAlgorithmECDSA)
+	}
+	if p.KeySize == 0 {
+		return nil
+	}
+	for _, size := range sizes {
+		if p.KeySize == size {
+			return nil
+		}
+	}
+	algorithm := p.KeyAlgorithm
+	if algorithm == "" {
+		algorithm = KeyAlgorithmRSA
+	}
+	return errors.Errorf("invalid %s key size %d, must be one of %v", algorithm, p.KeySize, sizes)
+}
+
+// PKIConfig is the policy for the certificates generated by the installer.
+type PKIConfig struct {
+	// Signers applies to the certificate authorities.
+	Signers CertificatePolicy `json:"signers,omitempty"`
+
+	// Leaves applies to the certificates signed by the certificate
+	// authorities.
+	Leaves CertificatePolicy `json:"leaves,omitempty"`
+
+	// Certificates override Signers and Leaves for single certificates,
+	// by the name of their files in the tls directory without extension,
+	// e.g. root-ca or kube-apiserver-lb-signer.
+	Certificates map[string]CertificatePolicy `json:"certificates,omitempty"`
+}
+
+// Validate returns an error when the policy is not valid.
+func (c *PKIConfig) Validate() error {
+	if err := c.Signers.validate(); err != nil {
+		return errors.Wrap(err, "invalid signers policy")
+	}
+	if err := c.Leaves.validate(); err != nil {
+		return errors.Wrap(err, "invalid leaves policy")
+	}
+	for name, p := range c.Certificates {
+		if err := p.validate(); err != nil {
+			return errors.Wrapf(err, "invalid policy for %s", name)
+		}
+	}
+	return nil
+}
+
+// PKIPolicy is the policy for the certificates generated by the installer. It
+// is read from pki-policy.yaml in the asset directory, or else from the file
+// named by OPENSHIFT_INSTALL_PKI_POLICY.
+type PKIPolicy struct {
+	Config *PKIConfig
+	File   *asset.File
+}
+
+var _ asset.WritableAsset = (*PKIPolicy)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (p *PKIPolicy) Name() string {
+	return "PKI Policy"
+}
+
+// Dependencies returns no dependencies.
+func (p *PKIPolicy) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate reads the policy named by OPENSHIFT_INSTALL_PKI_POLICY. Without
+// it, the certificates keep the defaults of the installer.
+func (p *PKIPolicy) Generate(asset.Parents) error {
+	p.Config = &PKIConfig{}
+	path := os.Getenv(PKIPolicyEnvVar)
+	if path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read PKI policy")
+	}
+	config, err := ParsePKIConfig(data)
+	if err != nil {
+		return errors.Wrapf(err, "invalid PKI policy %s", path)
+	}
+	p.Config = config
+	return nil
+}
+
+// Files returns the policy file when it was read from the asset directory.
+func (p *PKIPolicy) Files() []*asset.File {
+	if p.File != nil {
+		return []*asset.File{p.File}
+	}
+	return []*asset.File{}
+}
+
+// Load reads the policy from the asset directory.
+func (p *PKIPolicy) Load(f asset.FileFetcher) (found bool, err error) {
+	file, err := f.FetchByName(pkiPolicyFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	config, err := ParsePKIConfig(file.Data)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid %q file", pkiPolicyFilename)
+	}
+	p.Config, p.File = config, file
+	return true, nil
+}
+
+// ParsePKIConfig parses and validates a YAML PKI policy.
+func ParsePKIConfig(data []byte) (*PKIConfig, error) {
+	config := &PKIConfig{}
+	if err := yaml.UnmarshalStrict(data, config, yaml.DisallowUnknownFields); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// apply returns a copy of cfg, the configuration of the certificate stored in
+// the files named after filenameBase, constrained by the policy. A nil policy
+// leaves cfg unchanged. The validity of signers and leaves only shortens the
+// certificates, so that the short-lived certificates of the bootstrap are
+// not extended; the validity of a single certificate replaces its own.
+func (p *PKIPolicy) apply(cfg *CertCfg, filenameBase string) (*CertCfg, error) {
+	applied := *cfg
+	if p == nil || p.Config == nil {
+		return &applied, nil
+	}
+	policy := p.Config.Leaves
+	if cfg.IsCA {
+		policy = p.Config.Signers
+	}
+	certificate := p.Config.Certificates[filenameBase]
+	if policy.Validity != nil && policy.Validity.Duration < applied.Validity {
+		applied.Validity = policy.Validity.Duration
+	}
+	policy = policy.merge(certificate)
+	if err := policy.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid policy for %s", filenameBase)
+	}
+
+	if certificate.Validity != nil {
+		applied.Validity = certificate.Validity.Duration
+	}
+	if policy.MaxValidity != nil && applied.Validity > policy.MaxValidity.Duration {
+		applied.Validity = policy.MaxValidity.Duration
+	}
+	if applied.Validity < MinimumValidity {
+		return nil, errors.Errorf("the validity of %s must be at least %s, got %s", filenameBase, MinimumValidity, applied.Validity)
+	}
+	if policy.KeyAlgorithm != "" {
+		applied.KeyAlgorithm = policy.KeyAlgorithm
+	}
+	if policy.KeySize != 0 {
+		applied.KeySize = policy.KeySize
+	}
+	return &applied, nil
+}
+
+// setsValidity returns whether the policy sets the validity of the single
+// certificate stored in the files named after filenameBase.
+func (p *PKIPolicy) setsValidity(filenameBase string) bool {
+	if p == nil || p.Config == nil {
+		return false
+	}
+	return p.Config.Certificates[filenameBase].Validity != nil
+}
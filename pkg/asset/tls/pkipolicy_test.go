@@ -0,0 +1,257 @@
+package tls
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+func TestParsePKIConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected *PKIConfig
+		err      string
+	}{{
+		name:     "empty",
+		expected: &PKIConfig{},
+	}, {
+		name: "durations",
+		data: `
+signers:
+  maxValidity: 5y
+leaves:
+  maxValidity: 90d
+  keySize: 4096
+certificates:
+  root-ca:
+    validity: 8760h
+`,
+		expected: &PKIConfig{
+			Signers: CertificatePolicy{MaxValidity: &Duration{5 * ValidityOneYear}},
+			Leaves:  CertificatePolicy{MaxValidity: &Duration{90 * ValidityOneDay}, KeySize: 4096},
+			Certificates: map[string]CertificatePolicy{
+				"root-ca": {Validity: &Duration{8760 * time.Hour}},
+			},
+		},
+	}, {
+		name: "invalid duration",
+		data: "signers:\n  validity: 5 years\n",
+		err:  `failed to unmarshal: error unmarshaling JSON: while decoding JSON: invalid duration "5 years"`,
+	}, {
+		name: "unknown field",
+		data: "signer:\n  validity: 1y\n",
+		err:  `failed to unmarshal: error unmarshaling JSON: while decoding JSON: json: unknown field "signer"`,
+	}, {
+		name: "validity too short",
+		data: "leaves:\n  maxValidity: 12h\n",
+		err:  "invalid leaves policy: maxValidity must be at least 24h0m0s so that the cluster can bootstrap, got 12h0m0s",
+	}, {
+		name: "invalid key size",
+		data: "certificates:\n  root-ca:\n    keySize: 1024\n",
+		err:  "invalid policy for root-ca: invalid RSA key size 1024, must be one of [2048 3072 4096]",
+	}, {
+		name: "unsupported key algorithm",
+		data: "signers:\n  keyAlgorithm: DSA\n",
+		err:  `invalid signers policy: unsupported key algorithm "DSA", must be RSA or ECDSA`,
+	}, {
+		name: "ecdsa",
+		data: "signers:\n  keyAlgorithm: ECDSA\n  keySize: 384\n",
+		expected: &PKIConfig{
+			Signers: CertificatePolicy{KeyAlgorithm: KeyAlgorithmECDSA, KeySize: 384},
+		},
+	}, {
+		name: "invalid ecdsa key size",
+		data: "leaves:\n  keyAlgorithm: ECDSA\n  keySize: 521\n",
+		err:  "invalid leaves policy: invalid ECDSA key size 521, must be one of [256 384]",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := ParsePKIConfig([]byte(tc.data))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, config)
+		})
+	}
+}
+
+func TestPKIPolicyApply(t *testing.T) {
+	policy := &PKIPolicy{Config: &PKIConfig{
+		Signers: CertificatePolicy{MaxValidity: &Duration{5 * ValidityOneYear}},
+		Leaves:  CertificatePolicy{MaxValidity: &Duration{ValidityOneYear}, KeySize: 3072},
+		Certificates: map[string]CertificatePolicy{
+			"kubelet-signer":           {Validity: &Duration{2 * ValidityOneDay}},
+			"admin-kubeconfig-client":  {MaxValidity: &Duration{90 * ValidityOneDay}},
+			"etcd-signer":              {KeyAlgorithm: KeyAlgorithmECDSA},
+			"etcd-metric-signer":       {KeyAlgorithm: KeyAlgorithmECDSA, KeySize: 384},
+			"kube-apiserver-lb-signer": {KeyAlgorithm: KeyAlgorithmRSA},
+		},
+	}}
+	cases := []struct {
+		name         string
+		policy       *PKIPolicy
+		filenameBase string
+		cfg          CertCfg
+
+		validity     time.Duration
+		keyAlgorithm KeyAlgorithm
+		keySize      int
+	}{{
+		name:         "no policy",
+		filenameBase: "root-ca",
+		cfg:          CertCfg{Validity: ValidityTenYears, IsCA: true},
+		validity:     ValidityTenYears,
+	}, {
+		name:         "capped signer",
+		policy:       policy,
+		filenameBase: "root-ca",
+		cfg:          CertCfg{Validity: ValidityTenYears, IsCA: true},
+		validity:     5 * ValidityOneYear,
+	}, {
+		name:         "short-lived leaf is kept",
+		policy:       policy,
+		filenameBase: "aggregator-client",
+		cfg:          CertCfg{Validity: ValidityOneDay},
+		validity:     ValidityOneDay,
+		keySize:      3072,
+	}, {
+		name:         "leaves validity does not lengthen",
+		policy:       &PKIPolicy{Config: &PKIConfig{Leaves: CertificatePolicy{Validity: &Duration{90 * ValidityOneDay}}}},
+		filenameBase: "aggregator-client",
+		cfg:          CertCfg{Validity: ValidityOneDay},
+		validity:     ValidityOneDay,
+	}, {
+		name:         "leaves validity shortens",
+		policy:       &PKIPolicy{Config: &PKIConfig{Leaves: CertificatePolicy{Validity: &Duration{90 * ValidityOneDay}}}},
+		filenameBase: "admin-kubeconfig-client",
+		cfg:          CertCfg{Validity: ValidityTenYears},
+		validity:     90 * ValidityOneDay,
+	}, {
+		name:         "certificate validity",
+		policy:       policy,
+		filenameBase: "kubelet-signer",
+		cfg:          CertCfg{Validity: ValidityOneDay, IsCA: true},
+		validity:     2 * ValidityOneDay,
+	}, {
+		name:         "certificate override",
+		policy:       policy,
+		filenameBase: "admin-kubeconfig-client",
+		cfg:          CertCfg{Validity: ValidityTenYears},
+		validity:     90 * ValidityOneDay,
+		keySize:      3072,
+	}, {
+		name:         "ecdsa signer",
+		policy:       policy,
+		filenameBase: "etcd-signer",
+		cfg:          CertCfg{Validity: ValidityTenYears, IsCA: true},
+		validity:     5 * ValidityOneYear,
+		keyAlgorithm: KeyAlgorithmECDSA,
+	}, {
+		name:         "ecdsa signer with key size",
+		policy:       policy,
+		filenameBase: "etcd-metric-signer",
+		cfg:          CertCfg{Validity: ValidityTenYears, IsCA: true},
+		validity:     5 * ValidityOneYear,
+		keyAlgorithm: KeyAlgorithmECDSA,
+		keySize:      384,
+	}, {
+		name:         "same algorithm keeps key size",
+		policy:       &PKIPolicy{Config: &PKIConfig{Leaves: CertificatePolicy{KeyAlgorithm: KeyAlgorithmRSA, KeySize: 4096}, Certificates: map[string]CertificatePolicy{"mcs": {KeyAlgorithm: KeyAlgorithmRSA}}}},
+		filenameBase: "mcs",
+		cfg:          CertCfg{Validity: ValidityTenYears},
+		validity:     ValidityTenYears,
+		keyAlgorithm: KeyAlgorithmRSA,
+		keySize:      4096,
+	}, {
+		name:         "other algorithm resets key size",
+		policy:       &PKIPolicy{Config: &PKIConfig{Leaves: CertificatePolicy{KeySize: 4096}, Certificates: map[string]CertificatePolicy{"mcs": {KeyAlgorithm: KeyAlgorithmECDSA}}}},
+		filenameBase: "mcs",
+		cfg:          CertCfg{Validity: ValidityTenYears},
+		validity:     ValidityTenYears,
+		keyAlgorithm: KeyAlgorithmECDSA,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := tc.policy.apply(&tc.cfg, tc.filenameBase)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.validity, cfg.Validity)
+			assert.Equal(t, tc.keyAlgorithm, cfg.KeyAlgorithm)
+			assert.Equal(t, tc.keySize, cfg.KeySize)
+		})
+	}
+}
+
+func TestPKIPolicyApplyInvalid(t *testing.T) {
+	policy := &PKIPolicy{Config: &PKIConfig{
+		Leaves: CertificatePolicy{KeyAlgorithm: KeyAlgorithmECDSA},
+		Certificates: map[string]CertificatePolicy{
+			"mcs": {KeySize: 4096},
+		},
+	}}
+	_, err := policy.apply(&CertCfg{Validity: ValidityTenYears}, "mcs")
+	assert.EqualError(t, err, "invalid policy for mcs: invalid ECDSA key size 4096, must be one of [256 384]")
+}
+
+func TestSignedCertKeyExceedsSigner(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy *PKIPolicy
+		err    string
+	}{{
+		name: "capped at the signer",
+		policy: &PKIPolicy{Config: &PKIConfig{
+			Signers: CertificatePolicy{MaxValidity: &Duration{ValidityOneYear}},
+		}},
+	}, {
+		name: "certificate validity",
+		policy: &PKIPolicy{Config: &PKIConfig{
+			Signers: CertificatePolicy{MaxValidity: &Duration{ValidityOneYear}},
+			Certificates: map[string]CertificatePolicy{
+				"admin-kubeconfig-client": {Validity: &Duration{2 * ValidityOneYear}},
+			},
+		}},
+		err: "the validity of admin-kubeconfig-client (17520h0m0s) exceeds the validity of its signer admin-kubeconfig-signer (8760h0m0s)",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parents := asset.Parents{}
+			parents.Add(tc.policy, &ExternalCA{})
+			ca := &AdminKubeConfigSignerCertKey{}
+			if !assert.NoError(t, ca.Generate(parents)) {
+				return
+			}
+
+			cfg := &CertCfg{
+				Subject:   pkix.Name{CommonName: "system:admin", Organization: []string{"system:masters"}},
+				KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+				Validity:  ValidityTenYears,
+			}
+			certKey := &SignedCertKey{}
+			err := certKey.Generate(cfg, tc.policy, ca, "admin-kubeconfig-client", DoNotAppendParent)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			caCert, err := PemToCertificate(ca.Cert())
+			if !assert.NoError(t, err) {
+				return
+			}
+			cert, err := PemToCertificate(certKey.Cert())
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, caCert.NotAfter, cert.NotAfter)
+		})
+	}
+}
@@ -17,11 +17,18 @@ var _ asset.WritableAsset = (*RootCA)(nil)
 
 // Dependencies returns the dependency of the root-ca, which is empty.
 func (c *RootCA) Dependencies() []asset.Asset {
-	return []asset.Asset{}
+	return []asset.Asset{
+		&PKIPolicy{},
+		&ExternalCA{},
+	}
 }
 
 // Generate generates the root-ca key and cert pair.
 func (c *RootCA) Generate(parents asset.Parents) error {
+	policy := &PKIPolicy{}
+	externalCA := &ExternalCA{}
+	parents.Get(policy, externalCA)
+
 	cfg := &CertCfg{
 		Subject:   pkix.Name{CommonName: "root-ca", OrganizationalUnit: []string{"openshift"}},
 		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
@@ -29,7 +36,13 @@ func (c *RootCA) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	return c.SelfSignedCertKey.Generate(cfg, "root-ca")
+	return c.SelfSignedCertKey.Generate(cfg, policy, externalCA, "root-ca")
+}
+
+// Load reads the cert signed by the external CA and its key from the asset
+// directory.
+func (c *RootCA) Load(f asset.FileFetcher) (bool, error) {
+	return c.SelfSignedCertKey.load(f, "root-ca")
 }
 
 // Name returns the human-friendly name of the asset.
@@ -0,0 +1,64 @@
+package tls
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// SigningRequests are the certificate signing requests of the signers, and
+// their private keys, for the external CA to sign out of band. The
+// certificates it signs are read back from tls/<signer>.crt in the asset
+// directory.
+type SigningRequests struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*SigningRequests)(nil)
+
+// Name returns the human-friendly name of the asset.
+func (r *SigningRequests) Name() string {
+	return "Signing Requests"
+}
+
+// Dependencies returns the external CA and the signers.
+func (r *SigningRequests) Dependencies() []asset.Asset {
+	return append([]asset.Asset{&ExternalCA{}}, signerDependencies(signers())...)
+}
+
+// Generate collects the signing requests of the signers still waiting for
+// their certificates.
+func (r *SigningRequests) Generate(parents asset.Parents) error {
+	externalCA := &ExternalCA{}
+	parents.Get(externalCA)
+	if !externalCA.Configured() {
+		return errors.Errorf("signing requests need the certificate of the external CA in %s", assetFilePath(externalCACertFilename))
+	}
+	if externalCA.HasKey() {
+		logrus.Infof("The signers are signed with the key of the external CA, there is nothing to sign")
+		return nil
+	}
+
+	all := signers()
+	r.FileList = nil
+	for _, name := range sortedSignerNames(all) {
+		s := all[name]
+		parents.Get(s)
+		if len(s.Cert()) == 0 {
+			r.FileList = append(r.FileList, s.Files()...)
+		}
+	}
+	return nil
+}
+
+// Files returns the signing requests and the private keys of the signers.
+func (r *SigningRequests) Files() []*asset.File {
+	return r.FileList
+}
+
+// Load is a no-op because the signing requests are always regenerated from
+// the signers.
+func (r *SigningRequests) Load(asset.FileFetcher) (bool, error) {
+	return false, nil
+}
@@ -40,6 +40,13 @@ type CertCfg struct {
 	Subject      pkix.Name
 	Validity     time.Duration
 	IsCA         bool
+
+	// KeyAlgorithm is the algorithm of the private key. Defaults to RSA.
+	KeyAlgorithm KeyAlgorithm
+
+	// KeySize is the size of the private key, in bits: the modulus of RSA
+	// keys, 2048 by default, or the curve of ECDSA keys, 256 by default.
+	KeySize int
 }
 
 // rsaPublicKey reflects the ASN.1 structure of a PKCS#1 public key.
@@ -50,7 +57,12 @@ type rsaPublicKey struct {
 
 // PrivateKey generates an RSA Private key and returns the value
 func PrivateKey() (*rsa.PrivateKey, error) {
-	rsaKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	return privateKey(keySize)
+}
+
+// privateKey generates an RSA private key of size bits.
+func privateKey(size int) (*rsa.PrivateKey, error) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, size)
 	if err != nil {
 		return nil, errors.Wrap(err, "error generating RSA private key")
 	}
@@ -58,8 +70,52 @@ func PrivateKey() (*rsa.PrivateKey, error) {
 	return rsaKey, nil
 }
 
+// ecdsaPrivateKey generates an ECDSA private key on the NIST curve of size
+// bits.
+func ecdsaPrivateKey(size int) (*ecdsa.PrivateKey, error) {
+	var curve elliptic.Curve
+	switch size {
+	case 0, 256:
+		curve = elliptic.P256()
+	case 384:
+		curve = elliptic.P384()
+	default:
+		return nil, errors.Errorf("unsupported ECDSA key size %d", size)
+	}
+	ecKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error generating ECDSA private key")
+	}
+	return ecKey, nil
+}
+
+// certPrivateKey generates the private key of the certificate defined by
+// cfg.
+func certPrivateKey(cfg *CertCfg) (crypto.Signer, error) {
+	switch cfg.KeyAlgorithm {
+	case "", KeyAlgorithmRSA:
+		if cfg.KeySize != 0 {
+			return privateKey(cfg.KeySize)
+		}
+		return PrivateKey()
+	case KeyAlgorithmECDSA:
+		return ecdsaPrivateKey(cfg.KeySize)
+	default:
+		return nil, errors.Errorf("unsupported key algorithm %q", cfg.KeyAlgorithm)
+	}
+}
+
+// keyUsages returns the usages of a certificate for key. Only RSA keys can
+// encipher keys.
+func keyUsages(cfg *CertCfg, key crypto.PublicKey) x509.KeyUsage {
+	if _, ok := key.(*rsa.PublicKey); ok {
+		return cfg.KeyUsages
+	}
+	return cfg.KeyUsages &^ x509.KeyUsageKeyEncipherment
+}
+
 // SelfSignedCertificate creates a self signed certificate
-func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate, error) {
+func SelfSignedCertificate(cfg *CertCfg, key crypto.Signer) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
@@ -67,7 +123,7 @@ func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate
 	cert := x509.Certificate{
 		BasicConstraintsValid: true,
 		IsCA:                  cfg.IsCA,
-		KeyUsage:              cfg.KeyUsages,
+		KeyUsage:              keyUsages(cfg, key.Public()),
 		NotAfter:              time.Now().Add(cfg.Validity),
 		NotBefore:             time.Now(),
 		SerialNumber:          serial,
@@ -93,9 +149,9 @@ func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate
 func SignedCertificate(
 	cfg *CertCfg,
 	csr *x509.CertificateRequest,
-	key *rsa.PrivateKey,
+	key crypto.Signer,
 	caCert *x509.Certificate,
-	caKey *rsa.PrivateKey,
+	caKey crypto.Signer,
 ) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
@@ -106,7 +162,7 @@ func SignedCertificate(
 		DNSNames:              csr.DNSNames,
 		ExtKeyUsage:           cfg.ExtKeyUsages,
 		IPAddresses:           csr.IPAddresses,
-		KeyUsage:              cfg.KeyUsages,
+		KeyUsage:              keyUsages(cfg, key.Public()),
 		NotAfter:              time.Now().Add(cfg.Validity),
 		NotBefore:             caCert.NotBefore,
 		SerialNumber:          serial,
@@ -149,11 +205,11 @@ func generateSubjectKeyID(pub crypto.PublicKey) ([]byte, error) {
 }
 
 // GenerateSignedCertificate generate a key and cert defined by CertCfg and signed by CA.
-func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
-	cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
+func GenerateSignedCertificate(caKey crypto.Signer, caCert *x509.Certificate,
+	cfg *CertCfg) (crypto.Signer, *x509.Certificate, error) {
 
 	// create a private key
-	key, err := PrivateKey()
+	key, err := certPrivateKey(cfg)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
 	}
@@ -178,8 +234,8 @@ func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
 }
 
 // GenerateSelfSignedCertificate generates a key/cert pair defined by CertCfg.
-func GenerateSelfSignedCertificate(cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
-	key, err := PrivateKey()
+func GenerateSelfSignedCertificate(cfg *CertCfg) (crypto.Signer, *x509.Certificate, error) {
+	key, err := certPrivateKey(cfg)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
 	}
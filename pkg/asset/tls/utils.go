@@ -1,6 +1,8 @@
 package tls
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -20,6 +22,27 @@ func PrivateKeyToPem(key *rsa.PrivateKey) []byte {
 	return keyinPem
 }
 
+// SignerToPem converts an RSA or ECDSA private key to a pem string.
+func SignerToPem(key crypto.Signer) ([]byte, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return PrivateKeyToPem(key), nil
+	case *ecdsa.PrivateKey:
+		keyInBytes, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal ECDSA private key")
+		}
+		return pem.EncodeToMemory(
+			&pem.Block{
+				Type:  "EC PRIVATE KEY",
+				Bytes: keyInBytes,
+			},
+		), nil
+	default:
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+}
+
 // CertToPem converts an x509.Certificate object to a pem string
 func CertToPem(cert *x509.Certificate) []byte {
 	certInPem := pem.EncodeToMemory(
@@ -66,6 +89,36 @@ func PemToPrivateKey(data []byte) (*rsa.PrivateKey, error) {
 	return x509.ParsePKCS1PrivateKey(block.Bytes)
 }
 
+// PemToSigner converts a data block to an RSA or ECDSA private key. PKCS#1
+// RSA keys, SEC 1 EC keys and PKCS#8 keys are accepted.
+func PemToSigner(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("could not find a PEM block in the private key")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case *ecdsa.PrivateKey:
+			return key, nil
+		default:
+			return nil, errors.Errorf("unsupported private key type %T", key)
+		}
+	default:
+		return nil, errors.Errorf("unsupported private key PEM block %q", block.Type)
+	}
+}
+
 // PemToPublicKey converts a data block to rsa.PublicKey.
 func PemToPublicKey(data []byte) (*rsa.PublicKey, error) {
 	block, _ := pem.Decode(data)
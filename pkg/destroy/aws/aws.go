@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -36,6 +37,10 @@ var (
 	exists = struct{}{}
 )
 
+// defaultConcurrency is the number of resources deleted in parallel when
+// ClusterUninstaller.Concurrency is not set.
+const defaultConcurrency = 10
+
 // Filter holds the key/value pairs for the tags we will be matching against.
 //
 // A resource matches the filter if all of the key/value pairs are in its tags.
@@ -68,6 +73,20 @@ type ClusterUninstaller struct {
 	// new session will be created based on the usual credential
 	// configuration (AWS_PROFILE, AWS_ACCESS_KEY_ID, etc.).
 	Session *session.Session
+
+	// Concurrency is the maximum number of resources deleted in
+	// parallel. If zero, defaultConcurrency is used.
+	Concurrency int
+
+	// Exclusions selects resources that are left in place even though
+	// they match the filters.
+	Exclusions providers.Exclusions
+
+	recorder *providers.Recorder
+	// keepVPCs is set once a resource that lives in a VPC is excluded,
+	// since deleting a VPC removes everything left in it.
+	keepVPCs bool
+	keptVPCs sets.String
 }
 
 // New returns an AWS destroyer from ClusterMetadata.
@@ -106,53 +125,43 @@ func (o *ClusterUninstaller) validate() error {
 }
 
 // Run is the entrypoint to start the uninstall process
-func (o *ClusterUninstaller) Run() error {
-	_, err := o.RunWithContext(context.Background())
-	return err
+func (o *ClusterUninstaller) Run(ctx context.Context, options providers.RunOptions) (*providers.Report, error) {
+	o.Concurrency = options.ConcurrencyOrDefault(o.Concurrency)
+	o.Exclusions = options.Exclusions
+	return o.RunWithReport(ctx)
 }
 
 // RunWithContext runs the uninstall process with a context.
 // The first return is the list of ARNs for resources that could not be destroyed.
 func (o *ClusterUninstaller) RunWithContext(ctx context.Context) ([]string, error) {
-	err := o.validate()
-	if err != nil {
+	report, err := o.RunWithReport(ctx)
+	if report == nil {
 		return nil, err
 	}
-
-	awsSession := o.Session
-	if awsSession == nil {
-		// Relying on appropriate AWS ENV vars (eg AWS_PROFILE, AWS_ACCESS_KEY_ID, etc)
-		awsSession, err = session.NewSession(aws.NewConfig().WithRegion(o.Region))
-		if err != nil {
-			return nil, err
-		}
+	leftovers := make([]string, 0, len(report.Failed))
+	for _, failed := range report.Failed {
+		leftovers = append(leftovers, failed.Name)
 	}
-	awsSession.Handlers.Build.PushBackNamed(request.NamedHandler{
-		Name: "openshiftInstaller.OpenshiftInstallerUserAgentHandler",
-		Fn:   request.MakeAddToUserAgentHandler("OpenShift/4.x Destroyer", version.Raw),
-	})
+	return leftovers, err
+}
 
-	tagClients := []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI{
-		resourcegroupstaggingapi.New(awsSession),
+// RunWithReport runs the uninstall process with a context and reports the
+// deleted resources, the shared resources whose cluster tag was removed and
+// the resources that could not be destroyed.
+func (o *ClusterUninstaller) RunWithReport(ctx context.Context) (*providers.Report, error) {
+	err := o.validate()
+	if err != nil {
+		return nil, err
 	}
 
-	switch o.Region {
-	case endpoints.CnNorth1RegionID, endpoints.CnNorthwest1RegionID:
-		if o.Region != endpoints.CnNorthwest1RegionID {
-			tagClients = append(tagClients,
-				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.CnNorthwest1RegionID)))
-		}
-	case endpoints.UsGovEast1RegionID, endpoints.UsGovWest1RegionID:
-		if o.Region != endpoints.UsGovWest1RegionID {
-			tagClients = append(tagClients,
-				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.UsGovWest1RegionID)))
-		}
-	default:
-		if o.Region != endpoints.UsEast1RegionID {
-			tagClients = append(tagClients,
-				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.UsEast1RegionID)))
-		}
+	awsSession, err := o.session()
+	if err != nil {
+		return nil, err
 	}
+	tagClients := o.tagClients(awsSession)
+	o.recorder = &providers.Recorder{}
+	o.keepVPCs = false
+	o.keptVPCs = nil
 
 	iamClient := iam.New(awsSession)
 	iamRoleSearch := &iamRoleSearch{
@@ -173,7 +182,7 @@ func (o *ClusterUninstaller) RunWithContext(ctx context.Context) ([]string, erro
 	if err != nil {
 		o.Logger.WithError(err).Info("error while finding resources to delete")
 		if err := ctx.Err(); err != nil {
-			return resourcesToDelete.UnsortedList(), err
+			return newReport(o.recorder, deleted, nil, resourcesToDelete, nil, err), err
 		}
 	}
 
@@ -211,7 +220,7 @@ func (o *ClusterUninstaller) RunWithContext(ctx context.Context) ([]string, erro
 		ctx.Done(),
 	)
 	if err != nil {
-		return resourcesToDelete.UnsortedList(), err
+		return newReport(o.recorder, deleted, nil, resourcesToDelete, tracker, err), err
 	}
 
 	// Delete the rest of the resources.
@@ -245,15 +254,149 @@ func (o *ClusterUninstaller) RunWithContext(ctx context.Context) ([]string, erro
 		ctx.Done(),
 	)
 	if err != nil {
-		return resourcesToDelete.UnsortedList(), err
+		return newReport(o.recorder, deleted, nil, resourcesToDelete, tracker, err), err
+	}
+
+	shared, err := removeSharedTags(ctx, tagClients, o.Filters, o.Logger)
+	if err != nil {
+		return newReport(o.recorder, deleted, shared, nil, tracker, err), err
+	}
+
+	return newReport(o.recorder, deleted, shared, nil, tracker, nil), nil
+}
+
+// newReport adds to recorder the ARNs of the deleted resources, of the
+// shared resources that were untagged and of the resources left behind, and
+// builds the report of a run.
+func newReport(recorder *providers.Recorder, deleted, shared, leftovers sets.String, tracker *errorTracker, err error) *providers.Report {
+	for _, arnString := range deleted.UnsortedList() {
+		recorder.Deleted(arnResource(arnString))
+	}
+	for _, arnString := range shared.UnsortedList() {
+		recorder.SkippedShared(arnResource(arnString))
+	}
+	resources := make([]providers.Resource, 0, leftovers.Len())
+	for _, arnString := range leftovers.UnsortedList() {
+		resource := arnResource(arnString)
+		if tracker != nil {
+			recorder.Error(resource, tracker.lastError(arnString))
+		}
+		resources = append(resources, resource)
+	}
+	return recorder.Report(resources, err)
+}
+
+// session returns the AWS session to use for the uninstall, creating one
+// from the environment if none was provided.
+func (o *ClusterUninstaller) session() (*session.Session, error) {
+	awsSession := o.Session
+	if awsSession == nil {
+		var err error
+		// Relying on appropriate AWS ENV vars (eg AWS_PROFILE, AWS_ACCESS_KEY_ID, etc)
+		awsSession, err = session.NewSession(aws.NewConfig().WithRegion(o.Region))
+		if err != nil {
+			return nil, err
+		}
+	}
+	awsSession.Handlers.Build.PushBackNamed(request.NamedHandler{
+		Name: "openshiftInstaller.OpenshiftInstallerUserAgentHandler",
+		Fn:   request.MakeAddToUserAgentHandler("OpenShift/4.x Destroyer", version.Raw),
+	})
+	return awsSession, nil
+}
+
+// tagClients returns the tagging API clients for the cluster region and for
+// the partition's global region, where IAM and Route 53 resources live.
+func (o *ClusterUninstaller) tagClients(awsSession *session.Session) []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI {
+	tagClients := []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI{
+		resourcegroupstaggingapi.New(awsSession),
+	}
+
+	switch o.Region {
+	case endpoints.CnNorth1RegionID, endpoints.CnNorthwest1RegionID:
+		if o.Region != endpoints.CnNorthwest1RegionID {
+			tagClients = append(tagClients,
+				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.CnNorthwest1RegionID)))
+		}
+	case endpoints.UsGovEast1RegionID, endpoints.UsGovWest1RegionID:
+		if o.Region != endpoints.UsGovWest1RegionID {
+			tagClients = append(tagClients,
+				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.UsGovWest1RegionID)))
+		}
+	default:
+		if o.Region != endpoints.UsEast1RegionID {
+			tagClients = append(tagClients,
+				resourcegroupstaggingapi.New(awsSession, aws.NewConfig().WithRegion(endpoints.UsEast1RegionID)))
+		}
+	}
+	return tagClients
+}
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+func (o *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	return o.InventoryWithContext(context.Background())
+}
+
+// InventoryWithContext finds the resources that RunWithContext would remove.
+// Instances are listed alongside the tagged, IAM and untaggable resources.
+func (o *ClusterUninstaller) InventoryWithContext(ctx context.Context) ([]providers.Resource, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	awsSession, err := o.session()
+	if err != nil {
+		return nil, err
 	}
 
-	err = removeSharedTags(ctx, tagClients, o.Filters, o.Logger)
+	iamClient := iam.New(awsSession)
+	iamRoleSearch := &iamRoleSearch{
+		client:  iamClient,
+		filters: o.Filters,
+		logger:  o.Logger,
+	}
+	iamUserSearch := &iamUserSearch{
+		client:  iamClient,
+		filters: o.Filters,
+		logger:  o.Logger,
+	}
+
+	// Terminated instances are added to this set so that they are not reported.
+	terminated := sets.NewString()
+	instances, err := o.findEC2Instances(ctx, ec2.New(awsSession), terminated)
 	if err != nil {
 		return nil, err
 	}
+	found, _, err := o.findResourcesToDelete(ctx, o.tagClients(awsSession), iamClient, iamRoleSearch, iamUserSearch, terminated)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while finding resources to delete")
+	}
+	found.Insert(instances...)
+
+	resources := make([]providers.Resource, 0, found.Len())
+	for _, arnString := range found.List() {
+		resources = append(resources, arnResource(arnString))
+	}
+	providers.SortResources(resources)
+	return resources, nil
+}
 
-	return nil, nil
+// arnResource describes the resource identified by arnString. The type is
+// the service and resource type from the ARN, e.g. "ec2:instance".
+func arnResource(arnString string) providers.Resource {
+	parsed, err := arn.Parse(arnString)
+	if err != nil {
+		return providers.Resource{Type: "unknown", Name: arnString}
+	}
+	resourceType := parsed.Resource
+	if i := strings.IndexAny(resourceType, "/:"); i != -1 {
+		resourceType = resourceType[:i]
+	}
+	return providers.Resource{
+		Type:     fmt.Sprintf("%s:%s", parsed.Service, resourceType),
+		Name:     arnString,
+		Location: parsed.Region,
+	}
 }
 
 // findEC2Instances returns the EC2 instances with tags that satisfy the filters.
@@ -299,7 +442,7 @@ func (o *ClusterUninstaller) findEC2Instances(ctx context.Context, ec2Client *ec
 								instanceLogger.Info("Terminated")
 								deleted.Insert(arn)
 							}
-						} else {
+						} else if !o.excluded(arn, ec2TagsToMap(instance.Tags)) {
 							resources = append(resources, arn)
 						}
 					}
@@ -368,9 +511,90 @@ func (o *ClusterUninstaller) findResourcesToDelete(
 	}
 	resources = resources.Union(untaggableResources)
 
+	o.removeKeptVPCs(resources)
+
 	return resources, tagClientsWithResources, utilerrors.NewAggregate(errs)
 }
 
+// excluded reports whether the resource identified by arnString matches an
+// exclusion. tags may be nil when the tags of the resource are unknown.
+func (o *ClusterUninstaller) excluded(arnString string, tags map[string]string) bool {
+	resource := arnResource(arnString)
+	if !o.Exclusions.Skip(o.Logger, o.recorder, resource, tags) {
+		return false
+	}
+	if vpcResourceTypes.Has(resource.Type) {
+		o.keepVPCs = true
+	}
+	return true
+}
+
+// withoutExcluded returns the ARNs in resources that match no exclusion.
+func (o *ClusterUninstaller) withoutExcluded(resources sets.String) sets.String {
+	for _, arnString := range resources.UnsortedList() {
+		if o.excluded(arnString, nil) {
+			resources.Delete(arnString)
+		}
+	}
+	return resources
+}
+
+// vpcResourceTypes are the resource types that deleteEC2VPC removes along
+// with their VPC.
+var vpcResourceTypes = sets.NewString(
+	"ec2:instance",
+	"ec2:natgateway",
+	"ec2:network-interface",
+	"ec2:route-table",
+	"ec2:security-group",
+	"ec2:subnet",
+	"ec2:vpc-endpoint",
+	"elasticloadbalancing:loadbalancer",
+)
+
+// removeKeptVPCs removes the VPCs from resources once a resource that lives
+// in a VPC is excluded, and records them as excluded.
+func (o *ClusterUninstaller) removeKeptVPCs(resources sets.String) {
+	if !o.keepVPCs {
+		return
+	}
+	for _, arnString := range resources.UnsortedList() {
+		resource := arnResource(arnString)
+		if resource.Type != "ec2:vpc" {
+			continue
+		}
+		resources.Delete(arnString)
+		if o.keptVPCs == nil {
+			o.keptVPCs = sets.NewString()
+		}
+		if !o.keptVPCs.Has(arnString) {
+			o.Logger.WithField("vpc", arnString).Info("Keeping VPC with excluded resources")
+			o.keptVPCs.Insert(arnString)
+		}
+		if o.recorder != nil {
+			o.recorder.Excluded(resource)
+		}
+	}
+}
+
+// ec2TagsToMap converts EC2 tags to a map.
+func ec2TagsToMap(tags []*ec2.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		m[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return m
+}
+
+// taggingTagsToMap converts tags returned by the tagging API to a map.
+func taggingTagsToMap(tags []*resourcegroupstaggingapi.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		m[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return m
+}
+
 // findResourcesByTag returns the resources with tags that satisfy the filters.
 //   tagClients - clients of the tagging API to use to search for resources.
 //   deleted - the resources that have already been deleted. Any resources specified in this set will be ignored.
@@ -395,7 +619,7 @@ func (o *ClusterUninstaller) findResourcesByTag(
 			func(results *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
 				for _, resource := range results.ResourceTagMappingList {
 					arnString := *resource.ResourceARN
-					if !deleted.Has(arnString) {
+					if !deleted.Has(arnString) && !o.excluded(arnString, taggingTagsToMap(resource.Tags)) {
 						resources.Insert(arnString)
 					}
 				}
@@ -420,7 +644,7 @@ func (o *ClusterUninstaller) findIAMRoles(ctx context.Context, search *iamRoleSe
 		o.Logger.Info(err)
 		return nil, err
 	}
-	return sets.NewString(resources...).Difference(deleted), nil
+	return o.withoutExcluded(sets.NewString(resources...).Difference(deleted)), nil
 }
 
 // findIAMUsers returns the IAM users for the cluster.
@@ -432,7 +656,7 @@ func (o *ClusterUninstaller) findIAMUsers(ctx context.Context, search *iamUserSe
 		o.Logger.Info(err)
 		return nil, err
 	}
-	return sets.NewString(resources...).Difference(deleted), nil
+	return o.withoutExcluded(sets.NewString(resources...).Difference(deleted)), nil
 }
 
 // findUntaggableResources returns the resources for the cluster that cannot be tagged.
@@ -450,18 +674,26 @@ func (o *ClusterUninstaller) findUntaggableResources(ctx context.Context, iamCli
 			return resources, errors.Wrap(err, "failed to get IAM instance profile")
 		}
 		arnString := *response.InstanceProfile.Arn
-		if !deleted.Has(arnString) {
+		if !deleted.Has(arnString) && !o.excluded(arnString, nil) {
 			resources.Insert(arnString)
 		}
 	}
 	return resources, nil
 }
 
-// deleteResources deletes the specified resources.
+// deleteResources deletes the specified resources, up to o.Concurrency at a time.
 //   resources - the resources to be deleted.
 // The first return is the ARNs of the resources that were successfully deleted
 func (o *ClusterUninstaller) deleteResources(ctx context.Context, awsSession *session.Session, resources []string, tracker *errorTracker) (sets.String, error) {
+	concurrency := o.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
 	deleted := sets.NewString()
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
 	for _, arnString := range resources {
 		logger := o.Logger.WithField("arn", arnString)
 		parsedARN, err := arn.Parse(arnString)
@@ -469,16 +701,28 @@ func (o *ClusterUninstaller) deleteResources(ctx context.Context, awsSession *se
 			logger.WithError(err).Debug("could not parse ARN")
 			continue
 		}
-		if err := deleteARN(ctx, awsSession, parsedARN, o.Logger); err != nil {
-			tracker.suppressWarning(arnString, err, logger)
-			if err := ctx.Err(); err != nil {
-				return deleted, err
-			}
-			continue
+		if ctx.Err() != nil {
+			break
 		}
-		deleted.Insert(arnString)
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(arnString string, parsedARN arn.ARN, logger logrus.FieldLogger) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			if err := deleteARN(ctx, awsSession, parsedARN, o.Logger); err != nil {
+				tracker.suppressWarning(arnString, err, logger)
+				return
+			}
+			lock.Lock()
+			deleted.Insert(arnString)
+			lock.Unlock()
+		}(arnString, parsedARN, logger)
 	}
-	return deleted, nil
+	wg.Wait()
+	return deleted, ctx.Err()
 }
 
 func splitSlash(name string, input string) (base string, suffix string, err error) {
@@ -2011,13 +2255,16 @@ func isBucketNotFound(err interface{}) bool {
 	return false
 }
 
-func removeSharedTags(ctx context.Context, tagClients []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI, filters []Filter, logger logrus.FieldLogger) error {
+// removeSharedTags removes the cluster's shared tags and returns the ARNs of
+// the untagged resources.
+func removeSharedTags(ctx context.Context, tagClients []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI, filters []Filter, logger logrus.FieldLogger) (sets.String, error) {
+	untagged := sets.NewString()
 	for _, filter := range filters {
 		for key, value := range filter {
 			if strings.HasPrefix(key, "kubernetes.io/cluster/") {
 				if value == "owned" {
-					if err := removeSharedTag(ctx, tagClients, key, logger); err != nil {
-						return err
+					if err := removeSharedTag(ctx, tagClients, key, untagged, logger); err != nil {
+						return untagged, err
 					}
 				} else {
 					logger.Warnf("Ignoring non-owned cluster key %s: %s for shared-tag removal", key, value)
@@ -2026,10 +2273,10 @@ func removeSharedTags(ctx context.Context, tagClients []*resourcegroupstaggingap
 		}
 	}
 
-	return nil
+	return untagged, nil
 }
 
-func removeSharedTag(ctx context.Context, tagClients []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI, key string, logger logrus.FieldLogger) error {
+func removeSharedTag(ctx context.Context, tagClients []*resourcegroupstaggingapi.ResourceGroupsTaggingAPI, key string, untagged sets.String, logger logrus.FieldLogger) error {
 	request := &resourcegroupstaggingapi.UntagResourcesInput{
 		TagKeys: []*string{aws.String(key)},
 	}
@@ -2085,6 +2332,7 @@ func removeSharedTag(ctx context.Context, tagClients []*resourcegroupstaggingapi
 					arn := arns[i+j]
 					logger.WithField("arn", arn).Infof("Removed tag %s: shared", key)
 					removed[arn] = exists
+					untagged.Insert(arn)
 				}
 			}
 		}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -10,16 +11,29 @@ const (
 	suppressDuration = time.Minute * 5
 )
 
-// errorTracker holds a history of errors
+// errorTracker holds a history of errors. It is safe for concurrent use.
 type errorTracker struct {
+	lock    sync.Mutex
 	history map[string]time.Time
+	last    map[string]error
+}
+
+// lastError returns the last error seen for identifier, or nil.
+func (o *errorTracker) lastError(identifier string) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.last[identifier]
 }
 
 // suppressWarning logs errors WARN once every duration and the rest to DEBUG
 func (o *errorTracker) suppressWarning(identifier string, err error, logger logrus.FieldLogger) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
 	if o.history == nil {
 		o.history = map[string]time.Time{}
+		o.last = map[string]error{}
 	}
+	o.last[identifier] = err
 	if firstSeen, ok := o.history[identifier]; ok {
 		if time.Since(firstSeen) > suppressDuration {
 			logger.Warn(err)
@@ -0,0 +1,208 @@
+package aws
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	awssession "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+const (
+	clusterTagPrefix = "kubernetes.io/cluster/"
+	clusterIDTag     = "openshiftClusterID"
+)
+
+// installerNameRE matches the Name tags the installer gives to the
+// resources of a cluster after its infra ID, e.g. <infraID>-master-sg.
+var installerNameRE = regexp.MustCompile(`^-(?:master-sg|worker-sg|bootstrap|bootstrap-sg|master-\d+|master-role|worker-role|int|sint)$`)
+
+// FindOrphans lists the clusters with resources tagged
+// kubernetes.io/cluster/<infraID>: owned in the scope's region. Other
+// Kubernetes clusters, such as EKS or kops clusters, set the same tag, so only
+// the clusters with a resource carrying an installer tag or name are listed.
+// Resources are counted from the tagging API, and the cluster age is taken
+// from the launch time of its instances and the creation time of its load
+// balancers.
+func FindOrphans(logger logrus.FieldLogger, scope *providers.Scope) ([]providers.Orphan, error) {
+	if scope.Region == "" {
+		return nil, errors.New("a region is required to search for AWS clusters")
+	}
+	awsSession, err := awssession.GetSessionWithOptions(awssession.WithRegion(scope.Region))
+	if err != nil {
+		return nil, err
+	}
+	uninstaller := &ClusterUninstaller{Region: scope.Region, Logger: logger, Session: awsSession}
+	awsSession, err = uninstaller.session()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	orphans := providers.OrphanSet{}
+	clusterIDs := map[string]string{}
+	installer := map[string]bool{}
+	loadBalancers := map[string]string{}
+	// The first client searches the scope's region. The others search the
+	// partition's global region, which also holds the resources of clusters
+	// installed there, so they only add to clusters already found.
+	for i, tagClient := range uninstaller.tagClients(awsSession) {
+		regional := i == 0
+		logger.Debugf("search for tagged resources in %s", *tagClient.Config.Region)
+		err := tagClient.GetResourcesPagesWithContext(
+			ctx,
+			&resourcegroupstaggingapi.GetResourcesInput{ResourcesPerPage: aws.Int64(100)},
+			func(results *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+				for _, resource := range results.ResourceTagMappingList {
+					tags := make(map[string]string, len(resource.Tags))
+					for _, tag := range resource.Tags {
+						tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+					}
+					infraID := ownedInfraID(tags)
+					if _, ok := orphans[infraID]; infraID == "" || !regional && !ok {
+						continue
+					}
+					orphans.Add(infraID, time.Time{})
+					if clusterID := tags[clusterIDTag]; clusterID != "" {
+						clusterIDs[infraID] = clusterID
+					}
+					if installerResource(infraID, tags) {
+						installer[infraID] = true
+					}
+					arnString := aws.StringValue(resource.ResourceARN)
+					if parsed, err := arn.Parse(arnString); err == nil && parsed.Service == "elasticloadbalancing" && strings.HasPrefix(parsed.Resource, "loadbalancer/net/") {
+						loadBalancers[arnString] = infraID
+					}
+				}
+				return !lastPage
+			},
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "get tagged resources")
+		}
+	}
+
+	err = ec2.New(awsSession).DescribeInstancesPagesWithContext(
+		ctx,
+		&ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{{
+				Name:   aws.String("tag-key"),
+				Values: []*string{aws.String(clusterTagPrefix + "*")},
+			}},
+		},
+		func(results *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			for _, reservation := range results.Reservations {
+				for _, instance := range reservation.Instances {
+					tags := make(map[string]string, len(instance.Tags))
+					for _, tag := range instance.Tags {
+						tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+					}
+					infraID := ownedInfraID(tags)
+					if orphan, ok := orphans[infraID]; ok {
+						orphan.Observe(aws.TimeValue(instance.LaunchTime))
+						if installerResource(infraID, tags) {
+							installer[infraID] = true
+						}
+					}
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "get ec2 instances")
+	}
+
+	if err := observeLoadBalancers(ctx, elbv2.New(awsSession), loadBalancers, orphans); err != nil {
+		return nil, err
+	}
+
+	var result []providers.Orphan
+	for _, orphan := range orphans.List() {
+		if !installer[orphan.InfraID] {
+			logger.Debugf("skipping %s, none of its resources has an installer tag or name", orphan.InfraID)
+			continue
+		}
+		result = append(result, orphan)
+	}
+	for i := range result {
+		orphan := &result[i]
+		identifier := []map[string]string{{clusterTagPrefix + orphan.InfraID: "owned"}}
+		clusterID := clusterIDs[orphan.InfraID]
+		if clusterID != "" {
+			identifier = append(identifier, map[string]string{clusterIDTag: clusterID})
+		}
+		orphan.Metadata = &types.ClusterMetadata{
+			InfraID:   orphan.InfraID,
+			ClusterID: clusterID,
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				AWS: &awstypes.Metadata{
+					Region:     scope.Region,
+					Identifier: identifier,
+				},
+			},
+		}
+	}
+	return result, nil
+}
+
+// observeLoadBalancers records the creation time of the network load
+// balancers in loadBalancers, which maps ARNs to infra IDs.
+func observeLoadBalancers(ctx context.Context, client *elbv2.ELBV2, loadBalancers map[string]string, orphans providers.OrphanSet) error {
+	arns := make([]*string, 0, len(loadBalancers))
+	for arnString := range loadBalancers {
+		arns = append(arns, aws.String(arnString))
+	}
+	// DescribeLoadBalancers accepts at most 20 ARNs per call.
+	for len(arns) > 0 {
+		batch := arns
+		if len(batch) > 20 {
+			batch = batch[:20]
+		}
+		arns = arns[len(batch):]
+
+		output, err := client.DescribeLoadBalancersWithContext(ctx, &elbv2.DescribeLoadBalancersInput{LoadBalancerArns: batch})
+		if err != nil {
+			return errors.Wrap(err, "describe load balancers")
+		}
+		for _, lb := range output.LoadBalancers {
+			if orphan, ok := orphans[loadBalancers[aws.StringValue(lb.LoadBalancerArn)]]; ok {
+				orphan.Observe(aws.TimeValue(lb.CreatedTime))
+			}
+		}
+	}
+	return nil
+}
+
+// installerResource returns whether the tags of a resource of the cluster
+// infraID show that the installer created it.
+func installerResource(infraID string, tags map[string]string) bool {
+	if tags[clusterIDTag] != "" {
+		return true
+	}
+	name := tags["Name"]
+	return strings.HasPrefix(name, infraID) && installerNameRE.MatchString(strings.TrimPrefix(name, infraID))
+}
+
+// ownedInfraID returns the infra ID from a kubernetes.io/cluster/<infraID>:
+// owned tag, or "" if there is none.
+func ownedInfraID(tags map[string]string) string {
+	for key, value := range tags {
+		if value == "owned" && strings.HasPrefix(key, clusterTagPrefix) {
+			return strings.TrimPrefix(key, clusterTagPrefix)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallerResource(t *testing.T) {
+	cases := []struct {
+		name     string
+		tags     map[string]string
+		expected bool
+	}{{
+		name:     "cluster ID tag",
+		tags:     map[string]string{"kubernetes.io/cluster/test-abc12": "owned", "openshiftClusterID": "0000-1111"},
+		expected: true,
+	}, {
+		name:     "master security group",
+		tags:     map[string]string{"kubernetes.io/cluster/test-abc12": "owned", "Name": "test-abc12-master-sg"},
+		expected: true,
+	}, {
+		name:     "master instance",
+		tags:     map[string]string{"kubernetes.io/cluster/test-abc12": "owned", "Name": "test-abc12-master-2"},
+		expected: true,
+	}, {
+		name:     "eks node",
+		tags:     map[string]string{"kubernetes.io/cluster/test-abc12": "owned", "Name": "test-abc12-ng-1-Node", "eks:nodegroup-name": "ng-1"},
+		expected: false,
+	}, {
+		name:     "kops master",
+		tags:     map[string]string{"kubernetes.io/cluster/test-abc12": "owned", "Name": "master-us-east-1a.masters.test-abc12"},
+		expected: false,
+	}, {
+		name:     "other cluster name",
+		tags:     map[string]string{"kubernetes.io/cluster/test-abc12": "owned", "Name": "test-abc12-other-master-sg"},
+		expected: false,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, installerResource("test-abc12", tc.tags))
+		})
+	}
+}
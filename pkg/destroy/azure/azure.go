@@ -41,6 +41,8 @@ type ClusterUninstaller struct {
 	Logger logrus.FieldLogger
 
 	resourceGroupsClient    resources.GroupsClient
+	resourcesClient         resources.Client
+	providersClient         resources.ProvidersClient
 	zonesClient             dns.ZonesClient
 	recordsClient           dns.RecordSetsClient
 	privateRecordSetsClient privatedns.RecordSetsClient
@@ -53,6 +55,12 @@ func (o *ClusterUninstaller) configureClients() {
 	o.resourceGroupsClient = resources.NewGroupsClientWithBaseURI(o.Environment.ResourceManagerEndpoint, o.SubscriptionID)
 	o.resourceGroupsClient.Authorizer = o.Authorizer
 
+	o.resourcesClient = resources.NewClientWithBaseURI(o.Environment.ResourceManagerEndpoint, o.SubscriptionID)
+	o.resourcesClient.Authorizer = o.Authorizer
+
+	o.providersClient = resources.NewProvidersClientWithBaseURI(o.Environment.ResourceManagerEndpoint, o.SubscriptionID)
+	o.providersClient.Authorizer = o.Authorizer
+
 	o.zonesClient = dns.NewZonesClientWithBaseURI(o.Environment.ResourceManagerEndpoint, o.SubscriptionID)
 	o.zonesClient.Authorizer = o.Authorizer
 
@@ -100,8 +108,24 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	}, nil
 }
 
+// skipFunc reports whether a resource with the given tags must be left in
+// place.
+type skipFunc func(resource providers.Resource, tags map[string]string) bool
+
 // Run is the entrypoint to start the uninstall process.
-func (o *ClusterUninstaller) Run() error {
+func (o *ClusterUninstaller) Run(ctx context.Context, options providers.RunOptions) (*providers.Report, error) {
+	return providers.RunWithInventory(o.Logger, o.Inventory, func(recorder *providers.Recorder) error {
+		return o.destroy(ctx, func(resource providers.Resource, tags map[string]string) bool {
+			return options.Exclusions.Skip(o.Logger, recorder, resource, tags)
+		})
+	})
+}
+
+// destroy removes the cluster resources that skip does not select, giving up
+// after two hours or when ctx is done. Azure deletes a resource group as a
+// whole, so when the group or any resource in it is excluded, the other
+// resources of the group are deleted one by one and the group is kept.
+func (o *ClusterUninstaller) destroy(ctx context.Context, skip skipFunc) error {
 	var errs []error
 	var err error
 
@@ -109,14 +133,14 @@ func (o *ClusterUninstaller) Run() error {
 
 	// 2 hours
 	timeout := 120 * time.Minute
-	waitCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	wait.UntilWithContext(
 		waitCtx,
 		func(ctx context.Context) {
 			o.Logger.Debugf("deleting public records")
-			err = deletePublicRecords(ctx, o.zonesClient, o.recordsClient, o.privateZonesClient, o.privateRecordSetsClient, o.Logger, o.ResourceGroupName)
+			err = deletePublicRecords(ctx, o.zonesClient, o.recordsClient, o.privateZonesClient, o.privateRecordSetsClient, o.Logger, skip, o.ResourceGroupName)
 			if err != nil {
 				o.Logger.Debug(err)
 				if isAuthError(err) {
@@ -138,12 +162,26 @@ func (o *ClusterUninstaller) Run() error {
 	deadline, _ := waitCtx.Deadline()
 	diff := time.Until(deadline)
 	if diff > 0 {
-		waitCtx, cancel = context.WithTimeout(context.Background(), diff)
+		waitCtx, cancel = context.WithTimeout(ctx, diff)
 	}
 
 	wait.UntilWithContext(
 		waitCtx,
 		func(ctx context.Context) {
+			kept, err := o.deleteResourcesOfKeptGroup(ctx, skip)
+			if err != nil {
+				o.Logger.Debug(err)
+				if isAuthError(err) {
+					cancel()
+					errs = append(errs, errors.Wrap(err, "unable to authenticate when deleting the resources of the resource group"))
+				}
+				return
+			}
+			if kept {
+				o.Logger.WithField("resource group", o.ResourceGroupName).Info("Keeping resource group with excluded resources")
+				cancel()
+				return
+			}
 			o.Logger.Debugf("deleting resource group")
 			err = deleteResourceGroup(ctx, o.resourceGroupsClient, o.Logger, o.ResourceGroupName)
 			if err != nil {
@@ -167,14 +205,14 @@ func (o *ClusterUninstaller) Run() error {
 	deadline, _ = waitCtx.Deadline()
 	diff = time.Until(deadline)
 	if diff > 0 {
-		waitCtx, cancel = context.WithTimeout(context.Background(), diff)
+		waitCtx, cancel = context.WithTimeout(ctx, diff)
 	}
 
 	wait.UntilWithContext(
 		waitCtx,
 		func(ctx context.Context) {
 			o.Logger.Debugf("deleting application registrations")
-			err = deleteApplicationRegistrations(ctx, o.applicationsClient, o.serviceprincipalsClient, o.Logger, o.InfraID)
+			err = deleteApplicationRegistrations(ctx, o.applicationsClient, o.serviceprincipalsClient, o.Logger, skip, o.InfraID)
 			if err != nil {
 				o.Logger.Debug(err)
 				if isAuthError(err) {
@@ -196,7 +234,135 @@ func (o *ClusterUninstaller) Run() error {
 	return utilerrors.NewAggregate(errs)
 }
 
-func deletePublicRecords(ctx context.Context, dnsClient dns.ZonesClient, recordsClient dns.RecordSetsClient, privateDNSClient privatedns.PrivateZonesClient, privateRecordsClient privatedns.RecordSetsClient, logger logrus.FieldLogger, rgName string) error {
+// deleteResourcesOfKeptGroup deletes the resources of the resource group
+// one by one when the group or any resource in it is excluded, and reports
+// whether it did, in which case the group is kept. It returns false when the
+// group does not exist or nothing in it is excluded, so that the group can
+// be deleted as a whole. Resources that depend on others fail to delete
+// until those are gone, so an error asks for another pass.
+func (o *ClusterUninstaller) deleteResourcesOfKeptGroup(ctx context.Context, skip skipFunc) (bool, error) {
+	group, err := o.resourceGroupsClient.Get(ctx, o.ResourceGroupName)
+	if err != nil {
+		if wasNotFound(group.Response.Response) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get resource group %s", o.ResourceGroupName)
+	}
+	kept := skip(providers.Resource{
+		Type:     "resourceGroup",
+		Name:     o.ResourceGroupName,
+		Location: to.String(group.Location),
+	}, tagsToMap(group.Tags))
+	var deletable []resources.GenericResourceExpanded
+	for page, err := o.resourcesClient.ListByResourceGroup(ctx, o.ResourceGroupName, "", "", to.Int32Ptr(100)); page.NotDone(); err = page.NextWithContext(ctx) {
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to list resources in %s", o.ResourceGroupName)
+		}
+		for _, resource := range page.Values() {
+			if skip(providers.Resource{
+				Type:     to.String(resource.Type),
+				Name:     to.String(resource.ID),
+				Location: to.String(resource.Location),
+			}, tagsToMap(resource.Tags)) {
+				kept = true
+				continue
+			}
+			deletable = append(deletable, resource)
+		}
+	}
+	if !kept {
+		return false, nil
+	}
+
+	var errs []error
+	for _, resource := range deletable {
+		if err := o.deleteResource(ctx, to.String(resource.ID), to.String(resource.Type)); err != nil {
+			if isAuthError(err) {
+				return true, err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return true, utilerrors.NewAggregate(errs)
+}
+
+// deleteResource deletes the resource of the given ID and type, e.g.
+// Microsoft.Network/networkInterfaces.
+func (o *ClusterUninstaller) deleteResource(ctx context.Context, id string, resourceType string) error {
+	logger := o.Logger.WithField("resource", id)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	apiVersion, err := o.apiVersion(ctx, resourceType)
+	if err != nil {
+		return err
+	}
+	delFuture, err := o.resourcesClient.DeleteByID(ctx, id, apiVersion)
+	if err != nil {
+		if wasNotFound(delFuture.Response()) {
+			logger.Debug("already deleted")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete %s", id)
+	}
+	if err := delFuture.WaitForCompletionRef(ctx, o.resourcesClient.Client); err != nil {
+		if wasNotFound(delFuture.Response()) {
+			logger.Debug("already deleted")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete %s", id)
+	}
+	logger.Info("deleted")
+	return nil
+}
+
+// apiVersion returns the API version used to delete the resources of the
+// given type, from the versions of its resource provider.
+func (o *ClusterUninstaller) apiVersion(ctx context.Context, resourceType string) (string, error) {
+	parts := strings.SplitN(resourceType, "/", 2)
+	if len(parts) != 2 {
+		return "", errors.Errorf("invalid resource type %q", resourceType)
+	}
+	provider, err := o.providersClient.Get(ctx, parts[0], "")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get resource provider %s", parts[0])
+	}
+	if provider.ResourceTypes != nil {
+		for _, t := range *provider.ResourceTypes {
+			if strings.EqualFold(to.String(t.ResourceType), parts[1]) && t.APIVersions != nil {
+				if version := stableAPIVersion(*t.APIVersions); version != "" {
+					return version, nil
+				}
+			}
+		}
+	}
+	return "", errors.Errorf("no API version found for resource type %s", resourceType)
+}
+
+// stableAPIVersion returns the first version that is not a preview, or else
+// the first version. The providers list their latest versions first.
+func stableAPIVersion(versions []string) string {
+	for _, version := range versions {
+		if !strings.HasSuffix(version, "-preview") {
+			return version
+		}
+	}
+	if len(versions) > 0 {
+		return versions[0]
+	}
+	return ""
+}
+
+// tagsToMap converts Azure tags to a map of strings.
+func tagsToMap(tags map[string]*string) map[string]string {
+	m := make(map[string]string, len(tags))
+	for key, value := range tags {
+		m[key] = to.String(value)
+	}
+	return m
+}
+
+func deletePublicRecords(ctx context.Context, dnsClient dns.ZonesClient, recordsClient dns.RecordSetsClient, privateDNSClient privatedns.PrivateZonesClient, privateRecordsClient privatedns.RecordSetsClient, logger logrus.FieldLogger, skip skipFunc, rgName string) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
 	defer cancel()
 
@@ -223,7 +389,7 @@ func deletePublicRecords(ctx context.Context, dnsClient dns.ZonesClient, records
 
 		for _, zone := range zonesPage.Values() {
 			if zone.ZoneType == dns.Private {
-				if err := deletePublicRecordsForZone(ctx, dnsClient, recordsClient, logger, rgName, to.String(zone.Name)); err != nil {
+				if err := deletePublicRecordsForZone(ctx, dnsClient, recordsClient, logger, skip, rgName, to.String(zone.Name)); err != nil {
 					errs = append(errs, errors.Wrapf(err, "failed to delete public records for %s", to.String(zone.Name)))
 					if isAuthError(err) {
 						return err
@@ -253,7 +419,7 @@ func deletePublicRecords(ctx context.Context, dnsClient dns.ZonesClient, records
 		}
 
 		for _, zone := range privateZonesPage.Values() {
-			if err := deletePublicRecordsForPrivateZone(ctx, privateRecordsClient, dnsClient, recordsClient, logger, rgName, to.String(zone.Name)); err != nil {
+			if err := deletePublicRecordsForPrivateZone(ctx, privateRecordsClient, dnsClient, recordsClient, logger, skip, rgName, to.String(zone.Name)); err != nil {
 				errs = append(errs, errors.Wrapf(err, "failed to delete public records for %s", to.String(zone.Name)))
 				if isAuthError(err) {
 					return err
@@ -266,7 +432,7 @@ func deletePublicRecords(ctx context.Context, dnsClient dns.ZonesClient, records
 	return utilerrors.NewAggregate(errs)
 }
 
-func deletePublicRecordsForZone(ctx context.Context, dnsClient dns.ZonesClient, recordsClient dns.RecordSetsClient, logger logrus.FieldLogger, zoneGroup, zoneName string) error {
+func deletePublicRecordsForZone(ctx context.Context, dnsClient dns.ZonesClient, recordsClient dns.RecordSetsClient, logger logrus.FieldLogger, skip skipFunc, zoneGroup, zoneName string) error {
 	// collect all the records from the zoneName
 	allPrivateRecords := sets.NewString()
 	for recordPages, err := recordsClient.ListByDNSZone(ctx, zoneGroup, zoneName, to.Int32Ptr(100), ""); recordPages.NotDone(); err = recordPages.NextWithContext(ctx) {
@@ -281,10 +447,10 @@ func deletePublicRecordsForZone(ctx context.Context, dnsClient dns.ZonesClient,
 		}
 	}
 
-	return deletePublicRecordsMatchingZoneName(ctx, dnsClient, recordsClient, logger, allPrivateRecords, zoneName)
+	return deletePublicRecordsMatchingZoneName(ctx, dnsClient, recordsClient, logger, skip, allPrivateRecords, zoneName)
 }
 
-func deletePublicRecordsForPrivateZone(ctx context.Context, privateRecordsClient privatedns.RecordSetsClient, dnsClient dns.ZonesClient, recordsClient dns.RecordSetsClient, logger logrus.FieldLogger, zoneGroup, zoneName string) error {
+func deletePublicRecordsForPrivateZone(ctx context.Context, privateRecordsClient privatedns.RecordSetsClient, dnsClient dns.ZonesClient, recordsClient dns.RecordSetsClient, logger logrus.FieldLogger, skip skipFunc, zoneGroup, zoneName string) error {
 	// collect all the records from the zoneName
 	allPrivateRecords := sets.NewString()
 	for recordPages, err := privateRecordsClient.List(ctx, zoneGroup, zoneName, to.Int32Ptr(100), ""); recordPages.NotDone(); err = recordPages.NextWithContext(ctx) {
@@ -299,10 +465,10 @@ func deletePublicRecordsForPrivateZone(ctx context.Context, privateRecordsClient
 		}
 	}
 
-	return deletePublicRecordsMatchingZoneName(ctx, dnsClient, recordsClient, logger, allPrivateRecords, zoneName)
+	return deletePublicRecordsMatchingZoneName(ctx, dnsClient, recordsClient, logger, skip, allPrivateRecords, zoneName)
 }
 
-func deletePublicRecordsMatchingZoneName(ctx context.Context, dnsClient dns.ZonesClient, recordsClient dns.RecordSetsClient, logger logrus.FieldLogger, privateRecords sets.String, zoneName string) error {
+func deletePublicRecordsMatchingZoneName(ctx context.Context, dnsClient dns.ZonesClient, recordsClient dns.RecordSetsClient, logger logrus.FieldLogger, skip skipFunc, privateRecords sets.String, zoneName string) error {
 	sharedZones, err := getSharedDNSZones(ctx, dnsClient, zoneName)
 	if err != nil {
 		return errors.Wrapf(err, "failed to find shared zone for %s", zoneName)
@@ -315,6 +481,9 @@ func deletePublicRecordsMatchingZoneName(ctx context.Context, dnsClient dns.Zone
 			}
 			for _, record := range recordPages.Values() {
 				if privateRecords.Has(fmt.Sprintf("%s.%s", to.String(record.Name), sharedZone.Name)) {
+					if skip(providers.Resource{Type: to.String(record.Type), Name: to.String(record.ID)}, tagsToMap(record.Metadata)) {
+						continue
+					}
 					resp, err := recordsClient.Delete(ctx, sharedZone.Group, sharedZone.Name, to.String(record.Name), toRecordType(to.String(record.Type)), "")
 					if err != nil {
 						if wasNotFound(resp.Response) {
@@ -425,7 +594,7 @@ func isAuthError(err error) bool {
 	return false
 }
 
-func deleteApplicationRegistrations(ctx context.Context, appClient graphrbac.ApplicationsClient, spClient graphrbac.ServicePrincipalsClient, logger logrus.FieldLogger, infraID string) error {
+func deleteApplicationRegistrations(ctx context.Context, appClient graphrbac.ApplicationsClient, spClient graphrbac.ServicePrincipalsClient, logger logrus.FieldLogger, skip skipFunc, infraID string) error {
 	errorList := []error{}
 
 	tag := fmt.Sprintf("kubernetes.io_cluster.%s=owned", infraID)
@@ -438,6 +607,13 @@ func deleteApplicationRegistrations(ctx context.Context, appClient graphrbac.App
 	}
 
 	for _, sp := range servicePrincipals {
+		var tags []string
+		if sp.Tags != nil {
+			tags = *sp.Tags
+		}
+		if skip(providers.Resource{Type: "applicationRegistration", Name: to.String(sp.AppID)}, providers.TagsFromList(tags)) {
+			continue
+		}
 		logger = logger.WithField("appID", *sp.AppID)
 		appFilter := fmt.Sprintf("appId eq '%s'", *sp.AppID)
 		appResults, err := appClient.List(ctx, appFilter)
@@ -0,0 +1,34 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStableAPIVersion(t *testing.T) {
+	cases := []struct {
+		name     string
+		versions []string
+		expected string
+	}{{
+		name:     "latest stable",
+		versions: []string{"2020-07-01", "2020-06-01", "2019-12-01"},
+		expected: "2020-07-01",
+	}, {
+		name:     "preview first",
+		versions: []string{"2021-01-01-preview", "2020-07-01"},
+		expected: "2020-07-01",
+	}, {
+		name:     "preview only",
+		versions: []string{"2021-01-01-preview"},
+		expected: "2021-01-01-preview",
+	}, {
+		name: "none",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, stableAPIVersion(tc.versions))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+// Records in shared public DNS zones are matched against the cluster's private
+// zones at deletion time and are not listed individually.
+func (o *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	o.configureClients()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	var result []providers.Resource
+
+	group, err := o.resourceGroupsClient.Get(ctx, o.ResourceGroupName)
+	switch {
+	case wasNotFound(group.Response.Response):
+		o.Logger.WithField("resource group", o.ResourceGroupName).Debug("already deleted")
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to get resource group %s", o.ResourceGroupName)
+	default:
+		result = append(result, providers.Resource{
+			Type:     "resourceGroup",
+			Name:     o.ResourceGroupName,
+			Location: to.String(group.Location),
+		})
+		for page, err := o.resourcesClient.ListByResourceGroup(ctx, o.ResourceGroupName, "", "", to.Int32Ptr(100)); page.NotDone(); err = page.NextWithContext(ctx) {
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list resources in %s", o.ResourceGroupName)
+			}
+			for _, resource := range page.Values() {
+				result = append(result, providers.Resource{
+					Type:     to.String(resource.Type),
+					Name:     to.String(resource.ID),
+					Location: to.String(resource.Location),
+				})
+			}
+		}
+	}
+
+	tag := fmt.Sprintf("kubernetes.io_cluster.%s=owned", o.InfraID)
+	servicePrincipals, err := getServicePrincipalsByTag(ctx, o.serviceprincipalsClient, tag, o.InfraID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to gather list of Service Principals by tag")
+	}
+	for _, sp := range servicePrincipals {
+		result = append(result, providers.Resource{
+			Type: "applicationRegistration",
+			Name: to.String(sp.AppID),
+		})
+	}
+
+	providers.SortResources(result)
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package azure
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	azuresession "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/azure"
+)
+
+const clusterTagPrefix = "kubernetes.io_cluster."
+
+// FindOrphans lists the clusters whose resource group is tagged
+// kubernetes.io_cluster.<infraID>: owned. Only groups in the scope's region
+// are listed when one is given. The resources in each group are counted and
+// the oldest creation time is used as the cluster age.
+func FindOrphans(logger logrus.FieldLogger, scope *providers.Scope) ([]providers.Orphan, error) {
+	cloudName := azure.CloudEnvironment(scope.Cloud)
+	if cloudName == "" {
+		cloudName = azure.PublicCloud
+	}
+	session, err := azuresession.GetSession(cloudName)
+	if err != nil {
+		return nil, err
+	}
+	o := &ClusterUninstaller{
+		SubscriptionID:  session.Credentials.SubscriptionID,
+		TenantID:        session.Credentials.TenantID,
+		GraphAuthorizer: session.GraphAuthorizer,
+		Authorizer:      session.Authorizer,
+		Environment:     session.Environment,
+		Logger:          logger,
+	}
+	o.configureClients()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	orphans := providers.OrphanSet{}
+	groups := map[string]string{}
+	locations := map[string]string{}
+	for page, err := o.resourceGroupsClient.List(ctx, "", to.Int32Ptr(100)); page.NotDone(); err = page.NextWithContext(ctx) {
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list resource groups")
+		}
+		for _, group := range page.Values() {
+			location := to.String(group.Location)
+			if scope.Region != "" && !strings.EqualFold(location, scope.Region) {
+				continue
+			}
+			for key, value := range group.Tags {
+				if to.String(value) == "owned" && strings.HasPrefix(key, clusterTagPrefix) {
+					infraID := strings.TrimPrefix(key, clusterTagPrefix)
+					groups[infraID] = to.String(group.Name)
+					locations[infraID] = location
+					break
+				}
+			}
+		}
+	}
+
+	for infraID, group := range groups {
+		orphans.Add(infraID, time.Time{})
+		for page, err := o.resourcesClient.ListByResourceGroup(ctx, group, "", "createdTime", to.Int32Ptr(100)); page.NotDone(); err = page.NextWithContext(ctx) {
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list resources in %s", group)
+			}
+			for _, resource := range page.Values() {
+				var created time.Time
+				if resource.CreatedTime != nil {
+					created = resource.CreatedTime.Time
+				}
+				orphans.Add(infraID, created)
+			}
+		}
+	}
+
+	result := orphans.List()
+	for i := range result {
+		infraID := result[i].InfraID
+		result[i].Metadata = &types.ClusterMetadata{
+			InfraID: infraID,
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				Azure: &azure.Metadata{
+					CloudName:         cloudName,
+					Region:            locations[infraID],
+					ResourceGroupName: groups[infraID],
+				},
+			},
+		}
+	}
+	return result, nil
+}
@@ -3,6 +3,8 @@
 package baremetal
 
 import (
+	"context"
+
 	"github.com/libvirt/libvirt-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -20,7 +22,16 @@ type ClusterUninstaller struct {
 }
 
 // Run is the entrypoint to start the uninstall process.
-func (o *ClusterUninstaller) Run() error {
+func (o *ClusterUninstaller) Run(_ context.Context, options providers.RunOptions) (*providers.Report, error) {
+	return providers.RunWithInventory(o.Logger, o.Inventory, func(recorder *providers.Recorder) error {
+		return o.destroy(func(resource providers.Resource) bool {
+			return options.Exclusions.Skip(o.Logger, recorder, resource, nil)
+		})
+	})
+}
+
+// destroy removes the cluster resources that skip does not select.
+func (o *ClusterUninstaller) destroy(skip func(providers.Resource) bool) error {
 	o.Logger.Debug("Deleting bare metal resources")
 
 	// FIXME: close the connection
@@ -28,7 +39,7 @@ func (o *ClusterUninstaller) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to connect to Libvirt daemon")
 	}
-	err = o.deleteStoragePool(conn)
+	err = o.deleteStoragePool(conn, skip)
 	if err != nil {
 		return errors.Wrap(err, "failed to clean baremetal bootstrap storage pool")
 	}
@@ -50,10 +61,13 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 
 // deleteStoragePool destroys, deletes and undefines any storagePool left behind during the creation
 // of the bootstrap VM
-func (o *ClusterUninstaller) deleteStoragePool(conn *libvirt.Connect) error {
+func (o *ClusterUninstaller) deleteStoragePool(conn *libvirt.Connect, skip func(providers.Resource) bool) error {
 	o.Logger.Debug("Deleting baremetal bootstrap volumes")
 
 	pname := o.InfraID + "-bootstrap"
+	if skip(providers.Resource{Type: "pool", Name: pname}) {
+		return nil
+	}
 	pool, err := conn.LookupStoragePoolByName(pname)
 	if err != nil {
 		return errors.Wrapf(err, "get storage pool %q", pname)
@@ -66,17 +80,26 @@ func (o *ClusterUninstaller) deleteStoragePool(conn *libvirt.Connect) error {
 		return errors.Wrapf(err, "list volumes in %q", pname)
 	}
 
+	kept := false
 	for _, vol := range vols {
 		defer vol.Free()
 		vName, err := vol.GetName()
 		if err != nil {
 			return errors.Wrapf(err, "get volume names in %q", pname)
 		}
+		if skip(providers.Resource{Type: "volume", Name: vName, Location: pname}) {
+			kept = true
+			continue
+		}
 		if err := vol.Delete(0); err != nil {
 			return errors.Wrapf(err, "delete volume %q from %q", vName, pname)
 		}
 		o.Logger.WithField("volume", vName).Info("Deleted volume")
 	}
+	if kept {
+		o.Logger.WithField("pool", pname).Info("Keeping pool with excluded volumes")
+		return nil
+	}
 
 	if err := pool.Destroy(); err != nil {
 		return errors.Wrapf(err, "destroy pool %q", pname)
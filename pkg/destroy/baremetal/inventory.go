@@ -0,0 +1,46 @@
+// +build baremetal
+
+package baremetal
+
+import (
+	"github.com/libvirt/libvirt-go"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+func (o *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	conn, err := libvirt.NewConnect(o.LibvirtURI)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to Libvirt daemon")
+	}
+	defer conn.Close()
+
+	pname := o.InfraID + "-bootstrap"
+	pool, err := conn.LookupStoragePoolByName(pname)
+	if err != nil {
+		if lerr, ok := err.(libvirt.Error); ok && lerr.Code == libvirt.ERR_NO_STORAGE_POOL {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "get storage pool %q", pname)
+	}
+	defer pool.Free()
+
+	vols, err := pool.ListAllStorageVolumes(0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "list volumes in %q", pname)
+	}
+
+	resources := []providers.Resource{{Type: "pool", Name: pname}}
+	for _, vol := range vols {
+		defer vol.Free()
+		vName, err := vol.GetName()
+		if err != nil {
+			return nil, errors.Wrapf(err, "get volume names in %q", pname)
+		}
+		resources = append(resources, providers.Resource{Type: "volume", Name: vName, Location: pname})
+	}
+	providers.SortResources(resources)
+	return resources, nil
+}
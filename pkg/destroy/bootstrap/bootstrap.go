@@ -2,6 +2,7 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -18,8 +19,9 @@ import (
 	"github.com/pkg/errors"
 )
 
-// Destroy uses Terraform to remove bootstrap resources.
-func Destroy(dir string) (err error) {
+// Destroy uses Terraform to remove bootstrap resources.  Cancelling ctx
+// stops Terraform gracefully.
+func Destroy(ctx context.Context, dir string) (err error) {
 	metadata, err := cluster.LoadMetadata(dir)
 	if err != nil {
 		return err
@@ -54,23 +56,27 @@ func Destroy(dir string) (err error) {
 		}
 	}
 
+	if err := copyOverrides(dir, tempDir); err != nil {
+		return errors.Wrap(err, "failed to copy user-supplied Terraform files to the temporary directory")
+	}
+
 	switch platform {
 	case gcp.Name:
 		// First remove the bootstrap node from the load balancers to avoid race condition.
-		_, err = terraform.Apply(tempDir, platform, append(extraArgs, "-var=gcp_bootstrap_lb=false")...)
+		_, err = terraform.Apply(ctx, tempDir, platform, append(extraArgs, "-var=gcp_bootstrap_lb=false")...)
 		if err != nil {
 			return errors.Wrap(err, "failed disabling bootstrap load balancing")
 		}
 
 		// Then destory the bootstrap instance and instance group so destroy runs cleanly.
 		// First remove the bootstrap from LB target and its instance so that bootstrap module is cleanly destroyed.
-		_, err = terraform.Apply(tempDir, platform, append(extraArgs, "-var=gcp_bootstrap_enabled=false")...)
+		_, err = terraform.Apply(ctx, tempDir, platform, append(extraArgs, "-var=gcp_bootstrap_enabled=false")...)
 		if err != nil {
 			return errors.Wrap(err, "failed disabling bootstrap")
 		}
 	case libvirt.Name:
 		// First remove the bootstrap node from DNS
-		_, err = terraform.Apply(tempDir, platform, append(extraArgs, "-var=bootstrap_dns=false")...)
+		_, err = terraform.Apply(ctx, tempDir, platform, append(extraArgs, "-var=bootstrap_dns=false")...)
 		if err != nil {
 			return errors.Wrap(err, "Terraform apply")
 		}
@@ -86,17 +92,22 @@ func Destroy(dir string) (err error) {
 	}
 
 	extraArgs = append(extraArgs, "-target=module.bootstrap")
-	err = terraform.Destroy(tempDir, platform, extraArgs...)
-	if err != nil {
-		return errors.Wrap(err, "Terraform destroy")
+	destroyErr := terraform.Destroy(ctx, tempDir, platform, extraArgs...)
+	if destroyErr != nil && !terraform.IsCancelled(destroyErr) {
+		return errors.Wrap(destroyErr, "Terraform destroy")
 	}
 
+	// A cancelled destroy still saves the partial state, so that a later run
+	// does not try to remove resources that are already gone.
 	tempStateFilePath := filepath.Join(dir, terraform.StateFileName+".new")
 	err = copy(filepath.Join(tempDir, terraform.StateFileName), tempStateFilePath)
 	if err != nil {
 		return errors.Wrapf(err, "failed to copy %s from the temporary directory", terraform.StateFileName)
 	}
-	return os.Rename(tempStateFilePath, filepath.Join(dir, terraform.StateFileName))
+	if err := os.Rename(tempStateFilePath, filepath.Join(dir, terraform.StateFileName)); err != nil {
+		return err
+	}
+	return errors.Wrap(destroyErr, "Terraform destroy")
 }
 
 func copy(from string, to string) error {
@@ -107,3 +118,25 @@ func copy(from string, to string) error {
 
 	return ioutil.WriteFile(to, data, 0666)
 }
+
+// copyOverrides copies the user-supplied Terraform files, if any, so
+// that Terraform sees the same configuration that created the cluster.
+func copyOverrides(from string, to string) error {
+	src := filepath.Join(from, terraform.OverridesDirName)
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(to, rel), 0777)
+		}
+		return copy(path, filepath.Join(to, rel))
+	})
+}
@@ -15,7 +15,7 @@ var (
 )
 
 func (o *ClusterUninstaller) listBuckets() ([]cloudResource, error) {
-	return o.listBucketsWithFilter("items(name),nextPageToken", o.ClusterID+"-", nil)
+	return o.listBucketsWithFilter("items(name,labels),nextPageToken", o.ClusterID+"-", nil)
 }
 
 // listBucketsWithFilter lists buckets in the project that satisfy the filter criteria.
@@ -40,6 +40,7 @@ func (o *ClusterUninstaller) listBucketsWithFilter(fields string, prefix string,
 					key:      item.Name,
 					name:     item.Name,
 					typeName: "bucket",
+					labels:   item.Labels,
 				})
 			}
 		}
@@ -1,5 +1,9 @@
 package gcp
 
+import (
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
 // cloudResource hold various fields for any given cloud resource
 type cloudResource struct {
 	key      string
@@ -8,6 +12,18 @@ type cloudResource struct {
 	typeName string
 	url      string
 	zone     string
+
+	// labels holds the labels of the resource, when they were listed.
+	labels map[string]string
+}
+
+// resource describes the cloud resource for inventories and reports.
+func (r cloudResource) resource() providers.Resource {
+	return providers.Resource{
+		Type:     r.typeName,
+		Name:     r.name,
+		Location: r.zone,
+	}
 }
 
 type cloudResources map[string]cloudResource
@@ -10,7 +10,7 @@ import (
 )
 
 func (o *ClusterUninstaller) listDisks() ([]cloudResource, error) {
-	return o.listDisksWithFilter("items/*/disks(name,zone),nextPageToken", o.clusterIDFilter(), nil)
+	return o.listDisksWithFilter("items/*/disks(name,zone,labels),nextPageToken", o.clusterIDFilter(), nil)
 }
 
 // listDisksWithFilter lists disks in the project that satisfy the filter criteria.
@@ -37,6 +37,7 @@ func (o *ClusterUninstaller) listDisksWithFilter(fields string, filter string, f
 						name:     item.Name,
 						typeName: "disk",
 						zone:     zone,
+						labels:   item.Labels,
 					})
 				}
 			}
@@ -9,6 +9,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	dns "google.golang.org/api/dns/v1"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
 )
 
 type dnsZone struct {
@@ -155,6 +157,9 @@ func (o *ClusterUninstaller) destroyDNS() error {
 		o.Logger.Debugf("Private DNS zone not found")
 		return nil
 	}
+	if o.Exclusions.Skip(o.Logger, o.recorder, providers.Resource{Type: "dnszone", Name: privateZone.name}, nil) {
+		return nil
+	}
 
 	zoneRecordSets, err := o.listDNSZoneRecordSets(privateZone.name)
 	if err != nil {
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	resourcemanager "google.golang.org/api/cloudresourcemanager/v1"
@@ -32,6 +34,10 @@ var (
 	defaultTimeout = 2 * time.Minute
 )
 
+// defaultConcurrency is the number of resource types deleted in parallel
+// within a stage when the caller does not choose one.
+const defaultConcurrency = 4
+
 // ClusterUninstaller holds the various options for the cluster we want to delete
 type ClusterUninstaller struct {
 	Logger    logrus.FieldLogger
@@ -40,6 +46,13 @@ type ClusterUninstaller struct {
 	ClusterID string
 	Context   context.Context
 
+	// Concurrency is the number of resource types deleted in parallel
+	// within a stage.
+	Concurrency int
+
+	// Exclusions selects the resources that are left in place.
+	Exclusions providers.Exclusions
+
 	computeSvc *compute.Service
 	iamSvc     *iam.Service
 	dnsSvc     *dns.Service
@@ -51,6 +64,9 @@ type ClusterUninstaller struct {
 	// from metadata or by inferring it from existing cluster resources.
 	cloudControllerUID string
 
+	// recorder collects the excluded resources of a run.
+	recorder *providers.Recorder
+
 	errorTracker
 	requestIDTracker
 	pendingItemTracker
@@ -70,11 +86,64 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 	}, nil
 }
 
-// Run is the entrypoint to start the uninstall process
-func (o *ClusterUninstaller) Run() error {
-	ctx, cancel := o.contextWithTimeout()
+// Run is the entrypoint to start the uninstall process. It retries until
+// every resource is gone or ctx is done.
+func (o *ClusterUninstaller) Run(ctx context.Context, options providers.RunOptions) (*providers.Report, error) {
+	o.Context = ctx
+	o.Concurrency = options.ConcurrencyOrDefault(defaultConcurrency)
+	o.Exclusions = options.Exclusions
+	o.recorder = &providers.Recorder{}
+	o.pendingItemTracker.skip = func(item cloudResource) bool {
+		return o.Exclusions.Skip(o.Logger, o.recorder, item.resource(), item.labels)
+	}
+
+	serviceCtx, cancel := o.contextWithTimeout()
 	defer cancel()
 
+	if err := o.createServices(serviceCtx); err != nil {
+		return nil, err
+	}
+
+	err := wait.PollImmediateUntil(
+		time.Second*10,
+		o.destroyCluster,
+		ctx.Done(),
+	)
+	if err != nil {
+		err = errors.Wrap(ctx.Err(), "destroying cluster")
+		return o.report(err), err
+	}
+	return o.report(nil), nil
+}
+
+// report builds the report of a run from the deleted items and the items
+// still pending, with the last error seen for each of them.
+func (o *ClusterUninstaller) report(err error) *providers.Report {
+	recorder := o.recorder
+	if recorder == nil {
+		recorder = &providers.Recorder{}
+	}
+	for _, items := range o.deletedItems {
+		for _, item := range items {
+			recorder.Deleted(item.resource())
+		}
+	}
+	var leftovers []providers.Resource
+	for itemType, items := range o.pendingItems {
+		if pseudoItemTypes.Has(itemType) {
+			continue
+		}
+		for _, item := range items {
+			resource := item.resource()
+			recorder.Error(resource, o.lastError(item.key))
+			leftovers = append(leftovers, resource)
+		}
+	}
+	return recorder.Report(leftovers, err)
+}
+
+// createServices creates the API clients used to find and remove resources.
+func (o *ClusterUninstaller) createServices(ctx context.Context) error {
 	ssn, err := gcpconfig.GetSession(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to get session")
@@ -109,13 +178,7 @@ func (o *ClusterUninstaller) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to create resourcemanager service")
 	}
-
-	err = wait.PollImmediateInfinite(
-		time.Second*10,
-		o.destroyCluster,
-	)
 	return nil
-
 }
 
 func (o *ClusterUninstaller) destroyCluster() (bool, error) {
@@ -147,17 +210,38 @@ func (o *ClusterUninstaller) destroyCluster() (bool, error) {
 		{name: "Subnetworks", execute: o.destroySubnetworks},
 		{name: "Networks", execute: o.destroyNetworks},
 	}}
+	concurrency := o.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
 	done := true
 	for _, stage := range stagedFuncs {
-		if done {
-			for _, f := range stage {
-				err := f.execute()
-				if err != nil {
+		if !done || o.Context.Err() != nil {
+			break
+		}
+		var (
+			wg   sync.WaitGroup
+			lock sync.Mutex
+		)
+		sem := make(chan struct{}, concurrency)
+		for _, f := range stage {
+			f := f
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				if err := f.execute(); err != nil {
 					o.Logger.Debugf("%s: %v", f.name, err)
+					lock.Lock()
 					done = false
+					lock.Unlock()
 				}
-			}
+			}()
 		}
+		wg.Wait()
 	}
 	return done, nil
 }
@@ -211,8 +295,9 @@ func (o *ClusterUninstaller) contextWithTimeout() (context.Context, context.Canc
 }
 
 // requestIDTracker keeps track of a set of request IDs mapped to a unique resource
-// identifier
+// identifier. It is safe for concurrent use.
 type requestIDTracker struct {
+	lock       sync.Mutex
 	requestIDs map[string]string
 }
 
@@ -224,7 +309,9 @@ func newRequestIDTracker() requestIDTracker {
 
 // requestID returns a UID for a given item identifier. Unless the ID is reset, the
 // same requestID will be returned every time for a given item.
-func (t requestIDTracker) requestID(identifier ...string) string {
+func (t *requestIDTracker) requestID(identifier ...string) string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	key := strings.Join(identifier, "/")
 	id, exists := t.requestIDs[key]
 	if !exists {
@@ -237,24 +324,38 @@ func (t requestIDTracker) requestID(identifier ...string) string {
 // resetRequestID resets the request ID used for a particular item. This
 // should be called whenever a request fails, and a brand new request should be
 // sent.
-func (t requestIDTracker) resetRequestID(identifier ...string) {
+func (t *requestIDTracker) resetRequestID(identifier ...string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	key := strings.Join(identifier, "/")
 	delete(t.requestIDs, key)
 }
 
-// pendingItemTracker tracks a set of pending item names for a given type of resource
+// pseudoItemTypes are the pending item types that track a step other than
+// the deletion of a resource.
+var pseudoItemTypes = sets.NewString("stopinstance", "iampolicy")
+
+// pendingItemTracker tracks a set of pending item names for a given type of resource.
+// It is safe for concurrent use.
 type pendingItemTracker struct {
+	lock sync.Mutex
+	// skip, when set, selects the items that must never become pending.
+	skip         func(cloudResource) bool
 	pendingItems map[string]cloudResources
+	deletedItems map[string]cloudResources
 }
 
 func newPendingItemTracker() pendingItemTracker {
 	return pendingItemTracker{
 		pendingItems: map[string]cloudResources{},
+		deletedItems: map[string]cloudResources{},
 	}
 }
 
 // GetAllPendintItems returns a slice of all of the pending items across all types.
-func (t pendingItemTracker) GetAllPendingItems() []cloudResource {
+func (t *pendingItemTracker) GetAllPendingItems() []cloudResource {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	var items []cloudResource
 	for _, is := range t.pendingItems {
 		for _, i := range is {
@@ -265,7 +366,9 @@ func (t pendingItemTracker) GetAllPendingItems() []cloudResource {
 }
 
 // getPendingItems returns the list of resources to be deleted.
-func (t pendingItemTracker) getPendingItems(itemType string) []cloudResource {
+func (t *pendingItemTracker) getPendingItems(itemType string) []cloudResource {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	lastFound, exists := t.pendingItems[itemType]
 	if !exists {
 		lastFound = cloudResources{}
@@ -273,25 +376,41 @@ func (t pendingItemTracker) getPendingItems(itemType string) []cloudResource {
 	return lastFound.list()
 }
 
-// insertPendingItems adds to the list of resources to be deleted.
-func (t pendingItemTracker) insertPendingItems(itemType string, items []cloudResource) []cloudResource {
+// insertPendingItems adds to the list of resources to be deleted, leaving out
+// the items selected by skip.
+func (t *pendingItemTracker) insertPendingItems(itemType string, items []cloudResource) []cloudResource {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	lastFound, exists := t.pendingItems[itemType]
 	if !exists {
 		lastFound = cloudResources{}
 	}
-	lastFound = lastFound.insert(items...)
+	for _, item := range items {
+		if t.skip == nil || !t.skip(item) {
+			lastFound = lastFound.insert(item)
+		}
+	}
 	t.pendingItems[itemType] = lastFound
 	return lastFound.list()
 }
 
 // deletePendingItems removes from the list of resources to be deleted.
-func (t pendingItemTracker) deletePendingItems(itemType string, items []cloudResource) []cloudResource {
+func (t *pendingItemTracker) deletePendingItems(itemType string, items []cloudResource) []cloudResource {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	lastFound, exists := t.pendingItems[itemType]
 	if !exists {
 		lastFound = cloudResources{}
 	}
 	lastFound = lastFound.delete(items...)
 	t.pendingItems[itemType] = lastFound
+	if !pseudoItemTypes.Has(itemType) {
+		deleted, exists := t.deletedItems[itemType]
+		if !exists {
+			deleted = cloudResources{}
+		}
+		t.deletedItems[itemType] = deleted.insert(items...)
+	}
 	return lastFound.list()
 }
 
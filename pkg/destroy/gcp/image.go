@@ -8,7 +8,7 @@ import (
 )
 
 func (o *ClusterUninstaller) listImages() ([]cloudResource, error) {
-	return o.listImagesWithFilter("items(name),nextPageToken", o.clusterIDFilter(), nil)
+	return o.listImagesWithFilter("items(name,labels),nextPageToken", o.clusterIDFilter(), nil)
 }
 
 // listImagesWithFilter lists addresses in the project that satisfy the filter criteria.
@@ -32,6 +32,7 @@ func (o *ClusterUninstaller) listImagesWithFilter(fields string, filter string,
 					key:      item.Name,
 					name:     item.Name,
 					typeName: "image",
+					labels:   item.Labels,
 				})
 			}
 		}
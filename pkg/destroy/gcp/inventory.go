@@ -0,0 +1,76 @@
+package gcp
+
+import (
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+// It uses the same filters as the destroy stages, including the discovery of
+// load balancer resources created by the cloud controller.
+func (o *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	ctx, cancel := o.contextWithTimeout()
+	defer cancel()
+
+	if err := o.createServices(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := o.discoverCloudControllerResources(); err != nil {
+		return nil, err
+	}
+
+	listFuncs := []struct {
+		typeName string
+		list     func() ([]cloudResource, error)
+	}{
+		{typeName: "instance", list: o.listInstances},
+		{typeName: "disk", list: o.listDisks},
+		{typeName: "serviceaccount", list: o.listServiceAccounts},
+		{typeName: "image", list: o.listImages},
+		{typeName: "bucket", list: o.listBuckets},
+		{typeName: "route", list: o.listRoutes},
+		{typeName: "firewall", list: o.listFirewalls},
+		{typeName: "address", list: o.listAddresses},
+		{typeName: "targetpool", list: o.listTargetPools},
+		{typeName: "instancegroup", list: o.listInstanceGroups},
+		{typeName: "forwardingrule", list: o.listForwardingRules},
+		{typeName: "backendservice", list: o.listBackendServices},
+		{typeName: "healthcheck", list: o.listHealthChecks},
+		{typeName: "httphealthcheck", list: o.listHTTPHealthChecks},
+		{typeName: "router", list: o.listRouters},
+		{typeName: "subnetwork", list: o.listSubnetworks},
+		{typeName: "network", list: o.listNetworks},
+	}
+	for _, f := range listFuncs {
+		found, err := f.list()
+		if err != nil {
+			return nil, err
+		}
+		o.insertPendingItems(f.typeName, found)
+	}
+
+	// Routes attached to cluster networks are removed with the network.
+	for _, network := range o.getPendingItems("network") {
+		found, err := o.listNetworkRoutes(network.url)
+		if err != nil {
+			return nil, err
+		}
+		o.insertPendingItems("route", found)
+	}
+
+	privateZone, _, err := o.listDNSZones()
+	if err != nil {
+		return nil, err
+	}
+	if privateZone != nil {
+		o.insertPendingItems("dnszone", []cloudResource{{key: privateZone.name, name: privateZone.name, typeName: "dnszone"}})
+	}
+
+	items := o.GetAllPendingItems()
+	resources := make([]providers.Resource, 0, len(items))
+	for _, item := range items {
+		resources = append(resources, item.resource())
+	}
+	providers.SortResources(resources)
+	return resources, nil
+}
@@ -0,0 +1,155 @@
+package gcp
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+)
+
+const clusterLabelPrefix = "kubernetes-io-cluster-"
+
+// infraIDName matches the names the installer gives to subnetworks and
+// instances, capturing the infra ID.
+var infraIDName = regexp.MustCompile(`^(.+-[a-z0-9]{5})-(master-subnet|worker-subnet|bootstrap|master-[0-9]+|worker-.+)$`)
+
+// FindOrphans lists the clusters with subnetworks or instances in the
+// scope's region. Clusters are recognized by the kubernetes-io-cluster-<infraID>
+// label or by the names the installer uses, and their networks, disks and
+// firewalls are counted by infra ID name prefix.
+func FindOrphans(logger logrus.FieldLogger, scope *providers.Scope) ([]providers.Orphan, error) {
+	if scope.Region == "" || scope.ProjectID == "" {
+		return nil, errors.New("a region and a project are required to search for GCP clusters")
+	}
+	o := &ClusterUninstaller{
+		Logger:    logger,
+		Region:    scope.Region,
+		ProjectID: scope.ProjectID,
+		Context:   context.Background(),
+	}
+	ctx, cancel := context.WithTimeout(o.Context, 10*time.Minute)
+	defer cancel()
+	if err := o.createServices(ctx); err != nil {
+		return nil, err
+	}
+
+	orphans := providers.OrphanSet{}
+	inRegion := func(zoneURL string) bool {
+		return strings.HasPrefix(o.getZoneName(zoneURL), scope.Region+"-")
+	}
+
+	err := o.computeSvc.Subnetworks.List(o.ProjectID, o.Region).Fields(googleapi.Field("items(name,creationTimestamp),nextPageToken")).Pages(ctx, func(list *compute.SubnetworkList) error {
+		for _, item := range list.Items {
+			if match := infraIDName.FindStringSubmatch(item.Name); match != nil {
+				orphans.Add(match[1], parseCreationTimestamp(item.CreationTimestamp))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list subnetworks")
+	}
+
+	err = o.computeSvc.Instances.AggregatedList(o.ProjectID).Fields(googleapi.Field("items/*/instances(name,zone,labels,creationTimestamp),nextPageToken")).Pages(ctx, func(list *compute.InstanceAggregatedList) error {
+		for _, scopedList := range list.Items {
+			for _, item := range scopedList.Instances {
+				if !inRegion(item.Zone) {
+					continue
+				}
+				if infraID := instanceInfraID(item); infraID != "" {
+					orphans.Add(infraID, parseCreationTimestamp(item.CreationTimestamp))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list compute instances")
+	}
+
+	// Networks, disks and firewalls only add to the clusters found above.
+	byPrefix := func(name, created string) {
+		for infraID := range orphans {
+			if strings.HasPrefix(name, infraID+"-") {
+				orphans.Add(infraID, parseCreationTimestamp(created))
+				return
+			}
+		}
+	}
+	err = o.computeSvc.Networks.List(o.ProjectID).Fields(googleapi.Field("items(name,creationTimestamp),nextPageToken")).Pages(ctx, func(list *compute.NetworkList) error {
+		for _, item := range list.Items {
+			byPrefix(item.Name, item.CreationTimestamp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list networks")
+	}
+	err = o.computeSvc.Disks.AggregatedList(o.ProjectID).Fields(googleapi.Field("items/*/disks(name,zone,creationTimestamp),nextPageToken")).Pages(ctx, func(list *compute.DiskAggregatedList) error {
+		for _, scopedList := range list.Items {
+			for _, item := range scopedList.Disks {
+				if inRegion(item.Zone) {
+					byPrefix(item.Name, item.CreationTimestamp)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list disks")
+	}
+	err = o.computeSvc.Firewalls.List(o.ProjectID).Fields(googleapi.Field("items(name,creationTimestamp),nextPageToken")).Pages(ctx, func(list *compute.FirewallList) error {
+		for _, item := range list.Items {
+			byPrefix(item.Name, item.CreationTimestamp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list firewalls")
+	}
+
+	result := orphans.List()
+	for i := range result {
+		result[i].Metadata = &types.ClusterMetadata{
+			InfraID: result[i].InfraID,
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				GCP: &gcptypes.Metadata{
+					Region:    scope.Region,
+					ProjectID: scope.ProjectID,
+				},
+			},
+		}
+	}
+	return result, nil
+}
+
+// instanceInfraID returns the infra ID from the instance's cluster label or,
+// failing that, from its name.
+func instanceInfraID(instance *compute.Instance) string {
+	for key, value := range instance.Labels {
+		if value == "owned" && strings.HasPrefix(key, clusterLabelPrefix) {
+			return strings.TrimPrefix(key, clusterLabelPrefix)
+		}
+	}
+	if match := infraIDName.FindStringSubmatch(instance.Name); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+func parseCreationTimestamp(timestamp string) time.Time {
+	created, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return created
+}
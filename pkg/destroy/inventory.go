@@ -0,0 +1,58 @@
+package destroy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+const (
+	// InventoryFormatText prints one resource per line in aligned columns.
+	InventoryFormatText = "text"
+
+	// InventoryFormatJSON prints the resources as a JSON array.
+	InventoryFormatJSON = "json"
+)
+
+// FilterInventory returns the resources that no exclusion selects by name,
+// ID or ARN. Tags are not listed in an inventory, so tag exclusions are only
+// applied when the Destroyer runs.
+func FilterInventory(resources []providers.Resource, exclusions providers.Exclusions) []providers.Resource {
+	filtered := make([]providers.Resource, 0, len(resources))
+	for _, resource := range resources {
+		if !exclusions.Excludes(resource, nil) {
+			filtered = append(filtered, resource)
+		}
+	}
+	return filtered
+}
+
+// WriteInventory writes the resources found by a Destroyer to w in the given format.
+func WriteInventory(w io.Writer, resources []providers.Resource, format string) error {
+	switch format {
+	case InventoryFormatJSON:
+		if resources == nil {
+			resources = []providers.Resource{}
+		}
+		data, err := json.MarshalIndent(resources, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal resources")
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case InventoryFormatText:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "TYPE\tNAME\tLOCATION")
+		for _, resource := range resources {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", resource.Type, resource.Name, resource.Location)
+		}
+		return tw.Flush()
+	default:
+		return errors.Errorf("unsupported output format %q", format)
+	}
+}
@@ -0,0 +1,78 @@
+package destroy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+func TestWriteInventory(t *testing.T) {
+	resources := []providers.Resource{
+		{Type: "ec2:instance", Name: "arn:aws:ec2:us-east-1:123:instance/i-1", Location: "us-east-1"},
+		{Type: "iam:role", Name: "arn:aws:iam::123:role/master"},
+	}
+	cases := []struct {
+		name      string
+		resources []providers.Resource
+		format    string
+		expected  string
+		err       string
+	}{{
+		name:      "text",
+		resources: resources,
+		format:    InventoryFormatText,
+		expected: `TYPE          NAME                                    LOCATION
+ec2:instance  arn:aws:ec2:us-east-1:123:instance/i-1  us-east-1
+iam:role      arn:aws:iam::123:role/master            
+`,
+	}, {
+		name:      "json",
+		resources: resources,
+		format:    InventoryFormatJSON,
+		expected: `[
+  {
+    "type": "ec2:instance",
+    "name": "arn:aws:ec2:us-east-1:123:instance/i-1",
+    "location": "us-east-1"
+  },
+  {
+    "type": "iam:role",
+    "name": "arn:aws:iam::123:role/master"
+  }
+]
+`,
+	}, {
+		name:     "empty json",
+		format:   InventoryFormatJSON,
+		expected: "[]\n",
+	}, {
+		name:   "unknown format",
+		format: "yaml",
+		err:    `unsupported output format "yaml"`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteInventory(&buf, tc.resources, tc.format)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestFilterInventory(t *testing.T) {
+	instance := providers.Resource{Type: "ec2:instance", Name: "arn:aws:ec2:us-east-1:123:instance/i-1", Location: "us-east-1"}
+	role := providers.Resource{Type: "iam:role", Name: "arn:aws:iam::123:role/master"}
+	exclusions, err := providers.ParseExclusions([]string{"i-1", "tag:keep"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []providers.Resource{role}, FilterInventory([]providers.Resource{instance, role}, exclusions))
+}
@@ -16,8 +16,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// skipFunc reports whether a resource with the given labels must be left in
+// place.
+type skipFunc func(resource providers.Resource, labels map[string]string) bool
+
 // deleteFunc is the interface a function needs to implement to be delete resources.
-type deleteFunc func(ctx context.Context, namespace string, listOpts metav1.ListOptions, kubevirtClient ickubevirt.Client) error
+type deleteFunc func(ctx context.Context, namespace string, listOpts metav1.ListOptions, kubevirtClient ickubevirt.Client, skip skipFunc) error
 
 // ClusterUninstaller holds the Metadata info needed to delete the tenantCluster resources from the infraCluster.
 type ClusterUninstaller struct {
@@ -34,8 +38,17 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 }
 
 // Run is the entrypoint to start the uninstall process.
-func (uninstaller *ClusterUninstaller) Run() error {
-	ctx := context.Background()
+func (uninstaller *ClusterUninstaller) Run(ctx context.Context, options providers.RunOptions) (*providers.Report, error) {
+	return providers.RunWithInventory(uninstaller.Logger, uninstaller.Inventory, func(recorder *providers.Recorder) error {
+		return uninstaller.destroy(ctx, func(resource providers.Resource, labels map[string]string) bool {
+			return options.Exclusions.Skip(uninstaller.Logger, recorder, resource, labels)
+		})
+	})
+}
+
+// destroy removes the cluster resources that skip does not select, retrying
+// until they are gone or ctx is done.
+func (uninstaller *ClusterUninstaller) destroy(ctx context.Context, skip skipFunc) error {
 	namespace := uninstaller.Metadata.Kubevirt.Namespace
 
 	listOpts := metav1.ListOptions{LabelSelector: apilabels.FormatLabels(uninstaller.Metadata.Kubevirt.Labels)}
@@ -49,12 +62,17 @@ func (uninstaller *ClusterUninstaller) Run() error {
 	for i, del := range deleteFuncs {
 		go func(index int, delFunc deleteFunc) {
 			for {
-				err := delFunc(ctx, namespace, listOpts, kubevirtClient)
+				err := delFunc(ctx, namespace, listOpts, kubevirtClient, skip)
 				if err == nil {
 					results <- err
 					break
 				}
-				time.Sleep(10 * time.Second)
+				select {
+				case <-ctx.Done():
+					results <- ctx.Err()
+					return
+				case <-time.After(10 * time.Second):
+				}
 			}
 
 		}(i, del)
@@ -73,7 +91,7 @@ func (uninstaller *ClusterUninstaller) Run() error {
 	return nil
 }
 
-func (uninstaller *ClusterUninstaller) deleteAllVMs(ctx context.Context, namespace string, listOpts metav1.ListOptions, kubevirtClient ickubevirt.Client) error {
+func (uninstaller *ClusterUninstaller) deleteAllVMs(ctx context.Context, namespace string, listOpts metav1.ListOptions, kubevirtClient ickubevirt.Client, skip skipFunc) error {
 	vmList, err := kubevirtClient.ListVirtualMachine(ctx, namespace, listOpts)
 	if err != nil {
 		uninstaller.Logger.Errorf("failed to delete VirtualMachines: %s", err)
@@ -86,6 +104,9 @@ func (uninstaller *ClusterUninstaller) deleteAllVMs(ctx context.Context, namespa
 	}
 	uninstaller.Logger.Infof("Found %d Virtual Machines to delete in namespace %s", len(vmList.Items), namespace)
 	for _, vm := range vmList.Items {
+		if skip(providers.Resource{Type: "VirtualMachine", Name: vm.Name, Location: namespace}, vm.Labels) {
+			continue
+		}
 		uninstaller.Logger.Infof("Delete Virtual Machine %s from Namespace %s", vm.Name, namespace)
 		if err := kubevirtClient.DeleteVirtualMachine(ctx, namespace, vm.Name); err != nil {
 			uninstaller.Logger.Errorf("failed to delete VirtualMachines: %s", err)
@@ -102,7 +123,7 @@ func (uninstaller *ClusterUninstaller) deleteAllVMs(ctx context.Context, namespa
 	return nil
 }
 
-func (uninstaller *ClusterUninstaller) deleteAllDVs(ctx context.Context, namespace string, listOpts metav1.ListOptions, kubevirtClient ickubevirt.Client) error {
+func (uninstaller *ClusterUninstaller) deleteAllDVs(ctx context.Context, namespace string, listOpts metav1.ListOptions, kubevirtClient ickubevirt.Client, skip skipFunc) error {
 	dvList, err := kubevirtClient.ListDataVolume(ctx, namespace, listOpts)
 	if err != nil {
 		uninstaller.Logger.Errorf("failed to delete DataVolumes: %s", err)
@@ -115,6 +136,9 @@ func (uninstaller *ClusterUninstaller) deleteAllDVs(ctx context.Context, namespa
 	}
 	uninstaller.Logger.Infof("Found %d Data Volumes to delete in namespace %s", len(dvList.Items), namespace)
 	for _, dv := range dvList.Items {
+		if skip(providers.Resource{Type: "DataVolume", Name: dv.Name, Location: namespace}, dv.Labels) {
+			continue
+		}
 		uninstaller.Logger.Infof("Delete Data Volume %s from Namespace %s", dv.Name, namespace)
 		if err := kubevirtClient.DeleteDataVolume(ctx, namespace, dv.Name); err != nil {
 			uninstaller.Logger.Errorf("failed to delete DataVolumes: %s", err)
@@ -131,7 +155,7 @@ func (uninstaller *ClusterUninstaller) deleteAllDVs(ctx context.Context, namespa
 	return nil
 }
 
-func (uninstaller *ClusterUninstaller) deleteAllSecrets(ctx context.Context, namespace string, listOpts metav1.ListOptions, kubevirtClient ickubevirt.Client) error {
+func (uninstaller *ClusterUninstaller) deleteAllSecrets(ctx context.Context, namespace string, listOpts metav1.ListOptions, kubevirtClient ickubevirt.Client, skip skipFunc) error {
 	secretList, err := kubevirtClient.ListSecret(ctx, namespace, listOpts)
 	if err != nil {
 		uninstaller.Logger.Errorf("failed to delete Secrets: %s", err)
@@ -144,6 +168,9 @@ func (uninstaller *ClusterUninstaller) deleteAllSecrets(ctx context.Context, nam
 	}
 	uninstaller.Logger.Infof("Found %d Secrets to delete in namespace %s", len(secretList.Items), namespace)
 	for _, secret := range secretList.Items {
+		if skip(providers.Resource{Type: "Secret", Name: secret.Name, Location: namespace}, secret.Labels) {
+			continue
+		}
 		uninstaller.Logger.Infof("Delete Secret %s from Namespace %s", secret.Name, namespace)
 		if err := kubevirtClient.DeleteSecret(ctx, namespace, secret.Name); err != nil {
 			uninstaller.Logger.Errorf("failed to delete Secrets: %s", err)
@@ -0,0 +1,51 @@
+package kubevirt
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+
+	ickubevirt "github.com/openshift/installer/pkg/asset/installconfig/kubevirt"
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+func (uninstaller *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	ctx := context.Background()
+	namespace := uninstaller.Metadata.Kubevirt.Namespace
+
+	listOpts := metav1.ListOptions{LabelSelector: apilabels.FormatLabels(uninstaller.Metadata.Kubevirt.Labels)}
+	kubevirtClient, err := ickubevirt.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	vmList, err := kubevirtClient.ListVirtualMachine(ctx, namespace, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, vm := range vmList.Items {
+		resources = append(resources, providers.Resource{Type: "VirtualMachine", Name: vm.Name, Location: namespace})
+	}
+
+	dvList, err := kubevirtClient.ListDataVolume(ctx, namespace, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, dv := range dvList.Items {
+		resources = append(resources, providers.Resource{Type: "DataVolume", Name: dv.Name, Location: namespace})
+	}
+
+	secretList, err := kubevirtClient.ListSecret(ctx, namespace, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secretList.Items {
+		resources = append(resources, providers.Resource{Type: "Secret", Name: secret.Name, Location: namespace})
+	}
+
+	providers.SortResources(resources)
+	return resources, nil
+}
@@ -0,0 +1,107 @@
+// +build libvirt
+
+package libvirt
+
+import (
+	libvirt "github.com/libvirt/libvirt-go"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// listFunc is the interface a function needs to implement to list resources.
+type listFunc func(conn *libvirt.Connect, filter filterFunc) ([]providers.Resource, error)
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+func (o *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	conn, err := libvirt.NewConnect(o.LibvirtURI)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to Libvirt daemon")
+	}
+	defer conn.Close()
+
+	var resources []providers.Resource
+	for _, list := range []listFunc{
+		listDomains,
+		listNetworks,
+		listStoragePools,
+	} {
+		found, err := list(conn, o.Filter)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, found...)
+	}
+	providers.SortResources(resources)
+	return resources, nil
+}
+
+func listDomains(conn *libvirt.Connect, filter filterFunc) ([]providers.Resource, error) {
+	domains, err := conn.ListAllDomains(0)
+	if err != nil {
+		return nil, errors.Wrap(err, "list domains")
+	}
+
+	var resources []providers.Resource
+	for _, domain := range domains {
+		defer domain.Free()
+		dName, err := domain.GetName()
+		if err != nil {
+			return nil, errors.Wrap(err, "get domain name")
+		}
+		if filter(dName) {
+			resources = append(resources, providers.Resource{Type: "domain", Name: dName})
+		}
+	}
+	return resources, nil
+}
+
+func listNetworks(conn *libvirt.Connect, filter filterFunc) ([]providers.Resource, error) {
+	networks, err := conn.ListNetworks()
+	if err != nil {
+		return nil, errors.Wrap(err, "list networks")
+	}
+
+	var resources []providers.Resource
+	for _, nName := range networks {
+		if filter(nName) {
+			resources = append(resources, providers.Resource{Type: "network", Name: nName})
+		}
+	}
+	return resources, nil
+}
+
+func listStoragePools(conn *libvirt.Connect, filter filterFunc) ([]providers.Resource, error) {
+	pools, err := conn.ListStoragePools()
+	if err != nil {
+		return nil, errors.Wrap(err, "list storage pools")
+	}
+
+	var resources []providers.Resource
+	for _, pname := range pools {
+		if !filter(pname) {
+			continue
+		}
+
+		pool, err := conn.LookupStoragePoolByName(pname)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get storage pool %q", pname)
+		}
+		defer pool.Free()
+
+		vols, err := pool.ListAllStorageVolumes(0)
+		if err != nil {
+			return nil, errors.Wrapf(err, "list volumes in %q", pname)
+		}
+		for _, vol := range vols {
+			defer vol.Free()
+			vName, err := vol.GetName()
+			if err != nil {
+				return nil, errors.Wrapf(err, "get volume names in %q", pname)
+			}
+			resources = append(resources, providers.Resource{Type: "volume", Name: vName, Location: pname})
+		}
+		resources = append(resources, providers.Resource{Type: "pool", Name: pname})
+	}
+	return resources, nil
+}
@@ -3,6 +3,7 @@
 package libvirt
 
 import (
+	"context"
 	"strings"
 
 	libvirt "github.com/libvirt/libvirt-go"
@@ -37,8 +38,12 @@ var AlwaysTrueFilter = func() filterFunc {
 	}
 }
 
+// skipFunc reports whether a resource matched by the filter must be left in
+// place.
+type skipFunc func(resource providers.Resource) bool
+
 // deleteFunc is the interface a function needs to implement to be delete resources.
-type deleteFunc func(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) error
+type deleteFunc func(conn *libvirt.Connect, filter filterFunc, skip skipFunc, logger logrus.FieldLogger) error
 
 // ClusterUninstaller holds the various options for the cluster we want to delete.
 type ClusterUninstaller struct {
@@ -57,7 +62,17 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 }
 
 // Run is the entrypoint to start the uninstall process.
-func (o *ClusterUninstaller) Run() error {
+func (o *ClusterUninstaller) Run(ctx context.Context, options providers.RunOptions) (*providers.Report, error) {
+	return providers.RunWithInventory(o.Logger, o.Inventory, func(recorder *providers.Recorder) error {
+		return o.destroy(ctx, func(resource providers.Resource) bool {
+			return options.Exclusions.Skip(o.Logger, recorder, resource, nil)
+		})
+	})
+}
+
+// destroy removes the cluster resources that skip does not select, stopping
+// between resource types when ctx is done.
+func (o *ClusterUninstaller) destroy(ctx context.Context, skip skipFunc) error {
 	conn, err := libvirt.NewConnect(o.LibvirtURI)
 	if err != nil {
 		return errors.Wrap(err, "failed to connect to Libvirt daemon")
@@ -68,7 +83,10 @@ func (o *ClusterUninstaller) Run() error {
 		deleteNetwork,
 		deleteStoragePool,
 	} {
-		err = del(conn, o.Filter, o.Logger)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err = del(conn, o.Filter, skip, o.Logger)
 		if err != nil {
 			return err
 		}
@@ -82,12 +100,12 @@ func (o *ClusterUninstaller) Run() error {
 // additional nodes after the initial list call.  We continue deleting
 // domains until we either hit an error or we have a list call with no
 // matching domains.
-func deleteDomains(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) error {
+func deleteDomains(conn *libvirt.Connect, filter filterFunc, skip skipFunc, logger logrus.FieldLogger) error {
 	logger.Debug("Deleting libvirt domains")
 	var err error
 	nothingToDelete := false
 	for !nothingToDelete {
-		nothingToDelete, err = deleteDomainsSinglePass(conn, filter, logger)
+		nothingToDelete, err = deleteDomainsSinglePass(conn, filter, skip, logger)
 		if err != nil {
 			return err
 		}
@@ -95,7 +113,7 @@ func deleteDomains(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 	return nil
 }
 
-func deleteDomainsSinglePass(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) (nothingToDelete bool, err error) {
+func deleteDomainsSinglePass(conn *libvirt.Connect, filter filterFunc, skip skipFunc, logger logrus.FieldLogger) (nothingToDelete bool, err error) {
 	domains, err := conn.ListAllDomains(0)
 	if err != nil {
 		return false, errors.Wrap(err, "list domains")
@@ -108,7 +126,7 @@ func deleteDomainsSinglePass(conn *libvirt.Connect, filter filterFunc, logger lo
 		if err != nil {
 			return false, errors.Wrap(err, "get domain name")
 		}
-		if !filter(dName) {
+		if !filter(dName) || skip(providers.Resource{Type: "domain", Name: dName}) {
 			continue
 		}
 
@@ -132,7 +150,7 @@ func deleteDomainsSinglePass(conn *libvirt.Connect, filter filterFunc, logger lo
 	return nothingToDelete, nil
 }
 
-func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) error {
+func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, skip skipFunc, logger logrus.FieldLogger) error {
 	logger.Debug("Deleting libvirt volumes")
 
 	pools, err := conn.ListStoragePools()
@@ -142,7 +160,7 @@ func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, logger logrus.F
 
 	for _, pname := range pools {
 		// pool name that returns true from filter
-		if !filter(pname) {
+		if !filter(pname) || skip(providers.Resource{Type: "pool", Name: pname}) {
 			continue
 		}
 
@@ -158,17 +176,26 @@ func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, logger logrus.F
 			return errors.Wrapf(err, "list volumes in %q", pname)
 		}
 
+		kept := false
 		for _, vol := range vols {
 			defer vol.Free()
 			vName, err := vol.GetName()
 			if err != nil {
 				return errors.Wrapf(err, "get volume names in %q", pname)
 			}
+			if skip(providers.Resource{Type: "volume", Name: vName, Location: pname}) {
+				kept = true
+				continue
+			}
 			if err := vol.Delete(0); err != nil {
 				return errors.Wrapf(err, "delete volume %q from %q", vName, pname)
 			}
 			logger.WithField("volume", vName).Info("Deleted volume")
 		}
+		if kept {
+			logger.WithField("pool", pname).Info("Keeping pool with excluded volumes")
+			continue
+		}
 
 		// blow away entire pool.
 		if err := pool.Destroy(); err != nil {
@@ -188,7 +215,7 @@ func deleteStoragePool(conn *libvirt.Connect, filter filterFunc, logger logrus.F
 	return nil
 }
 
-func deleteNetwork(conn *libvirt.Connect, filter filterFunc, logger logrus.FieldLogger) error {
+func deleteNetwork(conn *libvirt.Connect, filter filterFunc, skip skipFunc, logger logrus.FieldLogger) error {
 	logger.Debug("Deleting libvirt network")
 
 	networks, err := conn.ListNetworks()
@@ -197,7 +224,7 @@ func deleteNetwork(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 	}
 
 	for _, nName := range networks {
-		if !filter(nName) {
+		if !filter(nName) || skip(providers.Resource{Type: "network", Name: nName}) {
 			continue
 		}
 		network, err := conn.LookupNetworkByName(nName)
@@ -0,0 +1,248 @@
+package destroy
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/asset/cluster/aws"
+	"github.com/openshift/installer/pkg/asset/cluster/azure"
+	"github.com/openshift/installer/pkg/asset/cluster/baremetal"
+	"github.com/openshift/installer/pkg/asset/cluster/gcp"
+	"github.com/openshift/installer/pkg/asset/cluster/kubevirt"
+	"github.com/openshift/installer/pkg/asset/cluster/libvirt"
+	"github.com/openshift/installer/pkg/asset/cluster/openstack"
+	"github.com/openshift/installer/pkg/asset/cluster/ovirt"
+	"github.com/openshift/installer/pkg/asset/cluster/vsphere"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	baremetaltypes "github.com/openshift/installer/pkg/types/baremetal"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+	kubevirttypes "github.com/openshift/installer/pkg/types/kubevirt"
+	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+	ovirttypes "github.com/openshift/installer/pkg/types/ovirt"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// VSpherePasswordEnvVar names the environment variable holding the vCenter
+// password when no password file is given, so that the password does not
+// show in the process list.
+const VSpherePasswordEnvVar = "OPENSHIFT_INSTALL_VSPHERE_PASSWORD"
+
+// MetadataOptions describes a cluster to destroy when its metadata.json is
+// not available. The field names match the `destroy cluster` flags.
+type MetadataOptions struct {
+	Platform  string
+	InfraID   string
+	ClusterID string
+
+	// Region is used by AWS, Azure and GCP.
+	Region string
+	// Cloud is the clouds.yaml entry for OpenStack or the cloud
+	// environment name for Azure.
+	Cloud string
+	// ProjectID is the GCP project.
+	ProjectID string
+	// ResourceGroup is the Azure resource group, defaulting to
+	// <infraID>-rg.
+	ResourceGroup string
+	// URI is the libvirt connection URI for libvirt and bare metal.
+	URI string
+	// VCenter, Username and Password are the vSphere credentials. The
+	// password is read from PasswordFile, or else from
+	// VSpherePasswordEnvVar, when not set.
+	VCenter      string
+	Username     string
+	Password     string
+	PasswordFile string
+	// Namespace is the KubeVirt namespace of the cluster.
+	Namespace string
+
+	// Filters holds additional key=value tags, all of which must match.
+	Filters []string
+}
+
+// Metadata validates the options for the selected platform and builds the
+// cluster metadata that the installer would have written for the cluster.
+func (o *MetadataOptions) Metadata() (*types.ClusterMetadata, error) {
+	if o.Platform == vspheretypes.Name {
+		if err := o.readPassword(); err != nil {
+			return nil, err
+		}
+	}
+	if err := o.validate().ToAggregate(); err != nil {
+		return nil, err
+	}
+	filters, _ := parseFilters(o.Filters)
+
+	metadata := &types.ClusterMetadata{
+		ClusterID: o.ClusterID,
+		InfraID:   o.InfraID,
+	}
+	config := &types.InstallConfig{}
+	switch o.Platform {
+	case awstypes.Name:
+		config.Platform.AWS = &awstypes.Platform{Region: o.Region}
+		md := aws.Metadata(o.ClusterID, o.InfraID, config)
+		if o.ClusterID == "" {
+			// Without a cluster ID only the infra ID tag identifies the cluster.
+			md.Identifier = md.Identifier[:1]
+		}
+		// The identifiers are ORed and the tags of each are ANDed, so the
+		// filters narrow every identifier.
+		for _, identifier := range md.Identifier {
+			for key, value := range filters {
+				identifier[key] = value
+			}
+		}
+		metadata.AWS = md
+	case azuretypes.Name:
+		config.Platform.Azure = &azuretypes.Platform{
+			CloudName:         azuretypes.CloudEnvironment(o.Cloud),
+			Region:            o.Region,
+			ResourceGroupName: o.ResourceGroup,
+		}
+		metadata.Azure = azure.Metadata(config)
+	case baremetaltypes.Name:
+		config.Platform.BareMetal = &baremetaltypes.Platform{LibvirtURI: o.URI}
+		metadata.BareMetal = baremetal.Metadata(config)
+	case gcptypes.Name:
+		config.Platform.GCP = &gcptypes.Platform{Region: o.Region, ProjectID: o.ProjectID}
+		metadata.GCP = gcp.Metadata(config)
+	case kubevirttypes.Name:
+		config.Platform.Kubevirt = &kubevirttypes.Platform{Namespace: o.Namespace}
+		metadata.Kubevirt = kubevirt.Metadata(o.InfraID, config)
+	case libvirttypes.Name:
+		config.Platform.Libvirt = &libvirttypes.Platform{URI: o.URI}
+		metadata.Libvirt = libvirt.Metadata(config)
+	case openstacktypes.Name:
+		config.Platform.OpenStack = &openstacktypes.Platform{Cloud: o.Cloud}
+		md := openstack.Metadata(o.InfraID, config)
+		for key, value := range filters {
+			md.Identifier[key] = value
+		}
+		metadata.OpenStack = md
+	case ovirttypes.Name:
+		config.Platform.Ovirt = &ovirttypes.Platform{}
+		metadata.Ovirt = ovirt.Metadata(config)
+	case vspheretypes.Name:
+		config.Platform.VSphere = &vspheretypes.Platform{
+			VCenter:  o.VCenter,
+			Username: o.Username,
+			Password: o.Password,
+		}
+		metadata.VSphere = vsphere.Metadata(config)
+	}
+	return metadata, nil
+}
+
+func (o *MetadataOptions) validate() field.ErrorList {
+	allErrs := field.ErrorList{}
+	if o.InfraID == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("infra-id"), "the infra ID identifies the cluster resources"))
+	}
+	if _, err := parseFilters(o.Filters); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	required := func(name, value string) {
+		if value == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath(name), "required for "+o.Platform))
+		}
+	}
+	forbidFilters := func() {
+		if len(o.Filters) > 0 {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("filter"), "filters are not supported for "+o.Platform))
+		}
+	}
+	switch o.Platform {
+	case awstypes.Name:
+		required("region", o.Region)
+	case azuretypes.Name:
+		forbidFilters()
+		if o.Cloud != "" {
+			switch cloud := azuretypes.CloudEnvironment(o.Cloud); cloud {
+			case azuretypes.PublicCloud, azuretypes.USGovernmentCloud, azuretypes.ChinaCloud, azuretypes.GermanCloud:
+			default:
+				allErrs = append(allErrs, field.NotSupported(field.NewPath("cloud"), o.Cloud, []string{
+					string(azuretypes.PublicCloud),
+					string(azuretypes.USGovernmentCloud),
+					string(azuretypes.ChinaCloud),
+					string(azuretypes.GermanCloud),
+				}))
+			}
+		}
+	case baremetaltypes.Name, libvirttypes.Name:
+		forbidFilters()
+		required("uri", o.URI)
+	case gcptypes.Name:
+		forbidFilters()
+		required("region", o.Region)
+		required("project-id", o.ProjectID)
+	case kubevirttypes.Name:
+		forbidFilters()
+		required("namespace", o.Namespace)
+	case openstacktypes.Name:
+		required("cloud", o.Cloud)
+	case ovirttypes.Name:
+		forbidFilters()
+	case vspheretypes.Name:
+		forbidFilters()
+		required("vcenter", o.VCenter)
+		required("username", o.Username)
+		if o.Password == "" {
+			allErrs = append(allErrs, field.Required(field.NewPath("password-file"), "the vCenter password is read from the file or the "+VSpherePasswordEnvVar+" environment variable"))
+		}
+	case "":
+		allErrs = append(allErrs, field.Required(field.NewPath("platform"), "a platform is required"))
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("platform"), o.Platform, []string{
+			awstypes.Name,
+			azuretypes.Name,
+			baremetaltypes.Name,
+			gcptypes.Name,
+			kubevirttypes.Name,
+			libvirttypes.Name,
+			openstacktypes.Name,
+			ovirttypes.Name,
+			vspheretypes.Name,
+		}))
+	}
+	return allErrs
+}
+
+// readPassword sets the vSphere password from PasswordFile or
+// VSpherePasswordEnvVar when it is not set.
+func (o *MetadataOptions) readPassword() error {
+	if o.Password != "" {
+		return nil
+	}
+	if o.PasswordFile == "" {
+		o.Password = os.Getenv(VSpherePasswordEnvVar)
+		return nil
+	}
+	data, err := ioutil.ReadFile(o.PasswordFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the vCenter password")
+	}
+	o.Password = strings.TrimRight(string(data), "\r\n")
+	return nil
+}
+
+// parseFilters converts key=value strings to a map.
+func parseFilters(filters []string) (map[string]string, *field.Error) {
+	parsed := make(map[string]string, len(filters))
+	for i, filter := range filters {
+		parts := strings.SplitN(filter, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, field.Invalid(field.NewPath("filter").Index(i), filter, "must be of the form key=value")
+		}
+		parsed[parts[0]] = parts[1]
+	}
+	return parsed, nil
+}
@@ -0,0 +1,192 @@
+package destroy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestMetadataOptions(t *testing.T) {
+	cases := []struct {
+		name     string
+		options  MetadataOptions
+		expected *types.ClusterMetadata
+		err      string
+	}{{
+		name: "aws infra ID only",
+		options: MetadataOptions{
+			Platform: "aws",
+			InfraID:  "test-abc12",
+			Region:   "us-east-1",
+		},
+		expected: &types.ClusterMetadata{
+			InfraID: "test-abc12",
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				AWS: &awstypes.Metadata{
+					Region: "us-east-1",
+					Identifier: []map[string]string{
+						{"kubernetes.io/cluster/test-abc12": "owned"},
+					},
+				},
+			},
+		},
+	}, {
+		name: "aws with cluster ID and filters",
+		options: MetadataOptions{
+			Platform:  "aws",
+			InfraID:   "test-abc12",
+			ClusterID: "0000-1111",
+			Region:    "us-east-1",
+			Filters:   []string{"team=ci", "job=e2e"},
+		},
+		expected: &types.ClusterMetadata{
+			InfraID:   "test-abc12",
+			ClusterID: "0000-1111",
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				AWS: &awstypes.Metadata{
+					Region: "us-east-1",
+					Identifier: []map[string]string{
+						{"kubernetes.io/cluster/test-abc12": "owned", "job": "e2e", "team": "ci"},
+						{"openshiftClusterID": "0000-1111", "job": "e2e", "team": "ci"},
+					},
+				},
+			},
+		},
+	}, {
+		name: "gcp",
+		options: MetadataOptions{
+			Platform:  "gcp",
+			InfraID:   "test-abc12",
+			Region:    "us-central1",
+			ProjectID: "my-project",
+		},
+		expected: &types.ClusterMetadata{
+			InfraID: "test-abc12",
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				GCP: &gcptypes.Metadata{Region: "us-central1", ProjectID: "my-project"},
+			},
+		},
+	}, {
+		name: "openstack filters are combined",
+		options: MetadataOptions{
+			Platform: "openstack",
+			InfraID:  "test-abc12",
+			Cloud:    "openstack",
+			Filters:  []string{"team=ci"},
+		},
+		expected: &types.ClusterMetadata{
+			InfraID: "test-abc12",
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				OpenStack: &openstacktypes.Metadata{
+					Cloud: "openstack",
+					Identifier: map[string]string{
+						"openshiftClusterID": "test-abc12",
+						"team":               "ci",
+					},
+				},
+			},
+		},
+	}, {
+		name:    "missing infra ID",
+		options: MetadataOptions{Platform: "aws", Region: "us-east-1"},
+		err:     `infra-id: Required value: the infra ID identifies the cluster resources`,
+	}, {
+		name:    "missing gcp project",
+		options: MetadataOptions{Platform: "gcp", InfraID: "test-abc12", Region: "us-central1"},
+		err:     `project-id: Required value: required for gcp`,
+	}, {
+		name:    "unsupported platform",
+		options: MetadataOptions{Platform: "none", InfraID: "test-abc12"},
+		err:     `platform: Unsupported value: "none": supported values: "aws", "azure", "baremetal", "gcp", "kubevirt", "libvirt", "openstack", "ovirt", "vsphere"`,
+	}, {
+		name:    "invalid filter",
+		options: MetadataOptions{Platform: "aws", InfraID: "test-abc12", Region: "us-east-1", Filters: []string{"team"}},
+		err:     `filter[0]: Invalid value: "team": must be of the form key=value`,
+	}, {
+		name:    "filters on gcp",
+		options: MetadataOptions{Platform: "gcp", InfraID: "test-abc12", Region: "us-central1", ProjectID: "p", Filters: []string{"team=ci"}},
+		err:     `filter: Forbidden: filters are not supported for gcp`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata, err := tc.options.Metadata()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, metadata)
+		})
+	}
+}
+
+func TestMetadataOptionsVSpherePassword(t *testing.T) {
+	dir, err := ioutil.TempDir("", "destroy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	passwordFile := filepath.Join(dir, "password")
+	if err := ioutil.WriteFile(passwordFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, ok := os.LookupEnv(VSpherePasswordEnvVar); ok {
+		defer os.Setenv(VSpherePasswordEnvVar, value)
+	} else {
+		defer os.Unsetenv(VSpherePasswordEnvVar)
+	}
+
+	cases := []struct {
+		name     string
+		file     string
+		env      string
+		password string
+		err      string
+	}{{
+		name:     "file",
+		file:     passwordFile,
+		env:      "from-env",
+		password: "from-file",
+	}, {
+		name:     "environment",
+		env:      "from-env",
+		password: "from-env",
+	}, {
+		name: "missing file",
+		file: filepath.Join(dir, "missing"),
+		err:  "failed to read the vCenter password: open " + filepath.Join(dir, "missing") + ": no such file or directory",
+	}, {
+		name: "missing password",
+		err:  "password-file: Required value: the vCenter password is read from the file or the " + VSpherePasswordEnvVar + " environment variable",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv(VSpherePasswordEnvVar, tc.env)
+			options := MetadataOptions{
+				Platform:     "vsphere",
+				InfraID:      "test-abc12",
+				VCenter:      "vcenter.example.com",
+				Username:     "admin",
+				PasswordFile: tc.file,
+			}
+			metadata, err := options.Metadata()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, &vspheretypes.Metadata{VCenter: "vcenter.example.com", Username: "admin", Password: tc.password}, metadata.VSphere)
+			}
+		})
+	}
+}
@@ -0,0 +1,453 @@
+package openstack
+
+import (
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/apiversions"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	sg "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/subnetpools"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	openstackdefaults "github.com/openshift/installer/pkg/types/openstack/defaults"
+)
+
+// listFunc is the interface a function needs to implement to list the
+// resources that the matching deleteFunc would remove.
+type listFunc func(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error)
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+func (o *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	opts := openstackdefaults.DefaultClientOpts(o.Cloud)
+
+	listFuncs := map[string]listFunc{
+		"listServers":        listServers,
+		"listServerGroups":   listServerGroups,
+		"listTrunks":         listTrunks,
+		"listLoadBalancers":  listLoadBalancers,
+		"listPorts":          listPorts,
+		"listSecurityGroups": listSecurityGroups,
+		"listRouters":        listRouters,
+		"listSubnets":        listSubnets,
+		"listSubnetPools":    listSubnetPools,
+		"listNetworks":       listNetworks,
+		"listContainers":     listContainers,
+		"listVolumes":        listVolumes,
+		"listFloatingIPs":    listFloatingIPs,
+		"listImages":         listImages,
+	}
+
+	var resources []providers.Resource
+	for name, list := range listFuncs {
+		found, err := list(opts, o.Filter)
+		if err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+		resources = append(resources, found...)
+	}
+	providers.SortResources(resources)
+	return resources, nil
+}
+
+// clusterIDFromFilter returns the openshiftClusterID used as a name prefix for
+// resources that cannot be tagged.
+func clusterIDFromFilter(filter Filter) string {
+	for k, v := range filter {
+		if strings.ToLower(k) == "openshiftclusterid" {
+			return v
+		}
+	}
+	return ""
+}
+
+func listServers(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("compute", opts)
+	if err != nil {
+		return nil, err
+	}
+	allPages, err := servers.List(conn, servers.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allServers, err := servers.ExtractServers(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	serverObjects := []ObjectWithTags{}
+	names := map[string]string{}
+	for _, server := range allServers {
+		serverObjects = append(serverObjects, ObjectWithTags{ID: server.ID, Tags: server.Metadata})
+		names[server.ID] = server.Name
+	}
+
+	var resources []providers.Resource
+	for _, server := range filterObjects(serverObjects, filter) {
+		resources = append(resources, providers.Resource{Type: "server", Name: server.ID + " (" + names[server.ID] + ")"})
+	}
+	return resources, nil
+}
+
+func listServerGroups(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	clusterID := clusterIDFromFilter(filter)
+	conn, err := clientconfig.NewServiceClient("compute", opts)
+	if err != nil {
+		return nil, err
+	}
+	allPages, err := servergroups.List(conn).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allServerGroups, err := servergroups.ExtractServerGroups(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, serverGroup := range allServerGroups {
+		if strings.HasPrefix(serverGroup.Name, clusterID) {
+			resources = append(resources, providers.Resource{Type: "servergroup", Name: serverGroup.ID + " (" + serverGroup.Name + ")"})
+		}
+	}
+	return resources, nil
+}
+
+func listTrunks(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := trunks.ListOpts{TagsAny: strings.Join(filterTags(filter), ",")}
+	allPages, err := trunks.List(conn, listOpts).AllPages()
+	if err != nil {
+		// The cloud doesn't support trunk ports
+		var gerr gophercloud.ErrDefault404
+		if errors.As(err, &gerr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	allTrunks, err := trunks.ExtractTrunks(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, trunk := range allTrunks {
+		resources = append(resources, providers.Resource{Type: "trunk", Name: trunk.ID})
+	}
+	return resources, nil
+}
+
+func listLoadBalancers(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("load-balancer", opts)
+	if err != nil {
+		// Octavia is not available for the cloud
+		var gerr *gophercloud.ErrEndpointNotFound
+		if errors.As(err, &gerr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	versionPages, err := apiversions.List(conn).AllPages()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list api versions")
+	}
+	allAPIVersions, err := apiversions.ExtractAPIVersions(versionPages)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to extract api versions")
+	}
+	octaviaTagSupport := false
+	for _, apiVersion := range allAPIVersions {
+		if apiVersion.ID >= minOctaviaVersionWithTagSupport {
+			octaviaTagSupport = true
+		}
+	}
+
+	tags := filterTags(filter)
+	listOpts := []loadbalancers.ListOpts{{Description: strings.Join(tags, ",")}}
+	if octaviaTagSupport {
+		listOpts = append(listOpts, loadbalancers.ListOpts{TagsAny: tags})
+	}
+
+	found := map[string]loadbalancers.LoadBalancer{}
+	for _, lo := range listOpts {
+		allPages, err := loadbalancers.List(conn, lo).AllPages()
+		if err != nil {
+			return nil, err
+		}
+		allLoadBalancers, err := loadbalancers.ExtractLoadBalancers(allPages)
+		if err != nil {
+			return nil, err
+		}
+		for _, loadbalancer := range allLoadBalancers {
+			found[loadbalancer.ID] = loadbalancer
+		}
+	}
+
+	var resources []providers.Resource
+	for _, loadbalancer := range found {
+		resources = append(resources, providers.Resource{Type: "loadbalancer", Name: loadbalancer.ID + " (" + loadbalancer.Name + ")"})
+	}
+	return resources, nil
+}
+
+func listPorts(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := ports.ListOpts{TagsAny: strings.Join(filterTags(filter), ",")}
+	allPages, err := ports.List(conn, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allPorts, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, port := range allPorts {
+		resources = append(resources, providers.Resource{Type: "port", Name: port.ID})
+	}
+	return resources, nil
+}
+
+func listSecurityGroups(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := sg.ListOpts{TagsAny: strings.Join(filterTags(filter), ",")}
+	allPages, err := sg.List(conn, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allGroups, err := sg.ExtractGroups(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, group := range allGroups {
+		resources = append(resources, providers.Resource{Type: "securitygroup", Name: group.ID + " (" + group.Name + ")"})
+	}
+	return resources, nil
+}
+
+func listRouters(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := routers.ListOpts{TagsAny: strings.Join(filterTags(filter), ",")}
+	allPages, err := routers.List(conn, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allRouters, err := routers.ExtractRouters(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, router := range allRouters {
+		resources = append(resources, providers.Resource{Type: "router", Name: router.ID + " (" + router.Name + ")"})
+	}
+	return resources, nil
+}
+
+func listSubnets(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := subnets.ListOpts{TagsAny: strings.Join(filterTags(filter), ",")}
+	allPages, err := subnets.List(conn, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allSubnets, err := subnets.ExtractSubnets(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, subnet := range allSubnets {
+		resources = append(resources, providers.Resource{Type: "subnet", Name: subnet.ID + " (" + subnet.Name + ")"})
+	}
+	return resources, nil
+}
+
+func listSubnetPools(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := subnetpools.ListOpts{TagsAny: strings.Join(filterTags(filter), ",")}
+	allPages, err := subnetpools.List(conn, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allSubnetPools, err := subnetpools.ExtractSubnetPools(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, subnetPool := range allSubnetPools {
+		resources = append(resources, providers.Resource{Type: "subnetpool", Name: subnetPool.ID + " (" + subnetPool.Name + ")"})
+	}
+	return resources, nil
+}
+
+func listNetworks(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := networks.ListOpts{TagsAny: strings.Join(filterTags(filter), ",")}
+	allPages, err := networks.List(conn, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allNetworks, err := networks.ExtractNetworks(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, network := range allNetworks {
+		resources = append(resources, providers.Resource{Type: "network", Name: network.ID + " (" + network.Name + ")"})
+	}
+	return resources, nil
+}
+
+func listContainers(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("object-store", opts)
+	if err != nil {
+		// Swift is not available for the cloud
+		var gerr *gophercloud.ErrEndpointNotFound
+		if errors.As(err, &gerr) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	allPages, err := containers.List(conn, containers.ListOpts{Full: false}).AllPages()
+	if err != nil {
+		// The user doesn't have the swiftoperator role, see deleteContainers.
+		var gerr403 gophercloud.ErrDefault403
+		var gerr401 gophercloud.ErrDefault401
+		if errors.As(err, &gerr403) || errors.As(err, &gerr401) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	allContainers, err := containers.ExtractNames(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, container := range allContainers {
+		metadata, err := containers.Get(conn, container, nil).ExtractMetadata()
+		if err != nil {
+			var gerr gophercloud.ErrDefault404
+			if errors.As(err, &gerr) {
+				continue
+			}
+			return nil, err
+		}
+		for key, val := range filter {
+			// Swift mangles the case of metadata keys
+			if metadata[strings.Title(strings.ToLower(key))] == val {
+				resources = append(resources, providers.Resource{Type: "container", Name: container})
+				break
+			}
+		}
+	}
+	return resources, nil
+}
+
+func listVolumes(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	clusterID := clusterIDFromFilter(filter)
+	conn, err := clientconfig.NewServiceClient("volume", opts)
+	if err != nil {
+		return nil, err
+	}
+	allPages, err := volumes.List(conn, volumes.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allVolumes, err := volumes.ExtractVolumes(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, volume := range allVolumes {
+		if strings.HasPrefix(volume.Name, clusterID) {
+			resources = append(resources, providers.Resource{Type: "volume", Name: volume.ID + " (" + volume.Name + ")"})
+		}
+	}
+	return resources, nil
+}
+
+func listFloatingIPs(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return nil, err
+	}
+	listOpts := floatingips.ListOpts{TagsAny: strings.Join(filterTags(filter), ",")}
+	allPages, err := floatingips.List(conn, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allFloatingIPs, err := floatingips.ExtractFloatingIPs(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, floatingIP := range allFloatingIPs {
+		resources = append(resources, providers.Resource{Type: "floatingip", Name: floatingIP.ID + " (" + floatingIP.FloatingIP + ")"})
+	}
+	return resources, nil
+}
+
+func listImages(opts *clientconfig.ClientOpts, filter Filter) ([]providers.Resource, error) {
+	conn, err := clientconfig.NewServiceClient("image", opts)
+	if err != nil {
+		return nil, err
+	}
+	allPages, err := images.List(conn, images.ListOpts{Tags: filterTags(filter)}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	allImages, err := images.ExtractImages(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []providers.Resource
+	for _, image := range allImages {
+		resources = append(resources, providers.Resource{Type: "image", Name: image.ID + " (" + image.Name + ")"})
+	}
+	return resources, nil
+}
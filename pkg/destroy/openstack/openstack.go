@@ -1,6 +1,7 @@
 package openstack
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -53,10 +54,15 @@ type ObjectWithTags struct {
 	Tags map[string]string
 }
 
+// skipFunc reports whether a resource with the given tags or metadata must be
+// left in place.
+type skipFunc func(resource providers.Resource, tags map[string]string) bool
+
 // deleteFunc type is the interface a function needs to implement to be called as a goroutine.
 // The (bool, error) return type mimics wait.ExponentialBackoff where the bool indicates successful
-// completion, and the error is for unrecoverable errors.
-type deleteFunc func(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error)
+// completion, and the error is for unrecoverable errors. Resources selected by skip are not
+// deleted and do not hold up completion.
+type deleteFunc func(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error)
 
 // ClusterUninstaller holds the various options for the cluster we want to delete.
 type ClusterUninstaller struct {
@@ -80,7 +86,19 @@ func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.
 }
 
 // Run is the entrypoint to start the uninstall process.
-func (o *ClusterUninstaller) Run() error {
+// Each resource type is deleted by its own goroutine, so the concurrency
+// option is ignored.
+func (o *ClusterUninstaller) Run(ctx context.Context, options providers.RunOptions) (*providers.Report, error) {
+	return providers.RunWithInventory(o.Logger, o.Inventory, func(recorder *providers.Recorder) error {
+		return o.destroy(ctx, recorder, func(resource providers.Resource, tags map[string]string) bool {
+			return options.Exclusions.Skip(o.Logger, recorder, resource, tags)
+		})
+	})
+}
+
+// destroy removes the cluster resources that skip does not select. A
+// user-provided primary network is untagged and recorded as shared.
+func (o *ClusterUninstaller) destroy(ctx context.Context, recorder *providers.Recorder, skip skipFunc) error {
 	// deleteFuncs contains the functions that will be launched as
 	// goroutines.
 	deleteFuncs := map[string]deleteFunc{
@@ -99,16 +117,16 @@ func (o *ClusterUninstaller) Run() error {
 		"deleteFloatingIPs":    deleteFloatingIPs,
 		"deleteImages":         deleteImages,
 	}
-	returnChannel := make(chan string)
+	returnChannel := make(chan string, len(deleteFuncs))
 
 	opts := openstackdefaults.DefaultClientOpts(o.Cloud)
 
 	// launch goroutines
 	for name, function := range deleteFuncs {
-		go deleteRunner(name, function, opts, o.Filter, o.Logger, returnChannel)
+		go deleteRunner(ctx, name, function, opts, o.Filter, skip, o.Logger, returnChannel)
 	}
 
-	err := cleanRouterRunner(opts, o.Filter, o.Logger, o.InfraID)
+	err := cleanRouterRunner(ctx, opts, o.Filter, o.Logger, o.InfraID)
 	if err != nil {
 		return err
 	}
@@ -118,9 +136,12 @@ func (o *ClusterUninstaller) Run() error {
 		res := <-returnChannel
 		o.Logger.Debugf("goroutine %v complete", res)
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// we need to untag the custom network if it was provided by the user
-	err = untagRunner(opts, o.InfraID, o.Logger)
+	err = untagRunner(ctx, opts, o.InfraID, o.Logger, recorder)
 	if err != nil {
 		return err
 	}
@@ -128,18 +149,18 @@ func (o *ClusterUninstaller) Run() error {
 	return nil
 }
 
-func deleteRunner(deleteFuncName string, dFunction deleteFunc, opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, channel chan string) {
+func deleteRunner(ctx context.Context, deleteFuncName string, dFunction deleteFunc, opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger, channel chan string) {
 	backoffSettings := wait.Backoff{
 		Duration: time.Second * 15,
 		Factor:   1.3,
 		Steps:    25,
 	}
 
-	err := wait.ExponentialBackoff(backoffSettings, func() (bool, error) {
-		return dFunction(opts, filter, logger)
+	err := exponentialBackoffWithContext(ctx, backoffSettings, func() (bool, error) {
+		return dFunction(opts, filter, skip, logger)
 	})
 
-	if err != nil {
+	if err != nil && err != ctx.Err() {
 		logger.Fatalf("Unrecoverable error/timed out: %v", err)
 	}
 
@@ -147,6 +168,26 @@ func deleteRunner(deleteFuncName string, dFunction deleteFunc, opts *clientconfi
 	channel <- deleteFuncName
 }
 
+// exponentialBackoffWithContext is wait.ExponentialBackoff, except that it
+// returns ctx.Err() as soon as ctx is done instead of sleeping until the next
+// attempt.
+func exponentialBackoffWithContext(ctx context.Context, backoff wait.Backoff, condition wait.ConditionFunc) error {
+	for backoff.Steps > 0 {
+		if ok, err := condition(); err != nil || ok {
+			return err
+		}
+		if backoff.Steps == 1 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff.Step()):
+		}
+	}
+	return wait.ErrWaitTimeout
+}
+
 // filterObjects will do client-side filtering given an appropriately filled out
 // list of ObjectWithTags.
 func filterObjects(osObjects []ObjectWithTags, filters Filter) []ObjectWithTags {
@@ -192,7 +233,7 @@ func filterTags(filters Filter) []string {
 	return tags
 }
 
-func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteServers(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack servers")
 	defer logger.Debugf("Exiting deleting openstack servers")
 
@@ -215,15 +256,22 @@ func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 	}
 
 	serverObjects := []ObjectWithTags{}
+	names := map[string]string{}
 	for _, server := range allServers {
 		serverObjects = append(
 			serverObjects, ObjectWithTags{
 				ID:   server.ID,
 				Tags: server.Metadata})
+		names[server.ID] = server.Name
 	}
 
 	filteredServers := filterObjects(serverObjects, filter)
+	excluded := 0
 	for _, server := range filteredServers {
+		if skip(providers.Resource{Type: "server", Name: server.ID + " (" + names[server.ID] + ")"}, server.Tags) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting Server %q", server.ID)
 		err = servers.Delete(conn, server.ID).ExtractErr()
 		if err != nil {
@@ -236,10 +284,10 @@ func deleteServers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			logger.Debugf("Cannot find server %q. It's probably already been deleted.", server.ID)
 		}
 	}
-	return len(filteredServers) == 0, nil
+	return len(filteredServers) == excluded, nil
 }
 
-func deleteServerGroups(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteServerGroups(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack server groups")
 	defer logger.Debugf("Exiting deleting openstack server groups")
 
@@ -278,7 +326,12 @@ func deleteServerGroups(opts *clientconfig.ClientOpts, filter Filter, logger log
 		}
 	}
 
+	excluded := 0
 	for _, serverGroup := range filteredGroups {
+		if skip(providers.Resource{Type: "servergroup", Name: serverGroup.ID + " (" + serverGroup.Name + ")"}, nil) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting Server Group %q", serverGroup.ID)
 		if err = servergroups.Delete(conn, serverGroup.ID).ExtractErr(); err != nil {
 			// Ignore the error if the server cannot be found and
@@ -292,10 +345,10 @@ func deleteServerGroups(opts *clientconfig.ClientOpts, filter Filter, logger log
 			logger.Debugf("Cannot find server group %q. It's probably already been deleted.", serverGroup.ID)
 		}
 	}
-	return len(filteredGroups) == 0, nil
+	return len(filteredGroups) == excluded, nil
 }
 
-func deletePorts(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deletePorts(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack ports")
 	defer logger.Debugf("Exiting deleting openstack ports")
 
@@ -320,7 +373,12 @@ func deletePorts(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fie
 		logger.Error(err)
 		return false, nil
 	}
+	excluded := 0
 	for _, port := range allPorts {
+		if skip(providers.Resource{Type: "port", Name: port.ID}, providers.TagsFromList(port.Tags)) {
+			excluded++
+			continue
+		}
 		listOpts := floatingips.ListOpts{
 			PortID: port.ID,
 		}
@@ -358,10 +416,10 @@ func deletePorts(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fie
 			return false, nil
 		}
 	}
-	return len(allPorts) == 0, nil
+	return len(allPorts) == excluded, nil
 }
 
-func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack security-groups")
 	defer logger.Debugf("Exiting deleting openstack security-groups")
 
@@ -386,7 +444,12 @@ func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger l
 		logger.Error(err)
 		return false, nil
 	}
+	excluded := 0
 	for _, group := range allGroups {
+		if skip(providers.Resource{Type: "securitygroup", Name: group.ID + " (" + group.Name + ")"}, providers.TagsFromList(group.Tags)) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting Security Group: %q", group.ID)
 		err = sg.Delete(conn, group.ID).ExtractErr()
 		if err != nil {
@@ -400,10 +463,10 @@ func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger l
 			logger.Debugf("Cannot find security group %q. It's probably already been deleted.", group.ID)
 		}
 	}
-	return len(allGroups) == 0, nil
+	return len(allGroups) == excluded, nil
 }
 
-func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack routers")
 	defer logger.Debugf("Exiting deleting openstack routers")
 
@@ -428,7 +491,12 @@ func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 		logger.Error(err)
 		return false, nil
 	}
+	excluded := 0
 	for _, router := range allRouters {
+		if skip(providers.Resource{Type: "router", Name: router.ID + " (" + router.Name + ")"}, providers.TagsFromList(router.Tags)) {
+			excluded++
+			continue
+		}
 		// If a user provisioned floating ip was used, it needs to be dissociated
 		// Any floating Ip's associated with routers that are going to be deleted will be dissociated
 		fipOpts := floatingips.ListOpts{
@@ -487,7 +555,7 @@ func deleteRouters(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			logger.Debugf("Cannot find router %q. It's probably already been deleted.", router.ID)
 		}
 	}
-	return len(allRouters) == 0, nil
+	return len(allRouters) == excluded, nil
 }
 
 func deleteCustomRouterInterfaces(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, infraID string) (bool, error) {
@@ -655,7 +723,7 @@ func isClusterSubnet(subnets []subnets.Subnet, subnetID string) bool {
 	return false
 }
 
-func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack subnets")
 	defer logger.Debugf("Exiting deleting openstack subnets")
 
@@ -680,7 +748,12 @@ func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 		logger.Error(err)
 		return false, nil
 	}
+	excluded := 0
 	for _, subnet := range allSubnets {
+		if skip(providers.Resource{Type: "subnet", Name: subnet.ID + " (" + subnet.Name + ")"}, providers.TagsFromList(subnet.Tags)) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting Subnet: %q", subnet.ID)
 		err = subnets.Delete(conn, subnet.ID).ExtractErr()
 		if err != nil {
@@ -694,10 +767,10 @@ func deleteSubnets(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 			logger.Debugf("Cannot find subnet %q. It's probably already been deleted.", subnet.ID)
 		}
 	}
-	return len(allSubnets) == 0, nil
+	return len(allSubnets) == excluded, nil
 }
 
-func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack networks")
 	defer logger.Debugf("Exiting deleting openstack networks")
 
@@ -722,7 +795,12 @@ func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.
 		logger.Error(err)
 		return false, nil
 	}
+	excluded := 0
 	for _, network := range allNetworks {
+		if skip(providers.Resource{Type: "network", Name: network.ID + " (" + network.Name + ")"}, providers.TagsFromList(network.Tags)) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting network: %q", network.ID)
 		err = networks.Delete(conn, network.ID).ExtractErr()
 		if err != nil {
@@ -736,10 +814,10 @@ func deleteNetworks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.
 			logger.Debugf("Cannot find network %q. It's probably already been deleted.", network.ID)
 		}
 	}
-	return len(allNetworks) == 0, nil
+	return len(allNetworks) == excluded, nil
 }
 
-func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack containers")
 	defer logger.Debugf("Exiting deleting openstack containers")
 
@@ -795,6 +873,9 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 			logger.Error(err)
 			return false, nil
 		}
+		if skip(providers.Resource{Type: "container", Name: container}, metadata) {
+			continue
+		}
 		for key, val := range filter {
 			// Swift mangles the case so openshiftClusterID becomes
 			// Openshiftclusterid in the X-Container-Meta- HEAD output
@@ -853,7 +934,7 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 	return true, nil
 }
 
-func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack trunks")
 	defer logger.Debugf("Exiting deleting openstack trunks")
 
@@ -883,7 +964,12 @@ func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 		logger.Error(err)
 		return false, nil
 	}
+	excluded := 0
 	for _, trunk := range allTrunks {
+		if skip(providers.Resource{Type: "trunk", Name: trunk.ID}, providers.TagsFromList(trunk.Tags)) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting Trunk %q", trunk.ID)
 		err = trunks.Delete(conn, trunk.ID).ExtractErr()
 		if err != nil {
@@ -897,10 +983,10 @@ func deleteTrunks(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 			logger.Debugf("Cannot find trunk %q. It's probably already been deleted.", trunk.ID)
 		}
 	}
-	return len(allTrunks) == 0, nil
+	return len(allTrunks) == excluded, nil
 }
 
-func deleteLoadBalancers(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteLoadBalancers(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack load balancers")
 	defer logger.Debugf("Exiting deleting openstack load balancers")
 
@@ -975,7 +1061,12 @@ func deleteLoadBalancers(opts *clientconfig.ClientOpts, filter Filter, logger lo
 	deleteOpts := loadbalancers.DeleteOpts{
 		Cascade: true,
 	}
+	excluded := 0
 	for _, loadbalancer := range allLoadBalancers {
+		if skip(providers.Resource{Type: "loadbalancer", Name: loadbalancer.ID + " (" + loadbalancer.Name + ")"}, providers.TagsFromList(loadbalancer.Tags)) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting LoadBalancer %q", loadbalancer.ID)
 		err = loadbalancers.Delete(conn, loadbalancer.ID, deleteOpts).ExtractErr()
 		if err != nil {
@@ -990,10 +1081,10 @@ func deleteLoadBalancers(opts *clientconfig.ClientOpts, filter Filter, logger lo
 		}
 	}
 
-	return len(allLoadBalancers) == 0, nil
+	return len(allLoadBalancers) == excluded, nil
 }
 
-func deleteSubnetPools(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteSubnetPools(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack subnet-pools")
 	defer logger.Debugf("Exiting deleting openstack subnet-pools")
 
@@ -1018,7 +1109,12 @@ func deleteSubnetPools(opts *clientconfig.ClientOpts, filter Filter, logger logr
 		logger.Error(err)
 		return false, nil
 	}
+	excluded := 0
 	for _, subnetPool := range allSubnetPools {
+		if skip(providers.Resource{Type: "subnetpool", Name: subnetPool.ID + " (" + subnetPool.Name + ")"}, providers.TagsFromList(subnetPool.Tags)) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting Subnet Pool %q", subnetPool.ID)
 		err = subnetpools.Delete(conn, subnetPool.ID).ExtractErr()
 		if err != nil {
@@ -1031,10 +1127,10 @@ func deleteSubnetPools(opts *clientconfig.ClientOpts, filter Filter, logger logr
 			logger.Debugf("Cannot find subnet pool %q. It's probably already been deleted.", subnetPool.ID)
 		}
 	}
-	return len(allSubnetPools) == 0, nil
+	return len(allSubnetPools) == excluded, nil
 }
 
-func deleteVolumes(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteVolumes(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting OpenStack volumes")
 	defer logger.Debugf("Exiting deleting OpenStack volumes")
 
@@ -1069,9 +1165,13 @@ func deleteVolumes(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 
 	volumeIDs := []string{}
 	for _, volume := range allVolumes {
-		if strings.HasPrefix(volume.Name, clusterID) {
-			volumeIDs = append(volumeIDs, volume.ID)
+		if !strings.HasPrefix(volume.Name, clusterID) {
+			continue
 		}
+		if skip(providers.Resource{Type: "volume", Name: volume.ID + " (" + volume.Name + ")"}, volume.Metadata) {
+			continue
+		}
+		volumeIDs = append(volumeIDs, volume.ID)
 	}
 
 	deleteOpts := volumes.DeleteOpts{
@@ -1095,7 +1195,7 @@ func deleteVolumes(opts *clientconfig.ClientOpts, filter Filter, logger logrus.F
 	return true, nil
 }
 
-func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack floating ips")
 	defer logger.Debugf("Exiting deleting openstack floating ips")
 
@@ -1120,7 +1220,12 @@ func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, logger logr
 		logger.Error(err)
 		return false, nil
 	}
+	excluded := 0
 	for _, floatingIP := range allFloatingIPs {
+		if skip(providers.Resource{Type: "floatingip", Name: floatingIP.ID + " (" + floatingIP.FloatingIP + ")"}, providers.TagsFromList(floatingIP.Tags)) {
+			excluded++
+			continue
+		}
 		logger.Debugf("Deleting Floating IP %q", floatingIP.ID)
 		err = floatingips.Delete(conn, floatingIP.ID).ExtractErr()
 		if err != nil {
@@ -1133,10 +1238,10 @@ func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, logger logr
 			logger.Debugf("Cannot find floating ip %q. It's probably already been deleted.", floatingIP.ID)
 		}
 	}
-	return len(allFloatingIPs) == 0, nil
+	return len(allFloatingIPs) == excluded, nil
 }
 
-func deleteImages(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+func deleteImages(opts *clientconfig.ClientOpts, filter Filter, skip skipFunc, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack base image")
 	defer logger.Debugf("Exiting deleting openstack base image")
 
@@ -1163,6 +1268,9 @@ func deleteImages(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 	}
 
 	for _, image := range allImages {
+		if skip(providers.Resource{Type: "image", Name: image.ID + " (" + image.Name + ")"}, providers.TagsFromList(image.Tags)) {
+			continue
+		}
 		logger.Debugf("Deleting image: %+v", image.ID)
 		err := images.Delete(conn, image.ID).ExtractErr()
 		if err != nil {
@@ -1174,17 +1282,17 @@ func deleteImages(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fi
 	return true, nil
 }
 
-func untagRunner(opts *clientconfig.ClientOpts, infraID string, logger logrus.FieldLogger) error {
+func untagRunner(ctx context.Context, opts *clientconfig.ClientOpts, infraID string, logger logrus.FieldLogger, recorder *providers.Recorder) error {
 	backoffSettings := wait.Backoff{
 		Duration: time.Second * 10,
 		Steps:    25,
 	}
 
-	err := wait.ExponentialBackoff(backoffSettings, func() (bool, error) {
-		return untagPrimaryNetwork(opts, infraID, logger)
+	err := exponentialBackoffWithContext(ctx, backoffSettings, func() (bool, error) {
+		return untagPrimaryNetwork(opts, infraID, logger, recorder)
 	})
 	if err != nil {
-		if err == wait.ErrWaitTimeout {
+		if err == wait.ErrWaitTimeout || err == ctx.Err() {
 			return err
 		}
 		return errors.Errorf("Unrecoverable error: %v", err)
@@ -1193,18 +1301,18 @@ func untagRunner(opts *clientconfig.ClientOpts, infraID string, logger logrus.Fi
 	return nil
 }
 
-func cleanRouterRunner(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, infraID string) error {
+func cleanRouterRunner(ctx context.Context, opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, infraID string) error {
 	backoffSettings := wait.Backoff{
 		Duration: time.Second * 15,
 		Factor:   1.3,
 		Steps:    25,
 	}
 
-	err := wait.ExponentialBackoff(backoffSettings, func() (bool, error) {
+	err := exponentialBackoffWithContext(ctx, backoffSettings, func() (bool, error) {
 		return deleteCustomRouterInterfaces(opts, filter, logger, infraID)
 	})
 	if err != nil {
-		if err == wait.ErrWaitTimeout {
+		if err == wait.ErrWaitTimeout || err == ctx.Err() {
 			return err
 		}
 		return errors.Errorf("Unrecoverable error: %v", err)
@@ -1214,7 +1322,7 @@ func cleanRouterRunner(opts *clientconfig.ClientOpts, filter Filter, logger logr
 }
 
 // untagNetwork removes the tag from the primary cluster network based on unfra id
-func untagPrimaryNetwork(opts *clientconfig.ClientOpts, infraID string, logger logrus.FieldLogger) (bool, error) {
+func untagPrimaryNetwork(opts *clientconfig.ClientOpts, infraID string, logger logrus.FieldLogger, recorder *providers.Recorder) (bool, error) {
 	networkTag := infraID + "-primaryClusterNetwork"
 
 	logger.Debugf("Removing tag %v from openstack networks", networkTag)
@@ -1255,6 +1363,7 @@ func untagPrimaryNetwork(opts *clientconfig.ClientOpts, infraID string, logger l
 	if err != nil {
 		return false, nil
 	}
+	recorder.SkippedShared(providers.Resource{Type: "network", Name: allNetworks[0].ID + " (" + allNetworks[0].Name + ")"})
 
 	return true, nil
 }
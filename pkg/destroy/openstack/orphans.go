@@ -0,0 +1,154 @@
+package openstack
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	sg "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+	openstackdefaults "github.com/openshift/installer/pkg/types/openstack/defaults"
+)
+
+const clusterIDTagPrefix = "openshiftClusterID="
+
+// FindOrphans lists the clusters with servers, networks, ports or security
+// groups tagged openshiftClusterID=<infraID> in the scope's cloud. The
+// cluster age is taken from the creation time of its servers, networks and
+// security groups.
+func FindOrphans(logger logrus.FieldLogger, scope *providers.Scope) ([]providers.Orphan, error) {
+	if scope.Cloud == "" {
+		return nil, errors.New("a cloud is required to search for OpenStack clusters")
+	}
+	opts := openstackdefaults.DefaultClientOpts(scope.Cloud)
+
+	orphans := providers.OrphanSet{}
+	for name, find := range map[string]func(*clientconfig.ClientOpts, providers.OrphanSet) error{
+		"servers":        findServerOrphans,
+		"networks":       findNetworkOrphans,
+		"ports":          findPortOrphans,
+		"securityGroups": findSecurityGroupOrphans,
+	} {
+		logger.Debugf("search for tagged %s", name)
+		if err := find(opts, orphans); err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s", name)
+		}
+	}
+
+	result := orphans.List()
+	for i := range result {
+		result[i].Metadata = &types.ClusterMetadata{
+			InfraID: result[i].InfraID,
+			ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+				OpenStack: &openstacktypes.Metadata{
+					Cloud:      scope.Cloud,
+					Identifier: map[string]string{"openshiftClusterID": result[i].InfraID},
+				},
+			},
+		}
+	}
+	return result, nil
+}
+
+// taggedInfraID returns the infra ID from an openshiftClusterID=<infraID>
+// tag, or "" if there is none.
+func taggedInfraID(tags []string) string {
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, clusterIDTagPrefix) {
+			return strings.TrimPrefix(tag, clusterIDTagPrefix)
+		}
+	}
+	return ""
+}
+
+func findServerOrphans(opts *clientconfig.ClientOpts, orphans providers.OrphanSet) error {
+	conn, err := clientconfig.NewServiceClient("compute", opts)
+	if err != nil {
+		return err
+	}
+	allPages, err := servers.List(conn, servers.ListOpts{}).AllPages()
+	if err != nil {
+		return err
+	}
+	allServers, err := servers.ExtractServers(allPages)
+	if err != nil {
+		return err
+	}
+	for _, server := range allServers {
+		if infraID := server.Metadata["openshiftClusterID"]; infraID != "" {
+			orphans.Add(infraID, server.Created)
+		}
+	}
+	return nil
+}
+
+func findNetworkOrphans(opts *clientconfig.ClientOpts, orphans providers.OrphanSet) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+	allPages, err := networks.List(conn, networks.ListOpts{}).AllPages()
+	if err != nil {
+		return err
+	}
+	allNetworks, err := networks.ExtractNetworks(allPages)
+	if err != nil {
+		return err
+	}
+	for _, network := range allNetworks {
+		if infraID := taggedInfraID(network.Tags); infraID != "" {
+			orphans.Add(infraID, network.CreatedAt)
+		}
+	}
+	return nil
+}
+
+func findPortOrphans(opts *clientconfig.ClientOpts, orphans providers.OrphanSet) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+	allPages, err := ports.List(conn, ports.ListOpts{}).AllPages()
+	if err != nil {
+		return err
+	}
+	allPorts, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return err
+	}
+	for _, port := range allPorts {
+		if infraID := taggedInfraID(port.Tags); infraID != "" {
+			orphans.Add(infraID, time.Time{})
+		}
+	}
+	return nil
+}
+
+func findSecurityGroupOrphans(opts *clientconfig.ClientOpts, orphans providers.OrphanSet) error {
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		return err
+	}
+	allPages, err := sg.List(conn, sg.ListOpts{}).AllPages()
+	if err != nil {
+		return err
+	}
+	allGroups, err := sg.ExtractGroups(allPages)
+	if err != nil {
+		return err
+	}
+	for _, group := range allGroups {
+		if infraID := taggedInfraID(group.Tags); infraID != "" {
+			orphans.Add(infraID, group.CreatedAt)
+		}
+	}
+	return nil
+}
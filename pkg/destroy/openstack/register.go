@@ -6,4 +6,5 @@ import (
 
 func init() {
 	providers.Registry["openstack"] = New
+	providers.OrphanFinders["openstack"] = FindOrphans
 }
@@ -0,0 +1,149 @@
+package destroy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// FindOrphans lists the installer-created clusters in scope using the
+// orphan finder registered for platform.
+func FindOrphans(logger logrus.FieldLogger, platform string, scope *providers.Scope) ([]providers.Orphan, error) {
+	find, ok := providers.OrphanFinders[platform]
+	if !ok {
+		return nil, errors.Errorf("searching for orphaned clusters is not supported on %q", platform)
+	}
+	return find(logger, scope)
+}
+
+// OrphanOptions selects the orphaned clusters to destroy. A cluster is
+// selected when it is older than OlderThan or, if Allowed is not empty, when
+// its infra ID is not in Allowed. Clusters of unknown age are never
+// considered older than OlderThan.
+type OrphanOptions struct {
+	// OlderThan is the maximum age of a cluster. Zero disables the check.
+	OlderThan time.Duration
+
+	// Allowed lists the infra IDs of the clusters known to be in use.
+	Allowed []string
+}
+
+// OrphanReportEntry describes an orphaned cluster and whether it is
+// selected for destruction.
+type OrphanReportEntry struct {
+	InfraID   string     `json:"infraID"`
+	Created   *time.Time `json:"created,omitempty"`
+	Age       string     `json:"age,omitempty"`
+	Resources int        `json:"resources"`
+	Destroy   bool       `json:"destroy"`
+	Reason    string     `json:"reason,omitempty"`
+
+	orphan providers.Orphan
+}
+
+// Report evaluates the orphans against the options at time now.
+func (o *OrphanOptions) Report(orphans []providers.Orphan, now time.Time) []OrphanReportEntry {
+	allowed := sets.NewString(o.Allowed...)
+	report := make([]OrphanReportEntry, 0, len(orphans))
+	for _, orphan := range orphans {
+		entry := OrphanReportEntry{
+			InfraID:   orphan.InfraID,
+			Resources: orphan.Resources,
+			orphan:    orphan,
+		}
+		var age time.Duration
+		if !orphan.Created.IsZero() {
+			created := orphan.Created
+			age = now.Sub(created).Truncate(time.Minute)
+			entry.Created = &created
+			entry.Age = age.String()
+		}
+		switch {
+		case o.OlderThan > 0 && age > o.OlderThan:
+			entry.Destroy = true
+			entry.Reason = fmt.Sprintf("older than %s", o.OlderThan)
+		case allowed.Len() > 0 && !allowed.Has(orphan.InfraID):
+			entry.Destroy = true
+			entry.Reason = "not in allow-list"
+		}
+		report = append(report, entry)
+	}
+	return report
+}
+
+// WriteOrphanReport writes the report to w in the given inventory format.
+func WriteOrphanReport(w io.Writer, report []OrphanReportEntry, format string) error {
+	switch format {
+	case InventoryFormatJSON:
+		if report == nil {
+			report = []OrphanReportEntry{}
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal orphaned clusters")
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case InventoryFormatText:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "INFRA ID\tAGE\tRESOURCES\tACTION\tREASON")
+		for _, entry := range report {
+			age := entry.Age
+			if age == "" {
+				age = "unknown"
+			}
+			action := "keep"
+			if entry.Destroy {
+				action = "destroy"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", entry.InfraID, age, entry.Resources, action, entry.Reason)
+		}
+		return tw.Flush()
+	default:
+		return errors.Errorf("unsupported output format %q", format)
+	}
+}
+
+// DestroyOrphans runs the platform destroyer of every cluster selected in
+// the report. A failure to destroy one cluster does not stop the others, but
+// no further cluster is started once ctx is done.
+func DestroyOrphans(ctx context.Context, logger logrus.FieldLogger, report []OrphanReportEntry, options providers.RunOptions) error {
+	var errs []error
+	for _, entry := range report {
+		if !entry.Destroy {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to destroy %s", entry.InfraID))
+			continue
+		}
+		clusterLogger := logger.WithField("infraID", entry.InfraID)
+		clusterLogger.Infof("Destroying orphaned cluster (%s)", entry.Reason)
+		destroyer, err := NewFromMetadata(clusterLogger, entry.orphan.Metadata)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to destroy %s", entry.InfraID))
+			continue
+		}
+		report, err := destroyer.Run(ctx, options)
+		if report != nil {
+			LogReport(clusterLogger, report)
+			if err == nil && report.Leftovers() {
+				err = errors.Errorf("%d resources were left behind", len(report.Failed))
+			}
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to destroy %s", entry.InfraID))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
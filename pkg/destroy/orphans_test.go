@@ -0,0 +1,94 @@
+package destroy
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+func TestOrphanReport(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	orphans := []providers.Orphan{
+		{InfraID: "old-abcde", Created: now.Add(-8 * time.Hour), Resources: 40},
+		{InfraID: "new-fghij", Created: now.Add(-time.Hour), Resources: 12},
+		{InfraID: "unknown-klmno", Resources: 3},
+	}
+	cases := []struct {
+		name     string
+		options  OrphanOptions
+		expected map[string]string
+	}{{
+		name:     "no selection",
+		expected: map[string]string{},
+	}, {
+		name:    "older than",
+		options: OrphanOptions{OlderThan: 6 * time.Hour},
+		expected: map[string]string{
+			"old-abcde": "older than 6h0m0s",
+		},
+	}, {
+		name:    "allow-list",
+		options: OrphanOptions{Allowed: []string{"old-abcde"}},
+		expected: map[string]string{
+			"new-fghij":     "not in allow-list",
+			"unknown-klmno": "not in allow-list",
+		},
+	}, {
+		name:    "older than or not allowed",
+		options: OrphanOptions{OlderThan: 6 * time.Hour, Allowed: []string{"old-abcde", "new-fghij"}},
+		expected: map[string]string{
+			"old-abcde":     "older than 6h0m0s",
+			"unknown-klmno": "not in allow-list",
+		},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			selected := map[string]string{}
+			for _, entry := range tc.options.Report(orphans, now) {
+				if entry.Destroy {
+					selected[entry.InfraID] = entry.Reason
+				}
+			}
+			assert.Equal(t, tc.expected, selected)
+		})
+	}
+}
+
+func TestWriteOrphanReport(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	options := OrphanOptions{OlderThan: 6 * time.Hour}
+	report := options.Report([]providers.Orphan{
+		{InfraID: "old-abcde", Created: now.Add(-8 * time.Hour), Resources: 40},
+		{InfraID: "unknown-klmno", Resources: 3},
+	}, now)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteOrphanReport(&buf, report, InventoryFormatText))
+	assert.Equal(t, `INFRA ID       AGE      RESOURCES  ACTION   REASON
+old-abcde      8h0m0s   40         destroy  older than 6h0m0s
+unknown-klmno  unknown  3          keep     
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteOrphanReport(&buf, report, InventoryFormatJSON))
+	assert.Equal(t, `[
+  {
+    "infraID": "old-abcde",
+    "created": "2020-06-01T04:00:00Z",
+    "age": "8h0m0s",
+    "resources": 40,
+    "destroy": true,
+    "reason": "older than 6h0m0s"
+  },
+  {
+    "infraID": "unknown-klmno",
+    "resources": 3,
+    "destroy": false
+  }
+]
+`, buf.String())
+}
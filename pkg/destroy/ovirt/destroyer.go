@@ -1,6 +1,7 @@
 package ovirt
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -20,7 +21,17 @@ type ClusterUninstaller struct {
 }
 
 // Run is the entrypoint to start the uninstall process.
-func (uninstaller *ClusterUninstaller) Run() error {
+func (uninstaller *ClusterUninstaller) Run(ctx context.Context, options providers.RunOptions) (*providers.Report, error) {
+	return providers.RunWithInventory(uninstaller.Logger, uninstaller.Inventory, func(recorder *providers.Recorder) error {
+		return uninstaller.destroy(ctx, func(resource providers.Resource) bool {
+			return options.Exclusions.Skip(uninstaller.Logger, recorder, resource, nil)
+		})
+	})
+}
+
+// destroy removes the cluster resources that skip does not select, stopping
+// between tags when ctx is done. A tag is kept while excluded VMs carry it.
+func (uninstaller *ClusterUninstaller) destroy(ctx context.Context, skip func(providers.Resource) bool) error {
 	con, err := ovirt.NewConnection()
 	if err != nil {
 		return fmt.Errorf("failed to initialize connection to ovirt-engine's %s", err)
@@ -33,35 +44,46 @@ func (uninstaller *ClusterUninstaller) Run() error {
 	tags := [2]string{tagVMs, tagVMbootstrap}
 
 	for _, tag := range tags {
-		if err := uninstaller.removeVMs(con, tag); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		kept, err := uninstaller.removeVMs(con, tag, skip)
+		if err != nil {
 			uninstaller.Logger.Errorf("failed to remove VMs: %s", err)
 		}
+		if kept || skip(providers.Resource{Type: "tag", Name: tag}) {
+			continue
+		}
 		if err := uninstaller.removeTag(con, tag); err != nil {
 			uninstaller.Logger.Errorf("failed to remove tag: %s", err)
 		}
 	}
-	if err := uninstaller.removeTemplate(con); err != nil {
+	if err := uninstaller.removeTemplate(con, skip); err != nil {
 		uninstaller.Logger.Errorf("Failed to remove template: %s", err)
 	}
 
 	return nil
 }
 
-func (uninstaller *ClusterUninstaller) removeVMs(con *ovirtsdk.Connection, tag string) error {
+func (uninstaller *ClusterUninstaller) removeVMs(con *ovirtsdk.Connection, tag string, skip func(providers.Resource) bool) (kept bool, err error) {
 	// - find all vms by tag name=infraID
 	vmsService := con.SystemService().VmsService()
 	searchTerm := fmt.Sprintf("tag=%s", tag)
 	uninstaller.Logger.Debugf("Searching VMs by %s", searchTerm)
 	vmsResponse, err := vmsService.List().Search(searchTerm).Send()
 	if err != nil {
-		return err
+		return false, err
 	}
 	// - stop + delete VMS
 	vms := vmsResponse.MustVms().Slice()
 	uninstaller.Logger.Debugf("Found %d VMs", len(vms))
 	wg := sync.WaitGroup{}
-	wg.Add(len(vms))
 	for _, vm := range vms {
+		if skip(providers.Resource{Type: "vm", Name: vm.MustName()}) {
+			kept = true
+			continue
+		}
+		wg.Add(1)
 		go func(vm *ovirtsdk.Vm) {
 			uninstaller.stopVM(vmsService, vm)
 			uninstaller.removeVM(vmsService, vm)
@@ -69,7 +91,7 @@ func (uninstaller *ClusterUninstaller) removeVMs(con *ovirtsdk.Connection, tag s
 		}(vm)
 	}
 	wg.Wait()
-	return nil
+	return kept, nil
 }
 
 func (uninstaller *ClusterUninstaller) removeTag(con *ovirtsdk.Connection, tag string) error {
@@ -121,7 +143,7 @@ func (uninstaller *ClusterUninstaller) removeVM(vmsService *ovirtsdk.VmsService,
 	}
 }
 
-func (uninstaller *ClusterUninstaller) removeTemplate(con *ovirtsdk.Connection) error {
+func (uninstaller *ClusterUninstaller) removeTemplate(con *ovirtsdk.Connection, skip func(providers.Resource) bool) error {
 	if uninstaller.Metadata.Ovirt.RemoveTemplate {
 		search, err := con.SystemService().TemplatesService().
 			List().Search(fmt.Sprintf("name=%s-rhcos", uninstaller.Metadata.InfraID)).Send()
@@ -132,6 +154,9 @@ func (uninstaller *ClusterUninstaller) removeTemplate(con *ovirtsdk.Connection)
 			// the results can potentially return a list of template
 			// because the search uses wildcards
 			for _, tmp := range result.Slice() {
+				if skip(providers.Resource{Type: "template", Name: tmp.MustName()}) {
+					continue
+				}
 				uninstaller.Logger.Infof("Removing Template %s", tmp.MustName())
 				service := con.SystemService().TemplatesService().TemplateService(tmp.MustId())
 				_, err := service.Remove().Send()
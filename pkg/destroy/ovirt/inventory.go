@@ -0,0 +1,58 @@
+package ovirt
+
+import (
+	"fmt"
+
+	"github.com/openshift/installer/pkg/asset/installconfig/ovirt"
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+func (uninstaller *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	con, err := ovirt.NewConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize connection to ovirt-engine's %s", err)
+	}
+	defer con.Close()
+
+	tagVMs := uninstaller.Metadata.InfraID
+	tagVMbootstrap := uninstaller.Metadata.InfraID + "-bootstrap"
+	tags := [2]string{tagVMs, tagVMbootstrap}
+
+	var resources []providers.Resource
+	for _, tag := range tags {
+		vmsResponse, err := con.SystemService().VmsService().List().Search(fmt.Sprintf("tag=%s", tag)).Send()
+		if err != nil {
+			return nil, err
+		}
+		for _, vm := range vmsResponse.MustVms().Slice() {
+			resources = append(resources, providers.Resource{Type: "vm", Name: vm.MustName()})
+		}
+	}
+
+	tagsServiceListResponse, err := con.SystemService().TagsService().List().Send()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tagsServiceListResponse.MustTags().Slice() {
+		if name := t.MustName(); name == tagVMs || name == tagVMbootstrap {
+			resources = append(resources, providers.Resource{Type: "tag", Name: name})
+		}
+	}
+
+	if uninstaller.Metadata.Ovirt.RemoveTemplate {
+		search, err := con.SystemService().TemplatesService().
+			List().Search(fmt.Sprintf("name=%s-rhcos", uninstaller.Metadata.InfraID)).Send()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find a template with name %s", uninstaller.Metadata.InfraID)
+		}
+		if result, ok := search.Templates(); ok {
+			for _, tmp := range result.Slice() {
+				resources = append(resources, providers.Resource{Type: "template", Name: tmp.MustName()})
+			}
+		}
+	}
+
+	providers.SortResources(resources)
+	return resources, nil
+}
@@ -0,0 +1,154 @@
+package providers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// tagPrefix introduces an exclusion that matches a tag or label.
+const tagPrefix = "tag:"
+
+// Exclusion selects resources that a Destroyer must leave in place, even
+// when they carry the cluster's tags or name prefix.
+type Exclusion struct {
+	// Pattern matches the name, ID or ARN of a resource. '*' matches any
+	// sequence of characters and '?' any single character.
+	Pattern string
+
+	// TagKey matches resources with this tag or label, used when Pattern
+	// is empty.
+	TagKey string
+
+	// TagValue restricts TagKey to resources whose tag has this value.
+	// An empty TagValue matches any value.
+	TagValue string
+
+	pattern *regexp.Regexp
+}
+
+// ParseExclusion parses an exclusion of the form tag:<key>[=<value>], or a
+// name, ID or ARN pattern.
+func ParseExclusion(value string) (Exclusion, error) {
+	if strings.HasPrefix(value, tagPrefix) {
+		parts := strings.SplitN(strings.TrimPrefix(value, tagPrefix), "=", 2)
+		if parts[0] == "" {
+			return Exclusion{}, errors.Errorf("invalid exclusion %q: the tag key is empty", value)
+		}
+		exclusion := Exclusion{TagKey: parts[0]}
+		if len(parts) == 2 {
+			exclusion.TagValue = parts[1]
+		}
+		return exclusion, nil
+	}
+	if value == "" {
+		return Exclusion{}, errors.New("invalid exclusion: the pattern is empty")
+	}
+	return Exclusion{Pattern: value, pattern: compilePattern(value)}, nil
+}
+
+// ParseExclusions parses every value with ParseExclusion.
+func ParseExclusions(values []string) (Exclusions, error) {
+	exclusions := make(Exclusions, 0, len(values))
+	for _, value := range values {
+		exclusion, err := ParseExclusion(value)
+		if err != nil {
+			return nil, err
+		}
+		exclusions = append(exclusions, exclusion)
+	}
+	return exclusions, nil
+}
+
+// compilePattern converts a pattern with '*' and '?' wildcards to an
+// anchored regular expression.
+func compilePattern(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// String returns the exclusion in the form accepted by ParseExclusion.
+func (e Exclusion) String() string {
+	if e.Pattern != "" {
+		return e.Pattern
+	}
+	if e.TagValue != "" {
+		return tagPrefix + e.TagKey + "=" + e.TagValue
+	}
+	return tagPrefix + e.TagKey
+}
+
+// Matches reports whether the exclusion selects resource. tags holds the
+// tags or labels of the resource and may be nil when they are unknown.
+func (e Exclusion) Matches(resource Resource, tags map[string]string) bool {
+	if e.Pattern != "" {
+		pattern := e.pattern
+		if pattern == nil {
+			pattern = compilePattern(e.Pattern)
+		}
+		for _, name := range resourceNames(resource.Name) {
+			if pattern.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}
+	value, ok := tags[e.TagKey]
+	return ok && (e.TagValue == "" || e.TagValue == value)
+}
+
+// resourceNames returns the strings a pattern is matched against: the name
+// itself, its last ARN or path component, which is the ID of the resource on
+// most platforms, and, for names of the form "<id> (<name>)", each part.
+func resourceNames(name string) []string {
+	names := []string{name, name[strings.LastIndexAny(name, "/:")+1:]}
+	if i := strings.Index(name, " ("); i > 0 && strings.HasSuffix(name, ")") {
+		names = append(names, name[:i], name[i+2:len(name)-1])
+	}
+	return names
+}
+
+// Exclusions is a list of resources that a Destroyer must leave in place.
+type Exclusions []Exclusion
+
+// Excludes reports whether any exclusion selects resource. tags holds the
+// tags or labels of the resource and may be nil when they are unknown.
+func (e Exclusions) Excludes(resource Resource, tags map[string]string) bool {
+	for _, exclusion := range e {
+		if exclusion.Matches(resource, tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// Skip reports whether resource is excluded. Excluded resources are logged
+// and, when recorder is not nil, recorded as such; a resource already
+// recorded is not logged again.
+func (e Exclusions) Skip(logger logrus.FieldLogger, recorder *Recorder, resource Resource, tags map[string]string) bool {
+	if !e.Excludes(resource, tags) {
+		return false
+	}
+	if recorder == nil || recorder.exclude(resource) {
+		logger.WithField("type", resource.Type).Infof("Skipping excluded %s", resource.Name)
+	}
+	return true
+}
+
+// TagsFromList converts tags of the form key=value, or a bare key, to a map.
+func TagsFromList(tags []string) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) == 2 {
+			m[parts[0]] = parts[1]
+		} else {
+			m[parts[0]] = ""
+		}
+	}
+	return m
+}
@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExclusion(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected string
+		err      string
+	}{
+		{value: "arn:aws:route53:::hostedzone/Z123", expected: "arn:aws:route53:::hostedzone/Z123"},
+		{value: "tag:keep", expected: "tag:keep"},
+		{value: "tag:keep=true", expected: "tag:keep=true"},
+		{value: "tag:=true", err: `invalid exclusion "tag:=true": the tag key is empty`},
+		{value: "", err: "invalid exclusion: the pattern is empty"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			exclusion, err := ParseExclusion(tc.value)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, exclusion.String())
+		})
+	}
+}
+
+func TestExclusionsExcludes(t *testing.T) {
+	zone := Resource{Type: "hostedzone", Name: "arn:aws:route53:::hostedzone/Z123"}
+	bucket := Resource{Type: "s3", Name: "arn:aws:s3:::test-abcde-image-registry"}
+	image := Resource{Type: "image", Name: "test-abcde-rhcos-image", Location: "us-central1"}
+
+	cases := []struct {
+		name       string
+		exclusions []string
+		resource   Resource
+		tags       map[string]string
+		expected   bool
+	}{{
+		name:     "no exclusions",
+		resource: zone,
+		expected: false,
+	}, {
+		name:       "ARN",
+		exclusions: []string{"arn:aws:route53:::hostedzone/Z123"},
+		resource:   zone,
+		expected:   true,
+	}, {
+		name:       "ID",
+		exclusions: []string{"Z123"},
+		resource:   zone,
+		expected:   true,
+	}, {
+		name:       "name pattern",
+		exclusions: []string{"*-image-registry"},
+		resource:   bucket,
+		expected:   true,
+	}, {
+		name:       "single character wildcard",
+		exclusions: []string{"test-?????-rhcos-image"},
+		resource:   image,
+		expected:   true,
+	}, {
+		name:       "pattern is anchored",
+		exclusions: []string{"rhcos"},
+		resource:   image,
+		expected:   false,
+	}, {
+		name:       "dot is literal",
+		exclusions: []string{"test-abcde.rhcos-image"},
+		resource:   image,
+		expected:   false,
+	}, {
+		name:       "ID of a named resource",
+		exclusions: []string{"8d1c3a5e-*"},
+		resource:   Resource{Type: "network", Name: "8d1c3a5e-5b0e-4c1b-a1c4-2f0e4b8c9d7a (test-abcde-openshift)"},
+		expected:   true,
+	}, {
+		name:       "name of a named resource",
+		exclusions: []string{"test-abcde-openshift"},
+		resource:   Resource{Type: "network", Name: "8d1c3a5e-5b0e-4c1b-a1c4-2f0e4b8c9d7a (test-abcde-openshift)"},
+		expected:   true,
+	}, {
+		name:       "tag key",
+		exclusions: []string{"tag:keep"},
+		resource:   bucket,
+		tags:       map[string]string{"keep": "yes"},
+		expected:   true,
+	}, {
+		name:       "tag value",
+		exclusions: []string{"tag:keep=true"},
+		resource:   bucket,
+		tags:       map[string]string{"keep": "false"},
+		expected:   false,
+	}, {
+		name:       "tags unknown",
+		exclusions: []string{"tag:keep"},
+		resource:   bucket,
+		expected:   false,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			exclusions, err := ParseExclusions(tc.exclusions)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expected, exclusions.Excludes(tc.resource, tc.tags))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Scope is the part of an account searched for orphaned clusters.
+type Scope struct {
+	// Region is the AWS, Azure or GCP region. Azure searches every region
+	// when it is empty.
+	Region string
+
+	// Cloud is the clouds.yaml entry for OpenStack or the cloud
+	// environment name for Azure.
+	Cloud string
+
+	// ProjectID is the GCP project.
+	ProjectID string
+}
+
+// Orphan is a cluster whose installer-created resources were found in a
+// Scope.
+type Orphan struct {
+	InfraID string
+
+	// Created is the creation time of the oldest resource found for the
+	// cluster. It is zero when the platform did not report one.
+	Created time.Time
+
+	// Resources is the number of resources found for the cluster. It is
+	// an estimate, as finders only look at the most common resource types.
+	Resources int
+
+	// Metadata describes the cluster to the platform's Destroyer.
+	Metadata *types.ClusterMetadata
+}
+
+// OrphanSet collects the resources found for each infra ID.
+type OrphanSet map[string]*Orphan
+
+// Add counts a resource of the cluster with the given infra ID, created at
+// created, and returns the cluster. A zero created time is ignored.
+func (s OrphanSet) Add(infraID string, created time.Time) *Orphan {
+	orphan, ok := s[infraID]
+	if !ok {
+		orphan = &Orphan{InfraID: infraID}
+		s[infraID] = orphan
+	}
+	orphan.Resources++
+	orphan.Observe(created)
+	return orphan
+}
+
+// Observe records that a resource of the cluster existed at created,
+// keeping the earliest time seen. A zero created time is ignored.
+func (o *Orphan) Observe(created time.Time) {
+	if !created.IsZero() && (o.Created.IsZero() || created.Before(o.Created)) {
+		o.Created = created
+	}
+}
+
+// List returns the clusters sorted by infra ID.
+func (s OrphanSet) List() []Orphan {
+	orphans := make([]Orphan, 0, len(s))
+	for _, orphan := range s {
+		orphans = append(orphans, *orphan)
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].InfraID < orphans[j].InfraID
+	})
+	return orphans
+}
+
+// FindOrphansFunc lists the installer-created clusters in a Scope. Each
+// Orphan carries the metadata needed to destroy it.
+type FindOrphansFunc func(logger logrus.FieldLogger, scope *Scope) ([]Orphan, error)
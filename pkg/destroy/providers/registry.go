@@ -2,3 +2,6 @@ package providers
 
 // Registry maps ClusterMetadata.Platform() to per-platform Destroyer creators.
 var Registry = make(map[string]NewFunc)
+
+// OrphanFinders maps platform names to per-platform orphan finders.
+var OrphanFinders = make(map[string]FindOrphansFunc)
@@ -0,0 +1,182 @@
+package providers
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Report is the outcome of a Destroyer run.
+type Report struct {
+	// Deleted lists the resources removed by the run.
+	Deleted []Resource `json:"deleted"`
+
+	// SkippedShared lists the resources shared with other clusters, which
+	// were left in place with the cluster's tags removed.
+	SkippedShared []Resource `json:"skippedShared"`
+
+	// Excluded lists the resources left in place because they matched an
+	// exclusion.
+	Excluded []Resource `json:"excluded"`
+
+	// Failed lists the resources left behind.
+	Failed []FailedResource `json:"failed"`
+}
+
+// FailedResource is a resource left behind by a Destroyer run.
+type FailedResource struct {
+	Resource
+
+	// Error is the last error seen while deleting the resource.
+	Error string `json:"error"`
+}
+
+// Leftovers reports whether any resource was left behind.
+func (r *Report) Leftovers() bool {
+	return len(r.Failed) > 0
+}
+
+// Recorder collects the outcome of the deletions made by a Destroyer run.
+// The zero value is ready to use, and a Recorder is safe for concurrent use.
+type Recorder struct {
+	mu       sync.Mutex
+	deleted  map[Resource]struct{}
+	shared   map[Resource]struct{}
+	excluded map[Resource]struct{}
+	errs     map[Resource]error
+}
+
+func (r *Recorder) init() {
+	if r.deleted == nil {
+		r.deleted = map[Resource]struct{}{}
+		r.shared = map[Resource]struct{}{}
+		r.excluded = map[Resource]struct{}{}
+		r.errs = map[Resource]error{}
+	}
+}
+
+// Deleted records that resource was removed.
+func (r *Recorder) Deleted(resource Resource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	r.deleted[resource] = struct{}{}
+	delete(r.errs, resource)
+}
+
+// SkippedShared records that resource is shared with other clusters and was
+// left in place.
+func (r *Recorder) SkippedShared(resource Resource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	r.shared[resource] = struct{}{}
+}
+
+// Excluded records that resource matched an exclusion and was left in
+// place.
+func (r *Recorder) Excluded(resource Resource) {
+	r.exclude(resource)
+}
+
+// exclude records resource as excluded and reports whether it was not
+// recorded before.
+func (r *Recorder) exclude(resource Resource) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	if _, ok := r.excluded[resource]; ok {
+		return false
+	}
+	r.excluded[resource] = struct{}{}
+	delete(r.errs, resource)
+	return true
+}
+
+// Error records err as the last error seen while deleting resource. A nil
+// err is ignored.
+func (r *Recorder) Error(resource Resource, err error) {
+	if err == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+	r.errs[resource] = err
+}
+
+// Report returns the recorded deletions, with leftovers as the failed
+// resources. Each leftover carries the last error recorded for it or, if
+// there is none, runErr. Leftovers recorded as excluded are not failures.
+func (r *Recorder) Report(leftovers []Resource, runErr error) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.init()
+
+	report := &Report{
+		Deleted:       []Resource{},
+		SkippedShared: []Resource{},
+		Excluded:      []Resource{},
+		Failed:        []FailedResource{},
+	}
+	for resource := range r.deleted {
+		report.Deleted = append(report.Deleted, resource)
+	}
+	for resource := range r.shared {
+		report.SkippedShared = append(report.SkippedShared, resource)
+	}
+	for resource := range r.excluded {
+		report.Excluded = append(report.Excluded, resource)
+	}
+	SortResources(report.Deleted)
+	SortResources(report.SkippedShared)
+	SortResources(report.Excluded)
+
+	sorted := append([]Resource(nil), leftovers...)
+	SortResources(sorted)
+	for _, resource := range sorted {
+		if _, ok := r.excluded[resource]; ok {
+			continue
+		}
+		message := "still present after destroy"
+		if err, ok := r.errs[resource]; ok {
+			message = err.Error()
+		} else if runErr != nil {
+			message = runErr.Error()
+		}
+		report.Failed = append(report.Failed, FailedResource{Resource: resource, Error: message})
+	}
+	return report
+}
+
+// RunWithInventory runs destroy and reports the resources listed by
+// inventory before it as deleted, unless inventory still lists them
+// afterwards, in which case they are reported as failed.
+func RunWithInventory(logger logrus.FieldLogger, inventory func() ([]Resource, error), destroy func(*Recorder) error) (*Report, error) {
+	recorder := &Recorder{}
+	before, err := inventory()
+	if err != nil {
+		logger.WithError(err).Debug("Failed to list resources, deleted resources will not be reported")
+	}
+
+	runErr := destroy(recorder)
+
+	after, err := inventory()
+	if err != nil {
+		if runErr == nil {
+			runErr = errors.Wrap(err, "failed to list the resources left behind")
+		}
+		return recorder.Report(nil, runErr), runErr
+	}
+	remaining := make(map[Resource]struct{}, len(after))
+	for _, resource := range after {
+		remaining[resource] = struct{}{}
+	}
+	for _, resource := range before {
+		if _, ok := remaining[resource]; !ok {
+			recorder.Deleted(resource)
+		}
+	}
+	return recorder.Report(after, runErr), runErr
+}
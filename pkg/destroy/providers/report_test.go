@@ -0,0 +1,94 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunWithInventory(t *testing.T) {
+	vm := Resource{Type: "vm", Name: "test-abcde-master-0"}
+	folder := Resource{Type: "folder", Name: "test-abcde"}
+	tag := Resource{Type: "tag", Name: "test-abcde"}
+
+	cases := []struct {
+		name     string
+		after    []Resource
+		destroy  func(*Recorder) error
+		expected *Report
+		err      string
+	}{{
+		name:    "everything deleted",
+		destroy: func(*Recorder) error { return nil },
+		expected: &Report{
+			Deleted:       []Resource{folder, tag, vm},
+			SkippedShared: []Resource{},
+			Excluded:      []Resource{},
+			Failed:        []FailedResource{},
+		},
+	}, {
+		name:  "leftover with recorded error",
+		after: []Resource{folder},
+		destroy: func(recorder *Recorder) error {
+			recorder.Error(folder, errors.New("folder is not empty"))
+			return nil
+		},
+		expected: &Report{
+			Deleted:       []Resource{tag, vm},
+			SkippedShared: []Resource{},
+			Excluded:      []Resource{},
+			Failed:        []FailedResource{{Resource: folder, Error: "folder is not empty"}},
+		},
+	}, {
+		name:  "leftover after failed run",
+		after: []Resource{tag, vm},
+		destroy: func(recorder *Recorder) error {
+			recorder.SkippedShared(Resource{Type: "network", Name: "shared"})
+			return errors.New("connection refused")
+		},
+		expected: &Report{
+			Deleted:       []Resource{folder},
+			SkippedShared: []Resource{{Type: "network", Name: "shared"}},
+			Excluded:      []Resource{},
+			Failed: []FailedResource{
+				{Resource: tag, Error: "connection refused"},
+				{Resource: vm, Error: "connection refused"},
+			},
+		},
+		err: "connection refused",
+	}, {
+		name:  "excluded resource left in place",
+		after: []Resource{folder},
+		destroy: func(recorder *Recorder) error {
+			recorder.Excluded(folder)
+			return nil
+		},
+		expected: &Report{
+			Deleted:       []Resource{tag, vm},
+			SkippedShared: []Resource{},
+			Excluded:      []Resource{folder},
+			Failed:        []FailedResource{},
+		},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			inventory := func() ([]Resource, error) {
+				calls++
+				if calls == 1 {
+					return []Resource{vm, folder, tag}, nil
+				}
+				return tc.after, nil
+			}
+			report, err := RunWithInventory(logrus.New(), inventory, tc.destroy)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, report)
+		})
+	}
+}
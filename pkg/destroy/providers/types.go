@@ -1,6 +1,9 @@
 package providers
 
 import (
+	"context"
+	"sort"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/types"
@@ -9,7 +12,64 @@ import (
 // Destroyer allows multiple implementations of destroy
 // for different platforms.
 type Destroyer interface {
-	Run() error
+	// Run removes the cluster resources and reports what was deleted,
+	// skipped and left behind. Run stops when ctx is done, reporting the
+	// resources that remain. The report may be nil if Run fails before
+	// deleting anything.
+	Run(ctx context.Context, options RunOptions) (*Report, error)
+
+	// Inventory returns the resources that Run would remove, without
+	// deleting anything.
+	Inventory() ([]Resource, error)
+}
+
+// RunOptions controls how a Destroyer deletes resources.
+type RunOptions struct {
+	// Concurrency is the maximum number of resources a Destroyer deletes
+	// in parallel. Zero selects the platform's default. Platforms that
+	// delete resources in a fixed order ignore it.
+	Concurrency int
+
+	// Exclusions selects resources the Destroyer must leave in place, even
+	// when they belong to the cluster. They are reported as excluded.
+	Exclusions Exclusions
+}
+
+// ConcurrencyOrDefault returns Concurrency, or defaultConcurrency when
+// Concurrency is not positive.
+func (o RunOptions) ConcurrencyOrDefault(defaultConcurrency int) int {
+	if o.Concurrency > 0 {
+		return o.Concurrency
+	}
+	return defaultConcurrency
+}
+
+// Resource describes a single platform resource found by a Destroyer.
+type Resource struct {
+	// Type is the platform-specific kind of the resource, e.g. "instance".
+	Type string `json:"type"`
+
+	// Name is the identifier used to address the resource on the platform,
+	// e.g. an ARN, a UUID or a name.
+	Name string `json:"name"`
+
+	// Location is the region, zone, resource group or namespace holding
+	// the resource, if any.
+	Location string `json:"location,omitempty"`
+}
+
+// SortResources sorts resources by type, location and name.
+func SortResources(resources []Resource) {
+	sort.Slice(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Name < b.Name
+	})
 }
 
 // NewFunc is an interface for creating platform-specific destroyers.
@@ -0,0 +1,38 @@
+package destroy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// ReportFileName is the name of the file in the asset directory that holds
+// the report of the last destroy run.
+const ReportFileName = "destroy-report.json"
+
+// WriteReport writes report as JSON to ReportFileName in directory.
+func WriteReport(directory string, report *providers.Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal destroy report")
+	}
+	path := filepath.Join(directory, ReportFileName)
+	if err := ioutil.WriteFile(path, append(data, '\n'), 0640); err != nil {
+		return errors.Wrapf(err, "failed to write %s", path)
+	}
+	return nil
+}
+
+// LogReport summarizes report and logs every resource left behind.
+func LogReport(logger logrus.FieldLogger, report *providers.Report) {
+	logger.Infof("Deleted %d resources, skipped %d shared resources, kept %d excluded resources, left %d resources behind",
+		len(report.Deleted), len(report.SkippedShared), len(report.Excluded), len(report.Failed))
+	for _, failed := range report.Failed {
+		logger.WithField("type", failed.Type).Errorf("Left behind %s: %s", failed.Name, failed.Error)
+	}
+}
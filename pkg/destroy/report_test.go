@@ -0,0 +1,63 @@
+package destroy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+func TestWriteReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "destroy-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	recorder := &providers.Recorder{}
+	network := providers.Resource{Type: "network", Name: "test-abcde-network"}
+	recorder.Deleted(providers.Resource{Type: "instance", Name: "test-abcde-master-0", Location: "us-central1-a"})
+	recorder.SkippedShared(providers.Resource{Type: "subnetwork", Name: "shared-subnet"})
+	recorder.Excluded(providers.Resource{Type: "bucket", Name: "test-abcde-image-registry"})
+	recorder.Error(network, errors.New("resource is in use"))
+	report := recorder.Report([]providers.Resource{network}, nil)
+
+	assert.NoError(t, WriteReport(dir, report))
+	data, err := ioutil.ReadFile(filepath.Join(dir, ReportFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "deleted": [
+    {
+      "type": "instance",
+      "name": "test-abcde-master-0",
+      "location": "us-central1-a"
+    }
+  ],
+  "skippedShared": [
+    {
+      "type": "subnetwork",
+      "name": "shared-subnet"
+    }
+  ],
+  "excluded": [
+    {
+      "type": "bucket",
+      "name": "test-abcde-image-registry"
+    }
+  ],
+  "failed": [
+    {
+      "type": "network",
+      "name": "test-abcde-network",
+      "error": "resource is in use"
+    }
+  ]
+}
+`, string(data))
+	assert.True(t, report.Leftovers())
+}
@@ -0,0 +1,45 @@
+package vsphere
+
+import (
+	"context"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+)
+
+// Inventory returns the resources that Run would remove, without deleting anything.
+func (o *ClusterUninstaller) Inventory() ([]providers.Resource, error) {
+	ctx := context.TODO()
+
+	o.Logger.Debug("Find attached objects on tag")
+	tagAttachedObjects, err := getAttachedObjectsOnTag(ctx, o.RestClient, o.InfraID)
+	if err != nil {
+		return nil, err
+	}
+	folderList, virtualMachineList := splitAttachedObjects(tagAttachedObjects)
+
+	var resources []providers.Resource
+	if len(virtualMachineList) > 0 {
+		virtualMachineMoList, err := getVirtualMachineManagedObjects(ctx, o.Client, virtualMachineList)
+		if err != nil {
+			return nil, err
+		}
+		for _, vmMO := range virtualMachineMoList {
+			resources = append(resources, providers.Resource{Type: "VirtualMachine", Name: vmMO.Name})
+		}
+	}
+	if len(folderList) > 0 {
+		folderMoList, err := getFolderManagedObjects(ctx, o.Client, folderList)
+		if err != nil {
+			return nil, err
+		}
+		for _, folderMO := range folderMoList {
+			resources = append(resources, providers.Resource{Type: "Folder", Name: folderMO.Name})
+		}
+	}
+	resources = append(resources,
+		providers.Resource{Type: "Tag", Name: o.InfraID},
+		providers.Resource{Type: "TagCategory", Name: "openshift-" + o.InfraID},
+	)
+	providers.SortResources(resources)
+	return resources, nil
+}
@@ -52,18 +52,22 @@ func New(logger logrus.FieldLogger, metadata *installertypes.ClusterMetadata) (p
 	}, nil
 }
 
-func deleteVirtualMachines(ctx context.Context, client *vim25.Client, virtualMachineMoList []mo.VirtualMachine, logger logrus.FieldLogger) error {
+func deleteVirtualMachines(ctx context.Context, client *vim25.Client, virtualMachineMoList []mo.VirtualMachine, skip func(providers.Resource) bool, logger logrus.FieldLogger) (kept bool, err error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Minute*30)
 	defer cancel()
 
 	if len(virtualMachineMoList) != 0 {
 		for _, vmMO := range virtualMachineMoList {
+			if skip(providers.Resource{Type: "VirtualMachine", Name: vmMO.Name}) {
+				kept = true
+				continue
+			}
 			virtualMachineLogger := logger.WithField("VirtualMachine", vmMO.Name)
 			vm := object.NewVirtualMachine(client, vmMO.Reference())
 			if vmMO.Summary.Runtime.PowerState == "poweredOn" {
 				task, err := vm.PowerOff(ctx)
 				if err != nil {
-					return err
+					return kept, err
 				}
 				task.Wait(ctx)
 				virtualMachineLogger.Debug("Powered off")
@@ -71,13 +75,13 @@ func deleteVirtualMachines(ctx context.Context, client *vim25.Client, virtualMac
 
 			task, err := vm.Destroy(ctx)
 			if err != nil {
-				return err
+				return kept, err
 			}
 			task.Wait(ctx)
 			virtualMachineLogger.Info("Destroyed")
 		}
 	}
-	return nil
+	return kept, nil
 }
 func deleteFolder(ctx context.Context, client *vim25.Client, folderMoList []mo.Folder, logger logrus.FieldLogger) error {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*60)
@@ -130,6 +134,22 @@ func getAttachedObjectsOnTag(ctx context.Context, client *rest.Client, tagName s
 	return attached, nil
 }
 
+// splitAttachedObjects separates the objects attached to the tag based on type.
+// We only need Folder and VirtualMachine.
+func splitAttachedObjects(tagAttachedObjects []tags.AttachedObjects) (folderList []types.ManagedObjectReference, virtualMachineList []types.ManagedObjectReference) {
+	for _, attachedObject := range tagAttachedObjects {
+		for _, ref := range attachedObject.ObjectIDs {
+			if ref.Reference().Type == "Folder" {
+				folderList = append(folderList, ref.Reference())
+			}
+			if ref.Reference().Type == "VirtualMachine" {
+				virtualMachineList = append(virtualMachineList, ref.Reference())
+			}
+		}
+	}
+	return folderList, virtualMachineList
+}
+
 func deleteTag(ctx context.Context, client *rest.Client, tagID string) error {
 	tagManager := tags.NewManager(client)
 	tag, err := tagManager.GetTag(ctx, tagID)
@@ -149,28 +169,24 @@ func deleteTagCategory(ctx context.Context, client *rest.Client, categoryID stri
 }
 
 // Run is the entrypoint to start the uninstall process.
-func (o *ClusterUninstaller) Run() error {
-	var folderList []types.ManagedObjectReference
-	var virtualMachineList []types.ManagedObjectReference
+func (o *ClusterUninstaller) Run(ctx context.Context, options providers.RunOptions) (*providers.Report, error) {
+	return providers.RunWithInventory(o.Logger, o.Inventory, func(recorder *providers.Recorder) error {
+		return o.destroy(ctx, func(resource providers.Resource) bool {
+			return options.Exclusions.Skip(o.Logger, recorder, resource, nil)
+		})
+	})
+}
 
+// destroy removes the cluster resources that skip does not select. The
+// folder, tag and tag category are kept along with any excluded virtual
+// machine, so that the cluster can still be found.
+func (o *ClusterUninstaller) destroy(ctx context.Context, skip func(providers.Resource) bool) error {
 	o.Logger.Debug("Find attached objects on tag")
-	tagAttachedObjects, err := getAttachedObjectsOnTag(context.TODO(), o.RestClient, o.InfraID)
+	tagAttachedObjects, err := getAttachedObjectsOnTag(ctx, o.RestClient, o.InfraID)
 	if err != nil {
 		return err
 	}
-
-	// Seperate the objects attached to the tag based on type
-	// We only need Folder and VirtualMachine
-	for _, attachedObject := range tagAttachedObjects {
-		for _, ref := range attachedObject.ObjectIDs {
-			if ref.Reference().Type == "Folder" {
-				folderList = append(folderList, ref.Reference())
-			}
-			if ref.Reference().Type == "VirtualMachine" {
-				virtualMachineList = append(virtualMachineList, ref.Reference())
-			}
-		}
-	}
+	folderList, virtualMachineList := splitAttachedObjects(tagAttachedObjects)
 
 	// The installer should create at most one parent,
 	// the parent to the VirtualMachines.
@@ -179,14 +195,15 @@ func (o *ClusterUninstaller) Run() error {
 		return errors.Errorf("Expected 1 Folder per tag but got %d", len(folderList))
 	}
 
+	kept := false
 	if len(virtualMachineList) > 0 {
 		o.Logger.Debug("Find VirtualMachine objects")
-		virtualMachineMoList, err := getVirtualMachineManagedObjects(context.TODO(), o.Client, virtualMachineList)
+		virtualMachineMoList, err := getVirtualMachineManagedObjects(ctx, o.Client, virtualMachineList)
 		if err != nil {
 			return err
 		}
 		o.Logger.Debug("Delete VirtualMachines")
-		err = deleteVirtualMachines(context.TODO(), o.Client, virtualMachineMoList, o.Logger)
+		kept, err = deleteVirtualMachines(ctx, o.Client, virtualMachineMoList, skip, o.Logger)
 		if err != nil {
 			return err
 		}
@@ -196,37 +213,52 @@ func (o *ClusterUninstaller) Run() error {
 
 	if len(folderList) > 0 {
 		o.Logger.Debug("Find Folder objects")
-		folderMoList, err := getFolderManagedObjects(context.TODO(), o.Client, folderList)
+		folderMoList, err := getFolderManagedObjects(ctx, o.Client, folderList)
 		if err != nil {
 			o.Logger.Errorln(err)
 			return err
 		}
 
-		o.Logger.Debug("Delete Folder")
-		err = deleteFolder(context.TODO(), o.Client, folderMoList, o.Logger)
-		if err != nil {
-			o.Logger.Errorln(err)
-			return err
+		switch {
+		case kept:
+			o.Logger.WithField("Folder", folderMoList[0].Name).Info("Keeping folder with excluded VirtualMachines")
+		case skip(providers.Resource{Type: "Folder", Name: folderMoList[0].Name}):
+			kept = true
+		default:
+			o.Logger.Debug("Delete Folder")
+			err = deleteFolder(ctx, o.Client, folderMoList, o.Logger)
+			if err != nil {
+				o.Logger.Errorln(err)
+				return err
+			}
 		}
 	} else {
 		o.Logger.Debug("No managed Folder found")
 	}
+	if kept {
+		o.Logger.WithField("Tag", o.InfraID).Info("Keeping tag and tag category of excluded objects")
+		return nil
+	}
 
-	o.Logger.Debug("Delete tag")
-	tagLogger := o.Logger.WithField("Tag", o.InfraID)
-	if err = deleteTag(context.TODO(), o.RestClient, o.InfraID); err != nil {
-		tagLogger.Errorln(err)
-		return err
+	if !skip(providers.Resource{Type: "Tag", Name: o.InfraID}) {
+		o.Logger.Debug("Delete tag")
+		tagLogger := o.Logger.WithField("Tag", o.InfraID)
+		if err = deleteTag(ctx, o.RestClient, o.InfraID); err != nil {
+			tagLogger.Errorln(err)
+			return err
+		}
+		tagLogger.Info("Destroyed")
 	}
-	tagLogger.Info("Destroyed")
 
-	o.Logger.Debug("Delete tag category")
-	tcLogger := o.Logger.WithField("TagCategory", "openshift-"+o.InfraID)
-	if err = deleteTagCategory(context.TODO(), o.RestClient, "openshift-"+o.InfraID); err != nil {
-		tcLogger.Errorln(err)
-		return err
+	if !skip(providers.Resource{Type: "TagCategory", Name: "openshift-" + o.InfraID}) {
+		o.Logger.Debug("Delete tag category")
+		tcLogger := o.Logger.WithField("TagCategory", "openshift-"+o.InfraID)
+		if err = deleteTagCategory(ctx, o.RestClient, "openshift-"+o.InfraID); err != nil {
+			tcLogger.Errorln(err)
+			return err
+		}
+		tcLogger.Info("Destroyed")
 	}
-	tcLogger.Info("Destroyed")
 
 	return nil
 }
@@ -25,6 +25,9 @@ func Test_PrintFields(t *testing.T) {
     apiVersion <string>
       APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources
 
+    authentication <object>
+      Authentication configures the identity providers the users log in with. If set, the installer does not create the kubeadmin user.
+
     baseDomain <string> -required-
       BaseDomain is the base domain to which the cluster should belong.
 
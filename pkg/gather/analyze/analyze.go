@@ -0,0 +1,284 @@
+// Package analyze looks for known install failures in the log bundles
+// written by gather.
+package analyze
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/diagnostics"
+)
+
+const (
+	// source is the Source of every finding.
+	source = "Log Bundle"
+
+	// maxLineLength bounds the lines read from the bundle files.
+	maxLineLength = 1024 * 1024
+
+	// maxExcerptLength bounds the matched line quoted in a finding.
+	maxExcerptLength = 300
+)
+
+// Finding is a known failure found in a log bundle.
+type Finding struct {
+	*diagnostics.Err
+
+	// Files are the files of the bundle showing the failure, relative to
+	// the bundle directory.
+	Files []string
+}
+
+// detector finds one kind of failure in a bundle.
+type detector struct {
+	reason  string
+	message string
+
+	// files are the patterns of the files searched, see Bundle.Glob.
+	files []string
+
+	// match selects the lines showing the failure on their own. When both
+	// match and repeated are nil, check is used instead.
+	match *regexp.Regexp
+
+	// repeated selects the lines that healthy installs log too while the
+	// control plane converges, and that only show the failure when a file
+	// has at least threshold of them.
+	repeated  *regexp.Regexp
+	threshold int
+
+	// check inspects the files and returns a description of the failure,
+	// or an empty string if there is none.
+	check func(path string) (string, error)
+}
+
+// detectors is the catalog of known failures, covering the bootstrap log
+// bundle and the cluster bundle.
+var detectors = []detector{{
+	reason:  "EtcdQuorumLost",
+	message: "etcd lost quorum or could not reach its peers, so the API server cannot persist anything. Check that the control plane hosts can reach each other on ports 2379 and 2380.",
+	files: []string{
+		"bootstrap/containers/etcd*.log",
+		"control-plane/*/containers/etcd*.log",
+		"pods/openshift-etcd/*/*.log",
+	},
+	match:     regexp.MustCompile(`etcdserver: no leader|lost leader|(?:has|with) no leader|cluster is unavailable|(?:lost|no) quorum`),
+	repeated:  regexp.MustCompile(`etcdserver: (?:request timed out|leader changed)|failed to reach the peer`),
+	threshold: 20,
+}, {
+	reason:  "ImagePullFailure",
+	message: "Container images could not be pulled. Check the pull secret, the mirror configuration and that the hosts can reach the registry.",
+	files: []string{
+		"bootstrap/journals/kubelet.log",
+		"bootstrap/journals/crio.log",
+		"control-plane/*/journals/kubelet.log",
+		"control-plane/*/journals/crio.log",
+		"journals/*/kubelet.log",
+		"journals/*/crio.log",
+	},
+	match:     regexp.MustCompile(`error pinging (?:docker )?registry|unauthorized: authentication required|manifest unknown`),
+	repeated:  regexp.MustCompile(`ErrImagePull|ImagePullBackOff|[Ff]ailed to pull image`),
+	threshold: 20,
+}, {
+	reason:  "KubeAPIServerCrashLoop",
+	message: "kube-apiserver keeps restarting. Its container logs usually show the cause, often etcd being unavailable or invalid certificates.",
+	files: []string{
+		"bootstrap/journals/kubelet.log",
+		"control-plane/*/journals/kubelet.log",
+		"journals/*/kubelet.log",
+	},
+	repeated:  regexp.MustCompile(`(?:CrashLoopBackOff|back-off \S+ restarting failed container)[^\n]*kube-apiserver|kube-apiserver[^\n]*CrashLoopBackOff`),
+	threshold: 10,
+}, {
+	reason:  "ReleaseImagePullFailure",
+	message: "The bootstrap host could not pull the release image. Check the pull secret, the release image override and that the bootstrap host can reach the registry.",
+	files:   []string{"bootstrap/journals/release-image.log"},
+	match:   regexp.MustCompile(`(?i)error (?:pulling|initializing source|reading manifest)|unable to pull|failed to pull|Error: .*(?:pull|manifest)`),
+}, {
+	reason:  "IgnitionFetchFailure",
+	message: "Hosts could not fetch their Ignition config from the machine config server. Check that the API load balancer forwards port 22623 to the bootstrap and control plane hosts.",
+	files: []string{
+		"bootstrap/containers/machine-config-server*.log",
+		"control-plane/*/journals/*.log",
+		"journals/*/*.log",
+		"unit-status/*.log",
+		"control-plane/*/unit-status/*.log",
+	},
+	match: regexp.MustCompile(`ignition\[\d+\]: .*(?:GET error|failed to fetch config|error at \$\.ignition)|couldn't get config for req|could not get config`),
+}, {
+	reason:  "CSRBacklog",
+	message: "Certificate signing requests are waiting for approval, so nodes cannot join the cluster. Check the approve-csr unit on the bootstrap host and the cluster-machine-approver.",
+	files:   []string{"resources/csr.json"},
+	check:   pendingCSRs,
+}}
+
+// Analyze runs the detectors against bundle and returns a finding for each
+// failure found.
+func Analyze(bundle *Bundle) ([]Finding, error) {
+	var findings []Finding
+	for _, d := range detectors {
+		finding, err := d.detect(bundle)
+		if err != nil {
+			return findings, errors.Wrapf(err, "failed to check for %s", d.reason)
+		}
+		if finding != nil {
+			findings = append(findings, *finding)
+		}
+	}
+	return findings, nil
+}
+
+func (d *detector) detect(bundle *Bundle) (*Finding, error) {
+	files, err := bundle.Glob(d.files...)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+	var excerpt string
+	for _, file := range files {
+		var found string
+		if d.match != nil || d.repeated != nil {
+			found, err = d.matchLines(bundle.Path(file))
+		} else {
+			found, err = d.check(bundle.Path(file))
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", file)
+		}
+		if found == "" {
+			continue
+		}
+		matched = append(matched, file)
+		if excerpt == "" {
+			excerpt = found
+		}
+	}
+	if len(matched) == 0 {
+		return nil, nil
+	}
+	return &Finding{
+		Err: &diagnostics.Err{
+			Orig:    errors.New(excerpt),
+			Source:  source,
+			Reason:  d.reason,
+			Message: d.message,
+		},
+		Files: matched,
+	}, nil
+}
+
+// matchLines returns the first line of the file at path selected by
+// d.match, or else the last line selected by d.repeated when the file has at
+// least d.threshold of them.
+func (d *detector) matchLines(path string) (string, error) {
+	var found, last string
+	count := 0
+	err := scanLines(path, func(line string) bool {
+		if d.match != nil && d.match.MatchString(line) {
+			found = line
+			return false
+		}
+		if d.repeated != nil && d.repeated.MatchString(line) {
+			count++
+			last = line
+		}
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case found != "":
+		return excerpt(found), nil
+	case count > 0 && count >= d.threshold:
+		return fmt.Sprintf("%s (%d times)", excerpt(last), count), nil
+	default:
+		return "", nil
+	}
+}
+
+// scanLines calls fn with the lines of the file at path until it returns
+// false. The lines longer than maxLineLength are skipped.
+func scanLines(path string, fn func(line string) bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !tooLong {
+			line = append(line, chunk...)
+			if len(line) > maxLineLength {
+				tooLong, line = true, line[:0]
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if !tooLong && len(line) > 0 && !fn(strings.TrimRight(string(line), "\r\n")) {
+			return nil
+		}
+		line, tooLong = line[:0], false
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func excerpt(line string) string {
+	line = strings.TrimSpace(line)
+	if len(line) > maxExcerptLength {
+		line = line[:maxExcerptLength] + "..."
+	}
+	return line
+}
+
+// pendingCSRs reports the certificate signing requests in a CSR list that
+// were neither approved nor denied.
+func pendingCSRs(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Status struct {
+				Conditions []json.RawMessage `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		// The gather script writes nothing useful when the API is down.
+		return "", nil
+	}
+	var pending []string
+	for _, csr := range list.Items {
+		if len(csr.Status.Conditions) == 0 {
+			pending = append(pending, csr.Metadata.Name)
+		}
+	}
+	if len(pending) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%d pending certificate signing requests: %s", len(pending), strings.Join(pending, ", ")), nil
+}
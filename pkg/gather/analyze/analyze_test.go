@@ -0,0 +1,192 @@
+package analyze
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	cases := []struct {
+		name     string
+		files    map[string]string
+		expected map[string][]string
+	}{{
+		name: "healthy",
+		files: map[string]string{
+			"log-bundle-1/bootstrap/journals/release-image.log": "Pulling quay.io/openshift-release-dev/ocp-release@sha256:abc...\n",
+			"log-bundle-1/resources/csr.json":                   `{"items": [{"metadata": {"name": "csr-1"}, "status": {"conditions": [{"type": "Approved"}]}}]}`,
+		},
+		expected: map[string][]string{},
+	}, {
+		name: "healthy bootstrap converging",
+		files: map[string]string{
+			"log-bundle-1/bootstrap/containers/etcd-abc.log": strings.Repeat("etcdserver: leader changed\n", 3) + "etcdserver: request timed out\n",
+			"log-bundle-1/bootstrap/journals/kubelet.log": strings.Repeat("Failed to pull image \"quay.io/x\": rpc error: connection reset by peer\n", 2) +
+				strings.Repeat("pod_workers.go:191] Error syncing pod: back-off 10s restarting failed container=kube-apiserver\n", 3) +
+				"started\n",
+		},
+		expected: map[string][]string{},
+	}, {
+		name: "oversized line",
+		files: map[string]string{
+			"log-bundle-1/bootstrap/journals/release-image.log": strings.Repeat("x", maxLineLength+1) + "\nError: error pulling image: unable to pull\n",
+		},
+		expected: map[string][]string{
+			"ReleaseImagePullFailure": {"log-bundle-1/bootstrap/journals/release-image.log"},
+		},
+	}, {
+		name: "release image",
+		files: map[string]string{
+			"log-bundle-1/bootstrap/journals/release-image.log": "Pulling quay.io/openshift-release-dev/ocp-release...\nError: error pulling image \"quay.io/openshift-release-dev/ocp-release\": unable to pull\n",
+		},
+		expected: map[string][]string{
+			"ReleaseImagePullFailure": {"log-bundle-1/bootstrap/journals/release-image.log"},
+		},
+	}, {
+		name: "etcd and image pulls on several masters",
+		files: map[string]string{
+			"log-bundle-1/control-plane/10.0.0.6/containers/etcd-abc.log":   "etcdserver: no leader\n",
+			"log-bundle-1/control-plane/10.0.0.6/journals/kubelet.log":      strings.Repeat("Error syncing pod: ErrImagePull\n", 20),
+			"log-bundle-1/control-plane/10.0.0.7/journals/kubelet.log":      "Failed to pull image \"quay.io/x\": unauthorized: authentication required\n",
+			"log-bundle-1/control-plane/10.0.0.8/containers/etcd-def.log":   "started\n",
+			"log-bundle-1/control-plane/10.0.0.8/journals/kubelet.log":      "started\n",
+			"log-bundle-1/bootstrap/containers/machine-config-server-1.log": "I0101 serving\n",
+		},
+		expected: map[string][]string{
+			"EtcdQuorumLost": {"log-bundle-1/control-plane/10.0.0.6/containers/etcd-abc.log"},
+			"ImagePullFailure": {
+				"log-bundle-1/control-plane/10.0.0.6/journals/kubelet.log",
+				"log-bundle-1/control-plane/10.0.0.7/journals/kubelet.log",
+			},
+		},
+	}, {
+		name: "cluster bundle",
+		files: map[string]string{
+			"cluster-bundle-1/journals/master-0/kubelet.log": strings.Repeat(`pod_workers.go:191] Error syncing pod, skipping: failed to "StartContainer" for "kube-apiserver" with CrashLoopBackOff`+"\n", 10),
+		},
+		expected: map[string][]string{
+			"KubeAPIServerCrashLoop": {"cluster-bundle-1/journals/master-0/kubelet.log"},
+		},
+	}, {
+		name: "csr backlog and ignition",
+		files: map[string]string{
+			"log-bundle-1/resources/csr.json":                               `{"items": [{"metadata": {"name": "csr-1"}, "status": {}}, {"metadata": {"name": "csr-2"}, "status": {}}]}`,
+			"log-bundle-1/bootstrap/containers/machine-config-server-1.log": "E0101 couldn't get config for req: {worker}, error: unknown pool\n",
+		},
+		expected: map[string][]string{
+			"CSRBacklog":           {"log-bundle-1/resources/csr.json"},
+			"IgnitionFetchFailure": {"log-bundle-1/bootstrap/containers/machine-config-server-1.log"},
+		},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "analyze")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			writeFiles(t, dir, tc.files)
+
+			bundle, err := Open(dir)
+			if !assert.NoError(t, err) {
+				return
+			}
+			findings, err := Analyze(bundle)
+			assert.NoError(t, err)
+			actual := map[string][]string{}
+			for _, finding := range findings {
+				actual[finding.Reason] = finding.Files
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestCSRBacklogMessage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "analyze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"resources/csr.json": `{"items": [{"metadata": {"name": "csr-1"}, "status": {}}]}`,
+	})
+
+	findings, err := Analyze(&Bundle{Dir: dir})
+	assert.NoError(t, err)
+	if assert.Len(t, findings, 1) {
+		assert.Regexp(t, `^error\(CSRBacklog\) from Log Bundle: .*: 1 pending certificate signing requests: csr-1$`, findings[0].Error())
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "analyze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		name  string
+		files map[string]string
+		err   string
+	}{{
+		name:  "valid",
+		files: map[string]string{"log-bundle-1/bootstrap/journals/kubelet.log": "started\n"},
+	}, {
+		name:  "path traversal",
+		files: map[string]string{"../escape.log": "boom\n"},
+		err:   `failed to unpack .*: invalid path "../escape.log" in bundle`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "bundle.tar.gz")
+			file, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gz := gzip.NewWriter(file)
+			tw := tar.NewWriter(gz)
+			for name, data := range tc.files {
+				assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg}))
+				_, err := tw.Write([]byte(data))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, tw.Close())
+			assert.NoError(t, gz.Close())
+			assert.NoError(t, file.Close())
+
+			bundle, err := Open(path)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer bundle.Close()
+			files, err := bundle.Glob("bootstrap/journals/*.log")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"log-bundle-1/bootstrap/journals/kubelet.log"}, files)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package analyze
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Bundle is an unpacked log bundle.
+type Bundle struct {
+	// Dir is the directory holding the files of the bundle.
+	Dir string
+
+	// temporary is set when Dir was created by Open and is removed by
+	// Close.
+	temporary bool
+}
+
+// Open unpacks the log bundle at path into a temporary directory. path may
+// also be a directory holding an already unpacked bundle.
+func Open(path string) (*Bundle, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &Bundle{Dir: path}, nil
+	}
+
+	dir, err := ioutil.TempDir("", "log-bundle")
+	if err != nil {
+		return nil, err
+	}
+	bundle := &Bundle{Dir: dir, temporary: true}
+	if err := unpack(path, dir); err != nil {
+		bundle.Close()
+		return nil, errors.Wrapf(err, "failed to unpack %s", path)
+	}
+	return bundle, nil
+}
+
+// Close removes the files unpacked by Open.
+func (b *Bundle) Close() error {
+	if !b.temporary {
+		return nil
+	}
+	return os.RemoveAll(b.Dir)
+}
+
+// Glob returns the files of the bundle matching any of patterns, relative to
+// the bundle directory and sorted. Patterns are matched below the top-level
+// directory of the bundle, e.g. "bootstrap/journals/*.log" matches
+// "log-bundle-<id>/bootstrap/journals/kubelet.log".
+func (b *Bundle) Glob(patterns ...string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		for _, prefix := range []string{"", "*"} {
+			matches, err := filepath.Glob(filepath.Join(b.Dir, prefix, filepath.FromSlash(pattern)))
+			if err != nil {
+				return nil, err
+			}
+			for _, match := range matches {
+				if info, err := os.Stat(match); err != nil || info.IsDir() {
+					continue
+				}
+				rel, err := filepath.Rel(b.Dir, match)
+				if err != nil {
+					return nil, err
+				}
+				files = append(files, filepath.ToSlash(rel))
+			}
+		}
+	}
+	sort.Strings(files)
+	return dedupe(files), nil
+}
+
+// Path returns the absolute path of a file returned by Glob.
+func (b *Bundle) Path(file string) string {
+	return filepath.Join(b.Dir, filepath.FromSlash(file))
+}
+
+func dedupe(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// unpack extracts the gzipped tarball at path into dir, ignoring entries
+// that are neither regular files nor directories.
+func unpack(path string, dir string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if target != dir && !strings.HasPrefix(target, dir+string(os.PathSeparator)) {
+			return errors.Errorf("invalid path %q in bundle", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,101 @@
+// Package aws finds the hosts of an AWS cluster through the EC2 API.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	awssession "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	"github.com/openshift/installer/pkg/gather"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Source returns a source listing the running bootstrap and control plane
+// instances of the cluster described by metadata.
+func Source(metadata *types.ClusterMetadata) gather.Source {
+	return gather.Source{
+		Name: "the EC2 API",
+		Hosts: func(ctx context.Context) (*gather.Hosts, error) {
+			return hosts(ctx, metadata)
+		},
+	}
+}
+
+func hosts(ctx context.Context, metadata *types.ClusterMetadata) (*gather.Hosts, error) {
+	region := metadata.AWS.Region
+	session, err := awssession.GetSessionWithOptions(
+		awssession.WithRegion(region),
+		awssession.WithServiceEndpoints(region, metadata.AWS.ServiceEndpoints),
+	)
+	if err != nil {
+		return nil, err
+	}
+	client := ec2.New(session)
+
+	bootstrapName := fmt.Sprintf("%s-bootstrap", metadata.InfraID)
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("tag:kubernetes.io/cluster/" + metadata.InfraID),
+			Values: []*string{aws.String("owned")},
+		}, {
+			Name:   aws.String("tag:Name"),
+			Values: []*string{aws.String(bootstrapName), aws.String(metadata.InfraID + "-master-*")},
+		}, {
+			Name:   aws.String("instance-state-name"),
+			Values: []*string{aws.String("pending"), aws.String("running")},
+		}},
+	}
+
+	hosts := &gather.Hosts{}
+	masters := map[string]string{}
+	err = client.DescribeInstancesPagesWithContext(ctx, input, func(results *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range results.Reservations {
+			for _, instance := range reservation.Instances {
+				name := instanceName(instance)
+				if name == bootstrapName {
+					// The bootstrap host is usually reached from outside its VPC.
+					hosts.Bootstrap = aws.StringValue(instance.PublicIpAddress)
+					if hosts.Bootstrap == "" {
+						hosts.Bootstrap = aws.StringValue(instance.PrivateIpAddress)
+					}
+				} else if address := aws.StringValue(instance.PrivateIpAddress); address != "" {
+					masters[name] = address
+				}
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing instances")
+	}
+	hosts.Masters = sortedAddresses(masters)
+	return hosts, nil
+}
+
+func instanceName(instance *ec2.Instance) string {
+	for _, tag := range instance.Tags {
+		if aws.StringValue(tag.Key) == "Name" {
+			return aws.StringValue(tag.Value)
+		}
+	}
+	return ""
+}
+
+// sortedAddresses returns the addresses ordered by host name.
+func sortedAddresses(addresses map[string]string) []string {
+	names := make([]string, 0, len(addresses))
+	for name := range addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sorted := make([]string, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, addresses[name])
+	}
+	return sorted
+}
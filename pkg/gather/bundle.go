@@ -0,0 +1,186 @@
+package gather
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/redact"
+)
+
+// AddToBundle adds the contents of other gzipped tarballs to the log bundle
+// at bundlePath. additions maps a directory, relative to the top-level
+// directory of the bundle, to the tarball placed under it.
+func AddToBundle(bundlePath string, additions map[string]string) error {
+	return WriteBundle(bundlePath, func(tw *tar.Writer) error {
+		top := ""
+		err := copyTarball(tw, bundlePath, func(name string) string {
+			if top == "" {
+				top = strings.SplitN(strings.TrimPrefix(name, "./"), "/", 2)[0]
+			}
+			return name
+		}, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", bundlePath)
+		}
+
+		dirs := make([]string, 0, len(additions))
+		for dir := range additions {
+			dirs = append(dirs, dir)
+		}
+		sort.Strings(dirs)
+		for _, dir := range dirs {
+			err := copyTarball(tw, additions[dir], func(name string) string {
+				name = strings.TrimPrefix(strings.TrimPrefix(name, "."), "/")
+				if name == "" {
+					return ""
+				}
+				return path.Join(top, dir, name)
+			}, nil)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read %s", additions[dir])
+			}
+		}
+		return nil
+	})
+}
+
+// RedactBundle writes the log bundle at srcPath to dstPath with the secrets
+// of its text files replaced by redactor.
+func RedactBundle(srcPath string, dstPath string, redactor *redact.Redactor) error {
+	return WriteBundle(dstPath, func(tw *tar.Writer) error {
+		err := copyTarball(tw, srcPath, func(name string) string { return name }, redactor)
+		return errors.Wrapf(err, "failed to read %s", srcPath)
+	})
+}
+
+// WriteBundle writes the gzipped tarball at bundlePath with the entries
+// written by fn. The file is only replaced once fn succeeds.
+func WriteBundle(bundlePath string, fn func(tw *tar.Writer) error) error {
+	tmpPath := bundlePath + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	if err := fn(tw); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, bundlePath)
+}
+
+// copyTarball copies the entries of the gzipped tarball at path to tw,
+// renamed by rename. Entries renamed to an empty name are skipped. The
+// secrets of regular files are replaced by redactor, if set, and the files
+// it cannot redact are skipped.
+func copyTarball(tw *tar.Writer, path string, rename func(string) string, redactor *redact.Redactor) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		header.Name = rename(header.Name)
+		if header.Name == "" {
+			continue
+		}
+		if redactor != nil && header.Typeflag == tar.TypeReg {
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			data, ok := redactFile(data, redactor)
+			if !ok {
+				logrus.Debugf("Dropping %s from the log bundle, its content cannot be redacted", header.Name)
+				continue
+			}
+			header.Size = int64(len(data))
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if _, err := tw.Write(data); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// redactFile returns data with its secrets replaced by redactor. Gzipped
+// files, e.g. compressed logs or API documents, are redacted uncompressed
+// and compressed again. It returns false for binary content, which cannot
+// be redacted.
+func redactFile(data []byte, redactor *redact.Redactor) ([]byte, bool) {
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		if bytes.IndexByte(data, 0) >= 0 {
+			return nil, false
+		}
+		return redactor.Bytes(data), true
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, false
+	}
+	defer gz.Close()
+	raw, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, false
+	}
+	redacted, ok := redactFile(raw, redactor)
+	if !ok {
+		return nil, false
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(redacted); err != nil {
+		return nil, false
+	}
+	if err := zw.Close(); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
@@ -0,0 +1,162 @@
+package gather
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/redact"
+)
+
+// writeTarball writes a tarball of files, each holding its own name.
+func writeTarball(t *testing.T, path string, files []string) {
+	contents := make([]string, 0, 2*len(files))
+	for _, name := range files {
+		contents = append(contents, name, name)
+	}
+	writeTarballContents(t, path, contents...)
+}
+
+// writeTarballContents writes a tarball of files given as name and content
+// pairs.
+func writeTarballContents(t *testing.T, path string, contents ...string) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for i := 0; i < len(contents); i += 2 {
+		name, data := contents[i], contents[i+1]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTarball(t *testing.T, path string) map[string]string {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(data)
+	}
+	return files
+}
+
+func TestAddToBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gather-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundle := filepath.Join(dir, "log-bundle-1.tar.gz")
+	writeTarball(t, bundle, []string{"log-bundle-1/bootstrap/journals/kubelet.log"})
+	master0 := HostBundlePath(dir, "10.0.0.6")
+	writeTarball(t, master0, []string{"./journals/kubelet.log", "./failed-units.txt"})
+	master1 := HostBundlePath(dir, "10.0.0.7")
+	writeTarball(t, master1, []string{"./journals/crio.log"})
+
+	err = AddToBundle(bundle, map[string]string{
+		"control-plane/10.0.0.6": master0,
+		"control-plane/10.0.0.7": master1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"log-bundle-1/bootstrap/journals/kubelet.log":              "log-bundle-1/bootstrap/journals/kubelet.log",
+		"log-bundle-1/control-plane/10.0.0.6/journals/kubelet.log": "./journals/kubelet.log",
+		"log-bundle-1/control-plane/10.0.0.6/failed-units.txt":     "./failed-units.txt",
+		"log-bundle-1/control-plane/10.0.0.7/journals/crio.log":    "./journals/crio.log",
+	}, readTarball(t, bundle))
+	_, err = os.Stat(bundle + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRedactBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gather-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "raw.tar.gz")
+	writeTarballContents(t, src,
+		"log-bundle-1/bootstrap/journals/release-image.log", `pulling with {"auths":{"quay.io":{"auth":"dXNlcjpwYXNz"}}}`,
+		"log-bundle-1/bootstrap/journals/kubelet.log", "Authorization: Bearer abc.def\nmore",
+		"log-bundle-1/bootstrap/binary", "password: secret\x00",
+		"log-bundle-1/resources/openapi.json.gz", gzipString(t, `{"token":"Bearer abc.def"}`),
+		"log-bundle-1/resources/binary.gz", gzipString(t, "password: secret\x00"),
+	)
+	redactor, err := redact.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "log-bundle-1.tar.gz")
+	assert.NoError(t, RedactBundle(src, dst, redactor))
+	files := readTarball(t, dst)
+	openapi := files["log-bundle-1/resources/openapi.json.gz"]
+	delete(files, "log-bundle-1/resources/openapi.json.gz")
+	assert.Equal(t, map[string]string{
+		"log-bundle-1/bootstrap/journals/release-image.log": `pulling with {"auths":{"quay.io":{"auth":"REDACTED"}}}`,
+		"log-bundle-1/bootstrap/journals/kubelet.log":       "Authorization: Bearer REDACTED\nmore",
+	}, files)
+	assert.Equal(t, `{"token":"REDACTED"}`, gunzipString(t, openapi))
+}
+
+func gzipString(t *testing.T, data string) string {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func gunzipString(t *testing.T, data string) string {
+	zr, err := gzip.NewReader(bytes.NewReader([]byte(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(raw)
+}
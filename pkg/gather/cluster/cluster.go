@@ -0,0 +1,305 @@
+// Package cluster gathers debugging data from a running cluster through its
+// API server, for install failures that happen after bootstrap.
+package cluster
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	cryptossh "golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/installer/pkg/gather"
+	"github.com/openshift/installer/pkg/gather/ssh"
+	"github.com/openshift/installer/pkg/redact"
+)
+
+const (
+	// podLogTailLines is the number of lines kept from each container log.
+	podLogTailLines = 1000
+
+	// journalCommand prints the journal of a unit since the last boot.
+	journalCommand = "sudo journalctl --no-pager --boot --unit=%s"
+)
+
+// journalUnits are the units whose journal is pulled from each node.
+var journalUnits = []string{"kubelet", "crio", "machine-config-daemon-firstboot"}
+
+// listedResource is a cluster-scoped or namespaced resource that is saved
+// as a whole.
+type listedResource struct {
+	file      string
+	resource  schema.GroupVersionResource
+	namespace string
+}
+
+var listedResources = []listedResource{{
+	file:     "clusterversion.json",
+	resource: schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"},
+}, {
+	file:     "clusteroperators.json",
+	resource: schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"},
+}, {
+	file:     "machineconfigpools.json",
+	resource: schema.GroupVersionResource{Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineconfigpools"},
+}, {
+	file:      "machines.json",
+	resource:  schema.GroupVersionResource{Group: "machine.openshift.io", Version: "v1beta1", Resource: "machines"},
+	namespace: "openshift-machine-api",
+}, {
+	file:      "machinesets.json",
+	resource:  schema.GroupVersionResource{Group: "machine.openshift.io", Version: "v1beta1", Resource: "machinesets"},
+	namespace: "openshift-machine-api",
+}}
+
+// Gatherer collects the state of a cluster into a log bundle.
+type Gatherer struct {
+	Logger logrus.FieldLogger
+
+	// Config is the admin client configuration of the cluster.
+	Config *rest.Config
+
+	// Runner connects to the nodes, in parallel, to pull their journals.
+	// If nil, the journals are not pulled.
+	Runner *ssh.Runner
+
+	// Redactor replaces the secrets in the files before they are written to
+	// the bundle. If nil, the files are written as they are.
+	Redactor *redact.Redactor
+}
+
+// Gather writes a gzipped tarball of the cluster state to path. Every item
+// is gathered independently; the bundle holds whatever could be collected
+// and the returned error lists what could not.
+func (g *Gatherer) Gather(ctx context.Context, path string, id string) error {
+	client, err := kubernetes.NewForConfig(g.Config)
+	if err != nil {
+		return errors.Wrap(err, "creating a Kubernetes client")
+	}
+	dynamicClient, err := dynamic.NewForConfig(g.Config)
+	if err != nil {
+		return errors.Wrap(err, "creating a dynamic client")
+	}
+
+	var errs []error
+	err = gather.WriteBundle(path, func(tw *tar.Writer) error {
+		b := newBundle(tw, fmt.Sprintf("cluster-bundle-%s", id))
+		b.redactor = g.Redactor
+
+		for _, listed := range listedResources {
+			if err := g.gatherList(ctx, b, dynamicClient, listed); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "listing nodes"))
+		} else if err := b.addJSON("nodes.json", nodes); err != nil {
+			errs = append(errs, err)
+		}
+		if err := g.gatherEvents(ctx, b, client); err != nil {
+			errs = append(errs, err)
+		}
+		if err := g.gatherPodLogs(ctx, b, client); err != nil {
+			errs = append(errs, err)
+		}
+		if g.Runner != nil && nodes != nil {
+			if err := g.gatherJournals(ctx, b, nodes.Items); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to write log bundle")
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (g *Gatherer) gatherList(ctx context.Context, b *bundle, client dynamic.Interface, listed listedResource) error {
+	g.Logger.Debugf("Gathering %s", listed.resource.Resource)
+	list, err := client.Resource(listed.resource).Namespace(listed.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "listing %s", listed.resource.Resource)
+	}
+	return b.addJSON(listed.file, list)
+}
+
+func (g *Gatherer) gatherEvents(ctx context.Context, b *bundle, client kubernetes.Interface) error {
+	g.Logger.Debug("Gathering events")
+	events, err := client.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing events")
+	}
+	return b.addJSON("events.json", events)
+}
+
+// gatherPodLogs saves the failing pods and the logs of their containers,
+// including the previous run of the containers that restarted.
+func (g *Gatherer) gatherPodLogs(ctx context.Context, b *bundle, client kubernetes.Interface) error {
+	g.Logger.Debug("Gathering failing pods")
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "listing pods")
+	}
+
+	failing := &corev1.PodList{}
+	var errs []error
+	for _, pod := range pods.Items {
+		if !podFailing(&pod) {
+			continue
+		}
+		failing.Items = append(failing.Items, pod)
+		dir := path.Join("pods", pod.Namespace, pod.Name)
+		for _, status := range pod.Status.ContainerStatuses {
+			if err := g.gatherContainerLog(ctx, b, client, &pod, status.Name, false, path.Join(dir, status.Name+".log")); err != nil {
+				errs = append(errs, err)
+			}
+			if status.RestartCount > 0 {
+				if err := g.gatherContainerLog(ctx, b, client, &pod, status.Name, true, path.Join(dir, status.Name+".previous.log")); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	if err := b.addJSON("pods.json", failing); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (g *Gatherer) gatherContainerLog(ctx context.Context, b *bundle, client kubernetes.Interface, pod *corev1.Pod, container string, previous bool, name string) error {
+	tailLines := int64(podLogTailLines)
+	data, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "getting logs of %s/%s container %s", pod.Namespace, pod.Name, container)
+	}
+	return b.add(name, data)
+}
+
+// podFailing reports whether pod is not running, has a container that is
+// not ready or a container that restarted. Completed pods are not failing.
+func podFailing(pod *corev1.Pod) bool {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return false
+	case corev1.PodRunning:
+	default:
+		return true
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if !status.Ready || status.RestartCount > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// gatherJournals pulls the journals of journalUnits from the nodes over
+// SSH, in parallel. A node that cannot be reached does not stop the others.
+func (g *Gatherer) gatherJournals(ctx context.Context, b *bundle, nodes []corev1.Node) error {
+	var errs []error
+	var hosts []string
+	names := map[string]string{}
+	for _, node := range nodes {
+		address := gather.NodeAddress(&node)
+		if address == "" {
+			errs = append(errs, errors.Errorf("no address for node %s", node.Name))
+			continue
+		}
+		hosts = append(hosts, address)
+		names[address] = node.Name
+	}
+
+	var mu sync.Mutex
+	journals := map[string][]byte{}
+	results := g.Runner.Run(ctx, hosts, func(client *cryptossh.Client, host string) error {
+		g.Logger.WithField("node", names[host]).Debug("Gathering journals")
+		var unitErrs []error
+		for _, unit := range journalUnits {
+			data, err := ssh.Output(client, fmt.Sprintf(journalCommand, unit))
+			if err != nil {
+				unitErrs = append(unitErrs, errors.Wrapf(err, "getting the %s journal", unit))
+				continue
+			}
+			mu.Lock()
+			journals[path.Join("journals", names[host], unit+".log")] = data
+			mu.Unlock()
+		}
+		return utilerrors.NewAggregate(unitErrs)
+	})
+	for _, result := range results {
+		if result.Err != nil {
+			g.Logger.WithField("node", names[result.Host]).WithError(result.Err).Warn("Failed to gather the journals of the node")
+			errs = append(errs, errors.Wrapf(result.Err, "node %s", names[result.Host]))
+		}
+		for _, unit := range journalUnits {
+			name := path.Join("journals", names[result.Host], unit+".log")
+			if data, ok := journals[name]; ok {
+				if err := b.add(name, data); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// bundle writes files into a tarball under a common directory.
+type bundle struct {
+	tar     *tar.Writer
+	dir     string
+	modTime time.Time
+
+	redactor *redact.Redactor
+}
+
+func newBundle(tw *tar.Writer, dir string) *bundle {
+	return &bundle{
+		tar:     tw,
+		dir:     dir,
+		modTime: time.Now(),
+	}
+}
+
+func (b *bundle) add(name string, data []byte) error {
+	data = b.redactor.Bytes(data)
+	err := b.tar.WriteHeader(&tar.Header{
+		Name:    path.Join(b.dir, name),
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: b.modTime,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to add %s to log bundle", name)
+	}
+	if _, err := b.tar.Write(data); err != nil {
+		return errors.Wrapf(err, "failed to add %s to log bundle", name)
+	}
+	return nil
+}
+
+func (b *bundle) addJSON(name string, obj interface{}) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", name)
+	}
+	return b.add(name, data)
+}
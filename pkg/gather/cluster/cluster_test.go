@@ -0,0 +1,100 @@
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/installer/pkg/gather"
+)
+
+func TestPodFailing(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   corev1.PodStatus
+		expected bool
+	}{{
+		name: "running and ready",
+		status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "etcd", Ready: true}},
+		},
+		expected: false,
+	}, {
+		name:     "succeeded",
+		status:   corev1.PodStatus{Phase: corev1.PodSucceeded},
+		expected: false,
+	}, {
+		name:     "pending",
+		status:   corev1.PodStatus{Phase: corev1.PodPending},
+		expected: true,
+	}, {
+		name: "not ready",
+		status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "etcd", Ready: false}},
+		},
+		expected: true,
+	}, {
+		name: "restarted",
+		status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "kube-apiserver", Ready: true, RestartCount: 3}},
+		},
+		expected: true,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, podFailing(&corev1.Pod{Status: tc.status}))
+		})
+	}
+}
+
+func TestBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cluster-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bundlePath := filepath.Join(dir, "cluster-bundle-1.tar.gz")
+	err = gather.WriteBundle(bundlePath, func(tw *tar.Writer) error {
+		b := newBundle(tw, "cluster-bundle-1")
+		assert.NoError(t, b.add("journals/master-0/kubelet.log", []byte("started\n")))
+		assert.NoError(t, b.addJSON("clusterversion.json", map[string]string{"kind": "ClusterVersionList"}))
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	file, err := os.Open(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		data, err := ioutil.ReadAll(tr)
+		assert.NoError(t, err)
+		files[header.Name] = string(data)
+	}
+	assert.Equal(t, map[string]string{
+		"cluster-bundle-1/journals/master-0/kubelet.log": "started\n",
+		"cluster-bundle-1/clusterversion.json":           "{\n  \"kind\": \"ClusterVersionList\"\n}",
+	}, files)
+}
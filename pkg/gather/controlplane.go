@@ -0,0 +1,70 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"github.com/openshift/installer/pkg/gather/ssh"
+)
+
+// mastersGatherScript is the name of the script run on each control plane
+// host, in the home directory of the user.
+const mastersGatherScript = "installer-masters-gather.sh"
+
+// ControlPlaneGatherer pulls the logs of the control plane hosts directly,
+// in parallel.
+type ControlPlaneGatherer struct {
+	Logger logrus.FieldLogger
+
+	// Runner connects to the hosts.
+	Runner *ssh.Runner
+
+	// Script is the content of installer-masters-gather.sh.
+	Script []byte
+}
+
+// Gather runs the gather script on every host and writes the artifacts of
+// each one to <dir>/<host>.tar.gz. The results tell which hosts failed and
+// why.
+func (g *ControlPlaneGatherer) Gather(ctx context.Context, hosts []string, gatherID string, dir string) []ssh.Result {
+	results := g.Runner.Run(ctx, hosts, func(client *cryptossh.Client, host string) error {
+		logger := g.Logger.WithField("host", host)
+		logger.Debug("Gathering control plane host logs")
+		if err := ssh.PushFile(client, g.Script, mastersGatherScript, 0755); err != nil {
+			return errors.Wrap(err, "failed to copy the gather script")
+		}
+		if err := ssh.Run(client, fmt.Sprintf("sudo ./%s --id '%s'", mastersGatherScript, gatherID)); err != nil {
+			return errors.Wrap(err, "failed to run the gather script")
+		}
+
+		file, err := os.Create(HostBundlePath(dir, host))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := ssh.RunTo(client, fmt.Sprintf("sudo tar cz -C /tmp/artifacts-%s .", gatherID), file); err != nil {
+			return errors.Wrap(err, "failed to pull the artifacts")
+		}
+		return file.Close()
+	})
+
+	for _, result := range results {
+		if result.Err != nil {
+			g.Logger.WithError(result.Err).Warnf("Failed to gather logs from control plane host %s", result.Host)
+			os.Remove(HostBundlePath(dir, result.Host))
+		}
+	}
+	return results
+}
+
+// HostBundlePath returns the path of the artifacts of host written by
+// ControlPlaneGatherer.Gather.
+func HostBundlePath(dir string, host string) string {
+	return filepath.Join(dir, host+".tar.gz")
+}
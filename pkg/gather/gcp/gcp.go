@@ -0,0 +1,82 @@
+// Package gcp finds the hosts of a GCP cluster through the Compute API.
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+
+	gcpconfig "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/gather"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/version"
+)
+
+// Source returns a source listing the running bootstrap and control plane
+// instances of the cluster described by metadata.
+func Source(metadata *types.ClusterMetadata) gather.Source {
+	return gather.Source{
+		Name: "the Compute API",
+		Hosts: func(ctx context.Context) (*gather.Hosts, error) {
+			return hosts(ctx, metadata)
+		},
+	}
+}
+
+func hosts(ctx context.Context, metadata *types.ClusterMetadata) (*gather.Hosts, error) {
+	ssn, err := gcpconfig.GetSession(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get session")
+	}
+	svc, err := compute.NewService(ctx,
+		option.WithCredentials(ssn.Credentials),
+		option.WithUserAgent(fmt.Sprintf("OpenShift/4.x Gather/%s", version.Raw)),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create compute service")
+	}
+
+	bootstrapName := fmt.Sprintf("%s-bootstrap", metadata.InfraID)
+	hosts := &gather.Hosts{}
+	masters := map[string]string{}
+	req := svc.Instances.AggregatedList(metadata.GCP.ProjectID).
+		Filter(fmt.Sprintf(`name eq "%s-(bootstrap|master-.*)"`, metadata.InfraID)).
+		Fields("items/*/instances(name,status,networkInterfaces),nextPageToken")
+	err = req.Pages(ctx, func(list *compute.InstanceAggregatedList) error {
+		for _, scopedList := range list.Items {
+			for _, instance := range scopedList.Instances {
+				if instance.Status != "RUNNING" || len(instance.NetworkInterfaces) == 0 {
+					continue
+				}
+				nic := instance.NetworkInterfaces[0]
+				if instance.Name == bootstrapName {
+					// The bootstrap host is usually reached from outside its network.
+					hosts.Bootstrap = nic.NetworkIP
+					if len(nic.AccessConfigs) > 0 && nic.AccessConfigs[0].NatIP != "" {
+						hosts.Bootstrap = nic.AccessConfigs[0].NatIP
+					}
+				} else if nic.NetworkIP != "" {
+					masters[instance.Name] = nic.NetworkIP
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing instances")
+	}
+
+	names := make([]string, 0, len(masters))
+	for name := range masters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hosts.Masters = append(hosts.Masters, masters[name])
+	}
+	return hosts, nil
+}
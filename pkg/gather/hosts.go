@@ -0,0 +1,115 @@
+// Package gather finds the hosts of a cluster and collects debugging data
+// from them.
+package gather
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Hosts holds the addresses of the bootstrap and control plane hosts of a
+// cluster, as found by a single source.
+type Hosts struct {
+	// Bootstrap is the address of the bootstrap host, if known.
+	Bootstrap string `json:"bootstrap,omitempty"`
+
+	// Masters are the addresses of the control plane hosts.
+	Masters []string `json:"masters,omitempty"`
+}
+
+// Source finds the hosts of a cluster from one kind of data, e.g. the
+// Terraform state or the cloud API.
+type Source struct {
+	// Name describes the source in log messages.
+	Name string
+
+	// Hosts returns the hosts known to the source. Addresses the source
+	// cannot provide are left empty.
+	Hosts func(ctx context.Context) (*Hosts, error)
+}
+
+// Discovery is the outcome of querying a list of sources.
+type Discovery struct {
+	// Bootstraps holds every address found for the bootstrap host, in the
+	// order of the sources, so that the next one can be tried when a host
+	// is unreachable.
+	Bootstraps []string
+
+	// Masters are the control plane addresses of the first source that
+	// found any.
+	Masters []string
+}
+
+// Discover queries sources in order. A source that fails is logged and
+// skipped, so that the remaining sources can still provide the addresses.
+func Discover(ctx context.Context, logger logrus.FieldLogger, sources []Source) (*Discovery, error) {
+	discovery := &Discovery{}
+	seen := map[string]bool{}
+	for _, source := range sources {
+		hosts, err := source.Hosts(ctx)
+		if err != nil {
+			logger.WithError(err).Warnf("Failed to find hosts from %s", source.Name)
+			continue
+		}
+		if hosts == nil {
+			continue
+		}
+		if hosts.Bootstrap != "" && !seen[hosts.Bootstrap] {
+			logger.Debugf("Found bootstrap host %s from %s", hosts.Bootstrap, source.Name)
+			seen[hosts.Bootstrap] = true
+			discovery.Bootstraps = append(discovery.Bootstraps, hosts.Bootstrap)
+		}
+		if len(discovery.Masters) == 0 && len(hosts.Masters) > 0 {
+			logger.Debugf("Found control plane hosts %v from %s", hosts.Masters, source.Name)
+			discovery.Masters = hosts.Masters
+		}
+	}
+	if len(discovery.Bootstraps) == 0 && len(discovery.Masters) == 0 {
+		return nil, errors.New("no bootstrap or control plane host address found")
+	}
+	return discovery, nil
+}
+
+// StaticSource returns a source for addresses given by the user.
+func StaticSource(name string, bootstrap string, masters []string) Source {
+	return Source{
+		Name: name,
+		Hosts: func(context.Context) (*Hosts, error) {
+			return &Hosts{Bootstrap: bootstrap, Masters: masters}, nil
+		},
+	}
+}
+
+// FileSource returns a source reading a host inventory file, written in
+// YAML or JSON:
+//
+//	bootstrap: 10.0.0.5
+//	masters:
+//	- 10.0.0.6
+//	- 10.0.0.7
+//	- 10.0.0.8
+func FileSource(path string) Source {
+	return Source{
+		Name: path,
+		Hosts: func(context.Context) (*Hosts, error) {
+			return LoadHosts(path)
+		},
+	}
+}
+
+// LoadHosts reads a host inventory file.
+func LoadHosts(path string) (*Hosts, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read host inventory")
+	}
+	hosts := &Hosts{}
+	if err := yaml.UnmarshalStrict(data, hosts, yaml.DisallowUnknownFields); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse host inventory %q", path)
+	}
+	return hosts, nil
+}
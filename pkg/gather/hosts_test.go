@@ -0,0 +1,107 @@
+package gather
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	failing := Source{
+		Name: "failing",
+		Hosts: func(context.Context) (*Hosts, error) {
+			return nil, errors.New("unreachable")
+		},
+	}
+	cases := []struct {
+		name     string
+		sources  []Source
+		expected *Discovery
+		err      string
+	}{{
+		name: "first source with masters wins",
+		sources: []Source{
+			StaticSource("flags", "", []string{"10.0.0.6"}),
+			StaticSource("state", "10.0.0.5", []string{"10.0.0.7"}),
+		},
+		expected: &Discovery{Bootstraps: []string{"10.0.0.5"}, Masters: []string{"10.0.0.6"}},
+	}, {
+		name: "bootstrap addresses are kept in order without duplicates",
+		sources: []Source{
+			StaticSource("state", "203.0.113.5", nil),
+			StaticSource("api", "10.0.0.5", nil),
+			StaticSource("file", "203.0.113.5", []string{"10.0.0.6"}),
+		},
+		expected: &Discovery{Bootstraps: []string{"203.0.113.5", "10.0.0.5"}, Masters: []string{"10.0.0.6"}},
+	}, {
+		name:     "failing source is skipped",
+		sources:  []Source{failing, StaticSource("file", "10.0.0.5", []string{"10.0.0.6"})},
+		expected: &Discovery{Bootstraps: []string{"10.0.0.5"}, Masters: []string{"10.0.0.6"}},
+	}, {
+		name:    "nothing found",
+		sources: []Source{failing, StaticSource("kubeconfig", "", nil)},
+		err:     "no bootstrap or control plane host address found",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			discovery, err := Discover(context.Background(), logrus.New(), tc.sources)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, discovery)
+		})
+	}
+}
+
+func TestLoadHosts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gather-hosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		name     string
+		data     string
+		expected *Hosts
+		err      string
+	}{{
+		name: "yaml",
+		data: "bootstrap: 10.0.0.5\nmasters:\n- 10.0.0.6\n- 10.0.0.7\n",
+		expected: &Hosts{
+			Bootstrap: "10.0.0.5",
+			Masters:   []string{"10.0.0.6", "10.0.0.7"},
+		},
+	}, {
+		name:     "json",
+		data:     `{"masters": ["master-0.example.com"]}`,
+		expected: &Hosts{Masters: []string{"master-0.example.com"}},
+	}, {
+		name: "unknown field",
+		data: "bootstrap: 10.0.0.5\nworkers:\n- 10.0.0.9\n",
+		err:  `failed to parse host inventory ".*": error unmarshaling JSON: .*unknown field "workers"`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "hosts.yaml")
+			if err := ioutil.WriteFile(path, []byte(tc.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+			hosts, err := LoadHosts(path)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, hosts)
+		})
+	}
+}
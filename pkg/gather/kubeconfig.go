@@ -0,0 +1,65 @@
+package gather
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// masterNodeSelector selects the control plane nodes.
+const masterNodeSelector = "node-role.kubernetes.io/master"
+
+// kubeconfigTimeout bounds the node list, since the API server is often
+// unavailable when gathering.
+const kubeconfigTimeout = 30 * time.Second
+
+// KubeconfigSource returns a source listing the control plane nodes through
+// the API server of the kubeconfig at path. It cannot find the bootstrap
+// host, which is not a node.
+func KubeconfigSource(path string) Source {
+	return Source{
+		Name: "the API server node list",
+		Hosts: func(ctx context.Context) (*Hosts, error) {
+			config, err := clientcmd.BuildConfigFromFlags("", path)
+			if err != nil {
+				return nil, errors.Wrap(err, "loading kubeconfig")
+			}
+			client, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return nil, errors.Wrap(err, "creating a Kubernetes client")
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, kubeconfigTimeout)
+			defer cancel()
+			nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: masterNodeSelector})
+			if err != nil {
+				return nil, errors.Wrap(err, "listing control plane nodes")
+			}
+			hosts := &Hosts{}
+			for _, node := range nodes.Items {
+				if address := NodeAddress(&node); address != "" {
+					hosts.Masters = append(hosts.Masters, address)
+				}
+			}
+			return hosts, nil
+		},
+	}
+}
+
+// NodeAddress returns the internal IP of node, falling back to its external
+// IP and its hostname.
+func NodeAddress(node *corev1.Node) string {
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeInternalIP, corev1.NodeExternalIP, corev1.NodeHostName} {
+		for _, address := range node.Status.Addresses {
+			if address.Type == addressType {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
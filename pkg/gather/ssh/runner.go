@@ -0,0 +1,114 @@
+package ssh
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// defaultConcurrency is the number of hosts a Runner works on in parallel
+// when Runner.Concurrency is not set.
+const defaultConcurrency = 5
+
+// Result is the outcome of running on a single host.
+type Result struct {
+	// Host is the address of the host.
+	Host string
+
+	// Err is the error that stopped the work on the host, if any.
+	Err error
+
+	// Duration is the time spent on the host.
+	Duration time.Duration
+}
+
+// Runner runs a function against many hosts in parallel, each with its own
+// SSH connection.
+type Runner struct {
+	ClientOptions
+
+	// Port is the SSH port of the hosts.
+	Port int
+
+	// Concurrency is the maximum number of hosts worked on in parallel.
+	// If zero, defaultConcurrency is used.
+	Concurrency int
+
+	// HostTimeout bounds the work on each host, including connecting to
+	// it. If zero, only the connection is bounded.
+	HostTimeout time.Duration
+}
+
+// Run connects to every host and calls fn with the connection. A host that
+// fails, or runs out of time, does not stop the others. The results are in
+// the order of hosts.
+func (r *Runner) Run(ctx context.Context, hosts []string, fn func(client *ssh.Client, host string) error) []Result {
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	results := make([]Result, len(hosts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			err := r.runHost(ctx, host, fn)
+			results[i] = Result{Host: host, Err: err, Duration: time.Since(start)}
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+func (r *Runner) runHost(ctx context.Context, host string, fn func(client *ssh.Client, host string) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.HostTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.HostTimeout)
+		defer cancel()
+	}
+
+	options := r.ClientOptions
+	if deadline, ok := ctx.Deadline(); ok && (options.Timeout == 0 || time.Until(deadline) < options.Timeout) {
+		options.Timeout = time.Until(deadline)
+	}
+	client, err := NewClientWithOptions(net.JoinHostPort(host, strconv.Itoa(r.Port)), &options)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect")
+	}
+
+	// Closing the connection interrupts fn when the host runs out of time.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			client.Close()
+		case <-done:
+			client.Close()
+		}
+	}()
+
+	err = fn(client, host)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if ctxErr == context.DeadlineExceeded && r.HostTimeout > 0 {
+			return errors.Errorf("timed out after %s", r.HostTimeout)
+		}
+		return ctxErr
+	}
+	return err
+}
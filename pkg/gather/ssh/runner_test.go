@@ -0,0 +1,92 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+// writeKey writes a private key for the runner to load, since no agent is
+// used in tests.
+func writeKey(t *testing.T, dir string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "id_ecdsa")
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// closedPort returns a local port with nothing listening on it.
+func closedPort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+func TestRunnerReportsEachHost(t *testing.T) {
+	if authSock, ok := os.LookupEnv("SSH_AUTH_SOCK"); ok {
+		os.Unsetenv("SSH_AUTH_SOCK")
+		defer os.Setenv("SSH_AUTH_SOCK", authSock)
+	}
+	dir, err := ioutil.TempDir("", "ssh-runner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	runner := &Runner{
+		ClientOptions: ClientOptions{User: "core", Keys: []string{writeKey(t, dir)}},
+		Port:          closedPort(t),
+		Concurrency:   2,
+	}
+	hosts := []string{"127.0.0.1", "127.0.0.1", "127.0.0.1"}
+	called := false
+	results := runner.Run(context.Background(), hosts, func(*ssh.Client, string) error {
+		called = true
+		return nil
+	})
+
+	assert.False(t, called)
+	if assert.Len(t, results, len(hosts)) {
+		for i, result := range results {
+			assert.Equal(t, hosts[i], result.Host)
+			assert.Regexp(t, "^failed to connect: .*connection refused", result.Err)
+		}
+	}
+}
+
+func TestRunnerStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runner := &Runner{Port: 22}
+	results := runner.Run(ctx, []string{"10.0.0.6"}, func(*ssh.Client, string) error {
+		return nil
+	})
+	assert.Equal(t, []Result{{Host: "10.0.0.6", Err: context.Canceled, Duration: results[0].Duration}}, results)
+}
@@ -2,10 +2,13 @@
 package ssh
 
 import (
+	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/openshift/installer/pkg/lineprinter"
 	"github.com/pkg/errors"
@@ -13,29 +16,83 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
 
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
+// dialTimeout is the maximum time to establish a connection to a host.
+const dialTimeout = 30 * time.Second
+
+// ClientOptions configures the connection made by NewClientWithOptions.
+type ClientOptions struct {
+	// User is the user to log in as.
+	User string
+
+	// Keys are the paths of the private keys used for authentication. If
+	// empty, the keys are loaded from the user's environment.
+	Keys []string
+
+	// Bastion is the host:port of a jump host through which the
+	// connection is made. If empty, the host is reached directly.
+	Bastion string
+
+	// Timeout bounds establishing each connection. If zero, dialTimeout
+	// is used.
+	Timeout time.Duration
+
+	// KnownHosts is the path of a known_hosts file used to verify the host
+	// keys, of the bastion as well. If empty, host keys are not verified.
+	KnownHosts string
+}
+
 // NewClient creates a new SSH client which can be used to SSH to address using user and the keys.
 //
 // if keys list is empty, it tries to load the keys from the user's environment.
 func NewClient(user, address string, keys []string) (*ssh.Client, error) {
-	ag, agentType, err := getAgent(keys)
+	return NewClientWithOptions(address, &ClientOptions{User: user, Keys: keys})
+}
+
+// NewClientWithOptions creates a new SSH client connected to address as
+// configured by options. The agent holding the keys is forwarded to the
+// host.
+func NewClientWithOptions(address string, options *ClientOptions) (*ssh.Client, error) {
+	ag, agentType, err := getAgent(options.Keys)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to initialize the SSH agent")
 	}
 
-	client, err := ssh.Dial("tcp", address, &ssh.ClientConfig{
-		User: user,
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if options.KnownHosts != "" {
+		hostKeyCallback, err = knownhosts.New(options.KnownHosts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load known hosts")
+		}
+	}
+	timeout := options.Timeout
+	if timeout == 0 {
+		// Bound the connection so that the next address of a host can be
+		// tried when one is unreachable.
+		timeout = dialTimeout
+	}
+	config := &ssh.ClientConfig{
+		User: options.User,
 		Auth: []ssh.AuthMethod{
 			// Use a callback rather than PublicKeys
 			// so we only consult the agent once the remote server
 			// wants it.
 			ssh.PublicKeysCallback(ag.Signers),
 		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	})
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         timeout,
+	}
+
+	var client *ssh.Client
+	if options.Bastion == "" {
+		client, err = ssh.Dial("tcp", address, config)
+	} else {
+		client, err = dialThroughBastion(address, options.Bastion, config)
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "ssh: handshake failed: ssh: unable to authenticate") {
 			if agentType == "agent" {
@@ -46,11 +103,55 @@ func NewClient(user, address string, keys []string) (*ssh.Client, error) {
 		return nil, err
 	}
 	if err := agent.ForwardToAgent(client, ag); err != nil {
+		client.Close()
 		return nil, errors.Wrap(err, "failed to forward agent")
 	}
 	return client, nil
 }
 
+// dialThroughBastion connects to address through an SSH connection to
+// bastion. The bastion connection is closed with the returned client.
+func dialThroughBastion(address, bastion string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	bastionClient, err := ssh.Dial("tcp", bastion, config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to bastion %s", bastion)
+	}
+
+	type dialResult struct {
+		conn net.Conn
+		err  error
+	}
+	// The SSH client has no dial timeout of its own.
+	dialed := make(chan dialResult, 1)
+	go func() {
+		conn, err := bastionClient.Dial("tcp", address)
+		dialed <- dialResult{conn: conn, err: err}
+	}()
+	var result dialResult
+	select {
+	case result = <-dialed:
+	case <-time.After(config.Timeout):
+		result.err = errors.Errorf("timed out after %s", config.Timeout)
+	}
+	if result.err != nil {
+		bastionClient.Close()
+		return nil, errors.Wrapf(result.err, "failed to reach %s through bastion %s", address, bastion)
+	}
+
+	conn, chans, reqs, err := ssh.NewClientConn(result.conn, address, config)
+	if err != nil {
+		result.conn.Close()
+		bastionClient.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(conn, chans, reqs)
+	go func() {
+		client.Wait()
+		bastionClient.Close()
+	}()
+	return client, nil
+}
+
 // Run uses an SSH client to execute commands.
 func Run(client *ssh.Client, command string) error {
 	sess, err := client.NewSession()
@@ -69,6 +170,58 @@ func Run(client *ssh.Client, command string) error {
 	return sess.Run(command)
 }
 
+// Output uses an SSH client to execute command and returns its standard
+// output. The standard error is logged at debug level.
+func Output(client *ssh.Client, command string) ([]byte, error) {
+	sess, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer sess.Close()
+
+	debugW := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Debug}).Print}
+	defer debugW.Close()
+	sess.Stderr = debugW
+	return sess.Output(command)
+}
+
+// RunTo uses an SSH client to execute command and copies its standard output
+// to w. The standard error is logged at debug level.
+func RunTo(client *ssh.Client, command string, w io.Writer) error {
+	sess, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	debugW := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Debug}).Print}
+	defer debugW.Close()
+	sess.Stdout = w
+	sess.Stderr = debugW
+	return sess.Run(command)
+}
+
+// PushFile uploads data to remotePath on the remote server using SSH
+// connection, with the given mode.
+func PushFile(client *ssh.Client, data []byte, remotePath string, mode os.FileMode) error {
+	sc, err := sftp.NewClient(client)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize the sftp client")
+	}
+	defer sc.Close()
+
+	rFile, err := sc.Create(remotePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create remote file")
+	}
+	defer rFile.Close()
+
+	if _, err := rFile.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write remote file")
+	}
+	return rFile.Chmod(mode)
+}
+
 // PullFileTo downloads the file from remote server using SSH connection and writes to localPath.
 func PullFileTo(client *ssh.Client, remotePath, localPath string) error {
 	sc, err := sftp.NewClient(client)
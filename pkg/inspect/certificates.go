@@ -0,0 +1,393 @@
+// Package inspect reports on the assets of an asset directory.
+package inspect
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	igntypes "github.com/coreos/ignition/v2/config/v3_1/types"
+	"github.com/pkg/errors"
+	"github.com/vincent-petithory/dataurl"
+
+	"github.com/openshift/installer/pkg/asset"
+	assetstore "github.com/openshift/installer/pkg/asset/store"
+)
+
+const (
+	// SourceState is the Source of the certificates of the state file.
+	SourceState = "state"
+
+	// SourceDisk is the Source of the certificates of the files of the
+	// asset directory.
+	SourceDisk = "disk"
+
+	// FormatText prints the certificates as blocks of aligned fields.
+	FormatText = "text"
+
+	// FormatJSON prints the certificates as a JSON array.
+	FormatJSON = "json"
+
+	// chainValid is the Chain of the certificates with a valid chain.
+	chainValid = "valid"
+)
+
+// Status is the validity of a certificate at the time of the inspection.
+type Status string
+
+const (
+	// StatusValid certificates are valid beyond the expiry window.
+	StatusValid Status = "Valid"
+	// StatusExpiring certificates expire within the expiry window.
+	StatusExpiring Status = "Expiring"
+	// StatusExpired certificates are no longer valid.
+	StatusExpired Status = "Expired"
+	// StatusNotYetValid certificates are not valid yet.
+	StatusNotYetValid Status = "NotYetValid"
+)
+
+// Certificate is a certificate found in the assets.
+type Certificate struct {
+	// Source is SourceState or SourceDisk.
+	Source string `json:"source"`
+
+	// File is the file holding the certificate, relative to the asset
+	// directory. The files embedded in an ignition config are named
+	// <config>:<path>.
+	File string `json:"file"`
+
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	SANs      []string  `json:"sans,omitempty"`
+	KeyType   string    `json:"keyType"`
+	IsCA      bool      `json:"isCA"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+
+	// Chain is "valid" when the certificate chains up to a self-signed
+	// certificate authority of the assets, or else why it does not.
+	Chain string `json:"chain"`
+
+	Status Status `json:"status"`
+
+	cert *x509.Certificate
+}
+
+// Certificates returns the certificates of the state file, of the tls
+// directory and of the ignition configs of the asset directory dir, with
+// their status at now. The certificates expiring within expiring of now are
+// StatusExpiring.
+func Certificates(dir string, now time.Time, expiring time.Duration) ([]Certificate, error) {
+	var certs []Certificate
+
+	onDisk, err := filepath.Glob(filepath.Join(dir, "*.ign"))
+	if err != nil {
+		return nil, err
+	}
+	ignitionOnDisk := map[string]bool{}
+	for _, path := range onDisk {
+		ignitionOnDisk[filepath.Base(path)] = true
+	}
+
+	stateCerts, err := stateFileCertificates(dir, ignitionOnDisk)
+	if err != nil {
+		return nil, err
+	}
+	certs = append(certs, stateCerts...)
+
+	tlsFiles, err := filepath.Glob(filepath.Join(dir, "tls", "*.crt"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(tlsFiles)
+	for _, path := range append(tlsFiles, onDisk...) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		found, err := fileCertificates(SourceDisk, name, data)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, found...)
+	}
+
+	verifyChains(certs, now)
+	for i := range certs {
+		certs[i].Status = status(certs[i].cert, now, expiring)
+	}
+	return certs, nil
+}
+
+// stateFileCertificates returns the certificates of the files of the assets
+// of the state file. The ignition configs also on disk are left to the disk.
+func stateFileCertificates(dir string, ignitionOnDisk map[string]bool) ([]Certificate, error) {
+	assets, err := assetstore.StateFileAssets(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*asset.File
+	for name, raw := range assets {
+		var state struct {
+			FileList []*asset.File
+
+			// The ignition configs keep their single file and the
+			// config it holds.
+			File   *asset.File
+			Config json.RawMessage
+		}
+		// Assets without files have other shapes.
+		if err := json.Unmarshal(raw, &state); err != nil {
+			continue
+		}
+		switch {
+		case state.File != nil:
+			state.FileList = append(state.FileList, state.File)
+		case len(state.Config) > 0 && string(state.Config) != "null":
+			state.FileList = append(state.FileList, &asset.File{Filename: ignitionFilename(name), Data: state.Config})
+		}
+		for _, file := range state.FileList {
+			if file == nil || (filepath.Ext(file.Filename) == ".ign" && ignitionOnDisk[file.Filename]) {
+				continue
+			}
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Filename < files[j].Filename })
+
+	var certs []Certificate
+	seen := map[string]bool{}
+	for _, file := range files {
+		// CA bundles and certificates share files with their signers.
+		if seen[file.Filename] {
+			continue
+		}
+		seen[file.Filename] = true
+		found, err := fileCertificates(SourceState, file.Filename, file.Data)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, found...)
+	}
+	return certs, nil
+}
+
+// ignitionFilename returns the file of the ignition config of the asset
+// stored in the state file under typeName, e.g. bootstrap.ign for
+// *bootstrap.Bootstrap.
+func ignitionFilename(typeName string) string {
+	return strings.ToLower(typeName[strings.LastIndex(typeName, ".")+1:]) + ".ign"
+}
+
+// fileCertificates returns the certificates of a .crt file or embedded in
+// the .crt files and certificate authorities of an ignition config.
+func fileCertificates(source, name string, data []byte) ([]Certificate, error) {
+	switch filepath.Ext(name) {
+	case ".crt":
+		return pemCertificates(source, name, data)
+	case ".ign":
+		return ignitionCertificates(source, name, data)
+	default:
+		return nil, nil
+	}
+}
+
+// ignitionCertificates returns the certificates embedded in an ignition
+// config.
+func ignitionCertificates(source, name string, data []byte) ([]Certificate, error) {
+	config := &igntypes.Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse ignition config %s", name)
+	}
+
+	var certs []Certificate
+	add := func(file string, contents *string) error {
+		if contents == nil {
+			return nil
+		}
+		// Remote files cannot be inspected.
+		decoded, err := dataurl.DecodeString(*contents)
+		if err != nil {
+			return nil
+		}
+		found, err := pemCertificates(source, file, decoded.Data)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, found...)
+		return nil
+	}
+	for _, ca := range config.Ignition.Security.TLS.CertificateAuthorities {
+		if err := add(name+":certificateAuthorities", ca.Source); err != nil {
+			return nil, err
+		}
+	}
+	for _, file := range config.Storage.Files {
+		if filepath.Ext(file.Path) != ".crt" {
+			continue
+		}
+		if err := add(name+":"+file.Path, file.Contents.Source); err != nil {
+			return nil, err
+		}
+	}
+	return certs, nil
+}
+
+// pemCertificates returns the certificates of PEM data.
+func pemCertificates(source, name string, data []byte) ([]Certificate, error) {
+	var certs []Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse certificate in %s", name)
+		}
+		certs = append(certs, Certificate{
+			Source:    source,
+			File:      name,
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			SANs:      subjectAltNames(cert),
+			KeyType:   keyType(cert),
+			IsCA:      cert.IsCA,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			cert:      cert,
+		})
+	}
+	return certs, nil
+}
+
+func subjectAltNames(cert *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func keyType(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
+
+// verifyChains sets the Chain of the certificates, verified at now against
+// the certificate authorities found with them.
+func verifyChains(certs []Certificate, now time.Time) {
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	for _, c := range certs {
+		if !c.cert.IsCA {
+			continue
+		}
+		if c.cert.CheckSignatureFrom(c.cert) == nil {
+			roots.AddCert(c.cert)
+		} else {
+			intermediates.AddCert(c.cert)
+		}
+	}
+	for i := range certs {
+		_, err := certs[i].cert.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   now,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			certs[i].Chain = err.Error()
+		} else {
+			certs[i].Chain = chainValid
+		}
+	}
+}
+
+func status(cert *x509.Certificate, now time.Time, expiring time.Duration) Status {
+	switch {
+	case now.Before(cert.NotBefore):
+		return StatusNotYetValid
+	case now.After(cert.NotAfter):
+		return StatusExpired
+	case cert.NotAfter.Sub(now) < expiring:
+		return StatusExpiring
+	default:
+		return StatusValid
+	}
+}
+
+// WriteCertificates writes the certificates to w in the given format.
+func WriteCertificates(w io.Writer, certs []Certificate, format string) error {
+	switch format {
+	case FormatJSON:
+		if certs == nil {
+			certs = []Certificate{}
+		}
+		data, err := json.MarshalIndent(certs, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal certificates")
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case FormatText:
+		for i, c := range certs {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			sans := "-"
+			if len(c.SANs) > 0 {
+				sans = strings.Join(c.SANs, ", ")
+			}
+			fmt.Fprintf(w, "%s (%s)\n", c.File, c.Source)
+			for _, field := range [][2]string{
+				{"Subject", c.Subject},
+				{"Issuer", c.Issuer},
+				{"SANs", sans},
+				{"Key", c.KeyType},
+				{"CA", fmt.Sprint(c.IsCA)},
+				{"Not before", c.NotBefore.UTC().Format(time.RFC3339)},
+				{"Not after", c.NotAfter.UTC().Format(time.RFC3339)},
+				{"Chain", c.Chain},
+				{"Status", string(c.Status)},
+			} {
+				fmt.Fprintf(w, "  %-12s%s\n", field[0]+":", field[1])
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported output format %q", format)
+	}
+}
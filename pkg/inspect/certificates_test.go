@@ -0,0 +1,196 @@
+package inspect
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vincent-petithory/dataurl"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/tls"
+)
+
+func TestCertificates(t *testing.T) {
+	caKey, ca, err := tls.GenerateSelfSignedCertificate(&tls.CertCfg{
+		Subject:   pkix.Name{CommonName: "root-ca", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  tls.ValidityTenYears,
+		IsCA:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, leaf, err := tls.GenerateSignedCertificate(caKey, ca, &tls.CertCfg{
+		Subject:   pkix.Name{CommonName: "mcs", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages: x509.KeyUsageDigitalSignature,
+		Validity:  tls.ValidityOneDay,
+		DNSNames:  []string{"api-int.example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, other, err := tls.GenerateSelfSignedCertificate(&tls.CertCfg{
+		Subject:   pkix.Name{CommonName: "other-ca", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages: x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  tls.ValidityOneYear,
+		IsCA:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, orphan, err := tls.GenerateSignedCertificate(otherKey, other, &tls.CertCfg{
+		Subject:   pkix.Name{CommonName: "orphan", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages: x509.KeyUsageDigitalSignature,
+		Validity:  tls.ValidityOneYear,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "inspect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The state file holds the CA and the leaf, twice for the leaf, and the
+	// ignition configs with the leaf and the CA; the master config is on
+	// disk.
+	leafFile := &asset.File{Filename: "tls/mcs.crt", Data: tls.CertToPem(leaf)}
+	bootstrapIgn := json.RawMessage(`{"ignition":{"version":"3.1.0"},"storage":{"files":[{"path":"/opt/openshift/tls/mcs.crt","contents":{"source":"` + dataurl.EncodeBytes(tls.CertToPem(leaf)) + `"}}]}}`)
+	ign := []byte(`{"ignition":{"version":"3.1.0","security":{"tls":{"certificateAuthorities":[{"source":"` + dataurl.EncodeBytes(tls.CertToPem(ca)) + `"}]}}}}`)
+	state := map[string]interface{}{
+		"*tls.RootCA": map[string]interface{}{
+			"FileList": []*asset.File{{Filename: "tls/root-ca.crt", Data: tls.CertToPem(ca)}},
+		},
+		"*tls.MCSCertKey": map[string]interface{}{
+			"FileList": []*asset.File{leafFile},
+		},
+		"*tls.MCSCopy": map[string]interface{}{
+			"FileList": []*asset.File{leafFile},
+		},
+		"*bootstrap.Bootstrap": map[string]interface{}{
+			"Config": bootstrapIgn,
+			"File":   &asset.File{Filename: "bootstrap.ign", Data: bootstrapIgn},
+		},
+		"*machine.Master": map[string]interface{}{
+			"Config": json.RawMessage(ign),
+			"File":   &asset.File{Filename: "master.ign", Data: ign},
+		},
+		"*machine.Worker": map[string]interface{}{
+			"Config": json.RawMessage(ign),
+		},
+		"*password.KubeadminPassword": map[string]interface{}{
+			"Password": "secret",
+		},
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ".openshift_install_state.json"), data, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	// The tls directory holds a certificate signed by a CA that is not
+	// around.
+	if err := os.MkdirAll(filepath.Join(dir, "tls"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls", "orphan.crt"), tls.CertToPem(orphan), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	// The ignition config embeds the CA as a certificate authority.
+	if err := ioutil.WriteFile(filepath.Join(dir, "master.ign"), ign, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	certs, err := Certificates(dir, now, 30*24*time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	type summary struct {
+		Source, File, Subject, Chain string
+		SANs                         []string
+		Status                       Status
+	}
+	var summaries []summary
+	for _, c := range certs {
+		summaries = append(summaries, summary{c.Source, c.File, c.Subject, c.Chain, c.SANs, c.Status})
+	}
+	assert.Equal(t, []summary{
+		{SourceState, "bootstrap.ign:/opt/openshift/tls/mcs.crt", "CN=mcs,OU=openshift", chainValid, []string{"api-int.example.com"}, StatusExpiring},
+		{SourceState, "tls/mcs.crt", "CN=mcs,OU=openshift", chainValid, []string{"api-int.example.com"}, StatusExpiring},
+		{SourceState, "tls/root-ca.crt", "CN=root-ca,OU=openshift", chainValid, nil, StatusValid},
+		{SourceState, "worker.ign:certificateAuthorities", "CN=root-ca,OU=openshift", chainValid, nil, StatusValid},
+		{SourceDisk, "tls/orphan.crt", "CN=orphan,OU=openshift", "x509: certificate signed by unknown authority", nil, StatusValid},
+		{SourceDisk, "master.ign:certificateAuthorities", "CN=root-ca,OU=openshift", chainValid, nil, StatusValid},
+	}, summaries)
+	assert.Equal(t, "RSA 2048", certs[1].KeyType)
+
+	// Past the validity of the leaf.
+	certs, err = Certificates(dir, now.Add(2*tls.ValidityOneDay), 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, StatusExpired, certs[1].Status)
+	assert.Contains(t, certs[1].Chain, "x509: certificate has expired or is not yet valid")
+}
+
+func TestWriteCertificates(t *testing.T) {
+	notBefore := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	certs := []Certificate{{
+		Source:    SourceState,
+		File:      "tls/mcs.crt",
+		Subject:   "CN=mcs,OU=openshift",
+		Issuer:    "CN=root-ca,OU=openshift",
+		SANs:      []string{"api-int.example.com", "10.0.0.1"},
+		KeyType:   "ECDSA P-256",
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(tls.ValidityOneDay),
+		Chain:     chainValid,
+		Status:    StatusExpiring,
+	}}
+
+	var text bytes.Buffer
+	assert.NoError(t, WriteCertificates(&text, certs, FormatText))
+	assert.Equal(t, `tls/mcs.crt (state)
+  Subject:    CN=mcs,OU=openshift
+  Issuer:     CN=root-ca,OU=openshift
+  SANs:       api-int.example.com, 10.0.0.1
+  Key:        ECDSA P-256
+  CA:         false
+  Not before: 2020-07-01T00:00:00Z
+  Not after:  2020-07-02T00:00:00Z
+  Chain:      valid
+  Status:     Expiring
+`, text.String())
+
+	var js bytes.Buffer
+	assert.NoError(t, WriteCertificates(&js, certs, FormatJSON))
+	assert.JSONEq(t, `[{
+  "source": "state",
+  "file": "tls/mcs.crt",
+  "subject": "CN=mcs,OU=openshift",
+  "issuer": "CN=root-ca,OU=openshift",
+  "sans": ["api-int.example.com", "10.0.0.1"],
+  "keyType": "ECDSA P-256",
+  "isCA": false,
+  "notBefore": "2020-07-01T00:00:00Z",
+  "notAfter": "2020-07-02T00:00:00Z",
+  "chain": "valid",
+  "status": "Expiring"
+}]`, js.String())
+
+	assert.EqualError(t, WriteCertificates(&js, certs, "yaml"), `unsupported output format "yaml"`)
+}
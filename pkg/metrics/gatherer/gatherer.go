@@ -0,0 +1,244 @@
+// Package gatherer collects the metrics of an installer invocation and exports
+// them to a Prometheus Pushgateway or to a file.
+package gatherer
+
+import (
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/openshift/installer/pkg/metrics/builder"
+	"github.com/openshift/installer/pkg/metrics/pushclient"
+)
+
+// Result is the outcome of the invocation.
+type Result string
+
+const (
+	// Success denotes an invocation that completed.
+	Success Result = "success"
+	// Failure denotes an invocation that failed.
+	Failure Result = "failure"
+)
+
+// JobName is the Pushgateway job the metrics are pushed to.
+const JobName = "openshift_install"
+
+// durationBuckets are the histogram buckets of the durations, in seconds,
+// from one minute to two hours.
+var durationBuckets = []float64{60, 120, 300, 600, 900, 1200, 1800, 2700, 3600, 5400, 7200}
+
+var (
+	durationOpts = builder.MetricOpts{
+		Name:       "openshift_install_duration_seconds",
+		Desc:       "Time taken by an installer invocation.",
+		Labels:     []string{"command", "platform", "result", "reason"},
+		Buckets:    durationBuckets,
+		MetricType: builder.Histogram,
+	}
+	stageDurationOpts = builder.MetricOpts{
+		Name:       "openshift_install_stage_duration_seconds",
+		Desc:       "Time taken by a stage of an installer invocation.",
+		Labels:     []string{"command", "platform", "stage"},
+		Buckets:    durationBuckets,
+		MetricType: builder.Histogram,
+	}
+	assetsGeneratedOpts = builder.MetricOpts{
+		Name:       "openshift_install_assets_generated_total",
+		Desc:       "Number of assets generated, and whether they replaced assets found in the directory or the state file.",
+		Labels:     []string{"command", "platform", "regenerated"},
+		MetricType: builder.Counter,
+	}
+	terraformResourcesOpts = builder.MetricOpts{
+		Name:       "openshift_install_terraform_resources_total",
+		Desc:       "Number of resources Terraform completed, by action.",
+		Labels:     []string{"command", "platform", "action"},
+		MetricType: builder.Counter,
+	}
+)
+
+// Invocation holds the metrics of an installer invocation.
+type Invocation struct {
+	// Command is the installer command, e.g. "create cluster".
+	Command string
+
+	// Platform is the platform of the cluster, if known.
+	Platform string
+
+	// Result and Reason describe the outcome of the invocation. Reason is
+	// empty on success.
+	Result Result
+	Reason string
+
+	// Duration is the time taken by the invocation.
+	Duration time.Duration
+
+	// Stages are the times taken by the stages of the invocation.
+	Stages map[string]time.Duration
+
+	// AssetsGenerated and AssetsRegenerated count the generated assets.
+	// Regenerated assets replaced one found in the directory or the state
+	// file.
+	AssetsGenerated   int
+	AssetsRegenerated int
+
+	// TerraformResources counts the resources Terraform completed, by
+	// action.
+	TerraformResources map[string]int
+}
+
+var (
+	mu      sync.Mutex
+	current = Invocation{TerraformResources: map[string]int{}}
+)
+
+// SetPlatform records the platform of the cluster.
+func SetPlatform(platform string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.Platform = platform
+}
+
+// AddAssetGenerated counts a generated asset.
+func AddAssetGenerated(regenerated bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if regenerated {
+		current.AssetsRegenerated++
+	} else {
+		current.AssetsGenerated++
+	}
+}
+
+// AddTerraformResource counts a resource Terraform completed.
+func AddTerraformResource(action string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current.TerraformResources[action]++
+}
+
+// Current returns a copy of the metrics recorded so far.
+func Current() Invocation {
+	mu.Lock()
+	defer mu.Unlock()
+	invocation := current
+	invocation.TerraformResources = make(map[string]int, len(current.TerraformResources))
+	for action, count := range current.TerraformResources {
+		invocation.TerraformResources[action] = count
+	}
+	return invocation
+}
+
+// Collectors returns the Prometheus collectors for the metrics.
+func (i *Invocation) Collectors() ([]prometheus.Collector, error) {
+	var collectors []prometheus.Collector
+	add := func(opts builder.MetricOpts, value float64, labels map[string]string) error {
+		labels["command"] = i.Command
+		labels["platform"] = i.Platform
+		metric, err := builder.NewMetricBuilder(opts, value, nil)
+		if err != nil {
+			return err
+		}
+		for key, value := range labels {
+			if err := metric.AddLabelValue(key, value); err != nil {
+				return err
+			}
+		}
+		collector, err := metric.PromCollector()
+		if err != nil {
+			return err
+		}
+		collectors = append(collectors, collector)
+		return nil
+	}
+
+	if err := add(durationOpts, i.Duration.Seconds(), map[string]string{"result": string(i.Result), "reason": i.Reason}); err != nil {
+		return nil, err
+	}
+	stages := make([]string, 0, len(i.Stages))
+	for stage := range i.Stages {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		if err := add(stageDurationOpts, i.Stages[stage].Seconds(), map[string]string{"stage": stage}); err != nil {
+			return nil, err
+		}
+	}
+	if err := add(assetsGeneratedOpts, float64(i.AssetsGenerated), map[string]string{"regenerated": "false"}); err != nil {
+		return nil, err
+	}
+	if err := add(assetsGeneratedOpts, float64(i.AssetsRegenerated), map[string]string{"regenerated": "true"}); err != nil {
+		return nil, err
+	}
+	actions := make([]string, 0, len(i.TerraformResources))
+	for action := range i.TerraformResources {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		if err := add(terraformResourcesOpts, float64(i.TerraformResources[action]), map[string]string{"action": action}); err != nil {
+			return nil, err
+		}
+	}
+	return collectors, nil
+}
+
+// Push pushes the metrics to the Pushgateway at url, in a group labelled
+// with the grouping labels.
+func (i *Invocation) Push(url string, grouping map[string]string) error {
+	collectors, err := i.Collectors()
+	if err != nil {
+		return err
+	}
+	client := &pushclient.PushClient{
+		URL:      url,
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		JobName:  JobName,
+		Grouping: grouping,
+	}
+	return client.Push(collectors...)
+}
+
+// WriteFile writes the metrics to the file at path in the OpenMetrics text
+// format.
+func (i *Invocation) WriteFile(path string) error {
+	collectors, err := i.Collectors()
+	if err != nil {
+		return err
+	}
+	registry := prometheus.NewRegistry()
+	for _, collector := range collectors {
+		if err := registry.Register(collector); err != nil {
+			return errors.Wrap(err, "failed to register metric")
+		}
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return errors.Wrap(err, "failed to gather metrics")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	encoder := expfmt.NewEncoder(file, expfmt.FmtOpenMetrics)
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return errors.Wrapf(err, "failed to write %s", family.GetName())
+		}
+	}
+	if closer, ok := encoder.(expfmt.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
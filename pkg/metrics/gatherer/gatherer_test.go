@@ -0,0 +1,91 @@
+package gatherer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testInvocation() *Invocation {
+	return &Invocation{
+		Command:  "create cluster",
+		Platform: "aws",
+		Result:   Failure,
+		Reason:   "BootstrapFailed",
+		Duration: 40 * time.Minute,
+		Stages: map[string]time.Duration{
+			"Infrastructure":     10 * time.Minute,
+			"Bootstrap Complete": 30 * time.Minute,
+		},
+		AssetsGenerated:    12,
+		AssetsRegenerated:  2,
+		TerraformResources: map[string]int{"create": 80},
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "metrics.txt")
+	if !assert.NoError(t, testInvocation().WriteFile(path)) {
+		return
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := string(data)
+	for _, expected := range []string{
+		`openshift_install_duration_seconds_sum{command="create cluster",platform="aws",reason="BootstrapFailed",result="failure"} 2400`,
+		`openshift_install_stage_duration_seconds_sum{command="create cluster",platform="aws",stage="Bootstrap Complete"} 1800`,
+		`openshift_install_stage_duration_seconds_sum{command="create cluster",platform="aws",stage="Infrastructure"} 600`,
+		`openshift_install_assets_generated_total{command="create cluster",platform="aws",regenerated="false"} 12`,
+		`openshift_install_assets_generated_total{command="create cluster",platform="aws",regenerated="true"} 2`,
+		`openshift_install_terraform_resources_total{action="create",command="create cluster",platform="aws"} 80`,
+	} {
+		assert.Contains(t, output, expected)
+	}
+	assert.True(t, strings.HasSuffix(output, "# EOF\n"), "missing OpenMetrics EOF marker")
+}
+
+func TestPush(t *testing.T) {
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		data, _ := ioutil.ReadAll(req.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	err := testInvocation().Push(server.URL, map[string]string{"cluster_id": "test-abcde"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/metrics/job/openshift_install/cluster_id/test-abcde", path)
+	assert.Contains(t, body, `openshift_install_terraform_resources_total{action="create",command="create cluster",platform="aws"} 80`)
+}
+
+func TestCurrent(t *testing.T) {
+	SetPlatform("gcp")
+	AddAssetGenerated(false)
+	AddAssetGenerated(true)
+	AddTerraformResource("create")
+
+	invocation := Current()
+	assert.Equal(t, "gcp", invocation.Platform)
+	assert.Equal(t, 1, invocation.AssetsGenerated)
+	assert.Equal(t, 1, invocation.AssetsRegenerated)
+	assert.Equal(t, map[string]int{"create": 1}, invocation.TerraformResources)
+
+	invocation.TerraformResources["create"]++
+	assert.Equal(t, 1, Current().TerraformResources["create"])
+}
@@ -2,6 +2,7 @@ package pushclient
 
 import (
 	"net/http"
+	"sort"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,6 +17,10 @@ type PushClient struct {
 	URL     string
 	Client  *http.Client
 	JobName string
+
+	// Grouping holds the labels of the group the metrics are pushed to,
+	// besides the job. Pushing replaces the metrics of the same group only.
+	Grouping map[string]string
 }
 
 // Push uses all the configuration settings from the client and pushes to the prometheus
@@ -24,6 +29,15 @@ type PushClient struct {
 func (p *PushClient) Push(collectors ...prometheus.Collector) error {
 	pushClient := push.New(p.URL, p.JobName).Client(p.Client).Format(expfmt.FmtText)
 
+	names := make([]string, 0, len(p.Grouping))
+	for name := range p.Grouping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pushClient.Grouping(name, p.Grouping[name])
+	}
+
 	for _, value := range collectors {
 		pushClient.Collector(value)
 	}
@@ -122,3 +122,22 @@ func TestPushMetricsBadServer(t *testing.T) {
 		assert.EqualError(t, err, errorMessage)
 	}
 }
+
+func TestPushMetricsToGroup(t *testing.T) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test", Help: "test metrics"})
+
+	var path string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+	}))
+	defer testServer.Close()
+
+	pushClient := PushClient{
+		URL:      testServer.URL,
+		Client:   &http.Client{},
+		JobName:  "installer_metrics",
+		Grouping: map[string]string{"platform": "aws", "cluster_id": "test-abcde"},
+	}
+	assert.NoError(t, pushClient.Push(counter))
+	assert.Equal(t, "/metrics/job/installer_metrics/cluster_id/test-abcde/platform/aws", path)
+}
@@ -32,6 +32,16 @@ func StopTimer(key string) {
 	timer.StopTimer(key)
 }
 
+// AddDuration records an already measured duration for the stage sent as the key parameter.
+func AddDuration(key string, duration time.Duration) {
+	timer.AddDuration(key, duration)
+}
+
+// StageDurations returns the durations of the stages timed so far.
+func StageDurations() map[string]time.Duration {
+	return timer.StageDurations()
+}
+
 // LogSummary prints the summary of all the times collected so far into the INFO section.
 func LogSummary() {
 	timer.LogSummary(logrus.StandardLogger())
@@ -61,6 +71,30 @@ func (t *Timer) StopTimer(key string) time.Duration {
 	return time.Since(time.Now())
 }
 
+// AddDuration records an already measured duration for the stage sent as the key parameter.
+// This is useful for stages that were timed by something else, such as terraform resources.
+func (t *Timer) AddDuration(key string, duration time.Duration) {
+	if _, found := t.stageTimes[key]; !found {
+		if _, started := t.startTimes[key]; !started {
+			t.listOfStages = append(t.listOfStages, key)
+		}
+	}
+	t.stageTimes[key] = duration.Round(time.Second)
+}
+
+// StageDurations returns the durations of the stages started with StartTimer and
+// stopped since, including TotalTimeElapsed. Durations recorded with AddDuration are
+// not included.
+func (t *Timer) StageDurations() map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for key := range t.startTimes {
+		if duration, found := t.stageTimes[key]; found {
+			durations[key] = duration
+		}
+	}
+	return durations
+}
+
 // LogSummary prints the summary of all the times collected so far into the INFO section.
 // The format of printing will be the following:
 // If there are no stages except the total time stage, then it only prints the following
@@ -129,3 +129,39 @@ func TestNewTimer(t *testing.T) {
 		t.Fatalf("Expected empty list of startTimes property in the new timer created, got %d", len(timer.stageTimes))
 	}
 }
+
+func TestAddDuration(t *testing.T) {
+	timer := NewTimer()
+
+	timer.AddDuration("testStage1", 90*time.Second)
+	timer.AddDuration("testStage1", 95*time.Second)
+
+	if len(timer.listOfStages) != 1 {
+		t.Fatalf("Expected a single stage after adding the same stage twice, got %d", len(timer.listOfStages))
+	}
+
+	if timer.stageTimes["testStage1"] != 95*time.Second {
+		t.Fatalf("Expected the duration of the last added stage to be 1m35s, got %s", timer.stageTimes["testStage1"])
+	}
+}
+
+func TestStageDurations(t *testing.T) {
+	timer := NewTimer()
+
+	timer.StartTimer(TotalTimeElapsed)
+	timer.StartTimer("testStage1")
+	timer.StartTimer("testStage2")
+	timer.StopTimer("testStage1")
+	timer.AddDuration("module.vpc.aws_vpc.new_vpc", 3*time.Second)
+	timer.StopTimer(TotalTimeElapsed)
+
+	durations := timer.StageDurations()
+	if len(durations) != 2 {
+		t.Fatalf("expected the durations of %s and testStage1, got %v", TotalTimeElapsed, durations)
+	}
+	for _, key := range []string{TotalTimeElapsed, "testStage1"} {
+		if _, found := durations[key]; !found {
+			t.Errorf("expected a duration for %s, got %v", key, durations)
+		}
+	}
+}
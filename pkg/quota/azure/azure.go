@@ -0,0 +1,89 @@
+// Package azure loads the compute and network usage quotas of an Azure
+// subscription.
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	azcompute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2018-10-01/compute"
+	aznetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	azureconfig "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	"github.com/openshift/installer/pkg/quota"
+)
+
+// usage is the usage of a resource in a region, as reported by the compute
+// and network usage APIs.
+type usage struct {
+	service string
+	name    string
+	inUse   int64
+	limit   int64
+}
+
+// Load loads the quota information for a region. Compute quotas are named
+// compute/<name>, e.g. compute/cores for the total regional vCPUs and
+// compute/standardDSv3Family for the vCPUs of a family, and network quotas
+// network/<name>, e.g. network/PublicIPAddresses.
+func Load(ctx context.Context, ssn *azureconfig.Session, region string) ([]quota.Quota, error) {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	var usages []usage
+	computeClient := azcompute.NewUsageClientWithBaseURI(ssn.Environment.ResourceManagerEndpoint, ssn.Credentials.SubscriptionID)
+	computeClient.Authorizer = ssn.Authorizer
+	for iter, err := computeClient.ListComplete(ctx, region); iter.NotDone(); err = iter.NextWithContext(ctx) {
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list compute usage")
+		}
+		u := iter.Value()
+		if u.Name == nil || u.Name.Value == nil || u.CurrentValue == nil || u.Limit == nil {
+			continue
+		}
+		usages = append(usages, usage{service: "compute", name: *u.Name.Value, inUse: int64(*u.CurrentValue), limit: *u.Limit})
+	}
+
+	networkClient := aznetwork.NewUsagesClientWithBaseURI(ssn.Environment.ResourceManagerEndpoint, ssn.Credentials.SubscriptionID)
+	networkClient.Authorizer = ssn.Authorizer
+	for iter, err := networkClient.ListComplete(ctx, region); iter.NotDone(); err = iter.NextWithContext(ctx) {
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list network usage")
+		}
+		u := iter.Value()
+		if u.Name == nil || u.Name.Value == nil || u.CurrentValue == nil || u.Limit == nil {
+			continue
+		}
+		usages = append(usages, usage{service: "network", name: *u.Name.Value, inUse: *u.CurrentValue, limit: *u.Limit})
+	}
+	return newQuota(region, usages), nil
+}
+
+func newQuota(region string, usages []usage) []quota.Quota {
+	ret := make([]quota.Quota, 0, len(usages))
+	for _, u := range usages {
+		ret = append(ret, quota.Quota{
+			Service: u.service,
+			Name:    fmt.Sprintf("%s/%s", u.service, u.name),
+			Region:  region,
+			InUse:   u.inUse,
+			Limit:   u.limit,
+		})
+	}
+	return ret
+}
+
+// IsUnauthorized checks if the error is un authorized.
+func IsUnauthorized(err error) bool {
+	var detailed autorest.DetailedError
+	if errors.As(err, &detailed) {
+		if status, ok := detailed.StatusCode.(int); ok {
+			return status == http.StatusUnauthorized || status == http.StatusForbidden
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Package ovirt computes the capacity quotas of an oVirt cluster and storage
+// domain.
+package ovirt
+
+import (
+	"fmt"
+
+	ovirtsdk "github.com/ovirt/go-ovirt"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/quota"
+	"github.com/openshift/installer/pkg/types/ovirt"
+)
+
+const (
+	// Storage is the quota of the storage domain, in GiB.
+	Storage = "ovirt/storage"
+
+	// VCPUs is the quota of logical processors of the cluster hosts. The
+	// usage is the vCPUs of the running virtual machines.
+	VCPUs = "ovirt/vcpus"
+
+	// Memory is the quota of memory of the cluster hosts, in MiB. The usage
+	// is the memory the scheduler can no longer place virtual machines in.
+	Memory = "ovirt/memory"
+)
+
+const (
+	mib = 1024 * 1024
+	gib = 1024 * mib
+)
+
+// Load loads the capacity of the storage domain and of the hosts of the
+// cluster. The quotas have no region.
+func Load(con *ovirtsdk.Connection, platform *ovirt.Platform) ([]quota.Quota, error) {
+	sdResponse, err := con.SystemService().StorageDomainsService().StorageDomainService(platform.StorageDomainID).Get().Send()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get storage domain %s", platform.StorageDomainID)
+	}
+	storage := quota.Quota{Service: "ovirt", Name: Storage}
+	if sd, ok := sdResponse.StorageDomain(); ok {
+		used, _ := sd.Used()
+		available, _ := sd.Available()
+		storage.InUse = used / gib
+		storage.Limit = (used + available) / gib
+	}
+
+	clusterResponse, err := con.SystemService().ClustersService().ClusterService(platform.ClusterID).Get().Send()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get cluster %s", platform.ClusterID)
+	}
+	cluster, ok := clusterResponse.Cluster()
+	if !ok {
+		return nil, errors.Errorf("cluster %s not found", platform.ClusterID)
+	}
+	name, _ := cluster.Name()
+
+	hostsResponse, err := con.SystemService().HostsService().List().Search(fmt.Sprintf("cluster=%s", name)).Send()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the hosts of cluster %s", name)
+	}
+	vmsResponse, err := con.SystemService().VmsService().List().Search(fmt.Sprintf("cluster=%s and status=up", name)).Send()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the running VMs of cluster %s", name)
+	}
+
+	vcpus := quota.Quota{Service: "ovirt", Name: VCPUs}
+	memory := quota.Quota{Service: "ovirt", Name: Memory}
+	if hosts, ok := hostsResponse.Hosts(); ok {
+		for _, host := range hosts.Slice() {
+			if cpu, ok := host.Cpu(); ok {
+				vcpus.Limit += topologyCPUs(cpu)
+			}
+			total, _ := host.Memory()
+			schedulable, _ := host.MaxSchedulingMemory()
+			memory.Limit += total / mib
+			memory.InUse += (total - schedulable) / mib
+		}
+	}
+	if vms, ok := vmsResponse.Vms(); ok {
+		for _, vm := range vms.Slice() {
+			if cpu, ok := vm.Cpu(); ok {
+				vcpus.InUse += topologyCPUs(cpu)
+			}
+		}
+	}
+	return []quota.Quota{storage, vcpus, memory}, nil
+}
+
+// topologyCPUs returns the number of logical processors of a CPU topology.
+func topologyCPUs(cpu *ovirtsdk.Cpu) int64 {
+	topology, ok := cpu.Topology()
+	if !ok {
+		return 0
+	}
+	sockets, _ := topology.Sockets()
+	cores, _ := topology.Cores()
+	threads, _ := topology.Threads()
+	if threads == 0 {
+		threads = 1
+	}
+	return sockets * cores * threads
+}
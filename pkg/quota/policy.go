@@ -0,0 +1,124 @@
+package quota
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// UnknownAction is what a Policy does with the constraints whose quota could
+// not be found.
+type UnknownAction string
+
+const (
+	// UnknownWarn warns about the constraints with unknown quota.
+	UnknownWarn UnknownAction = "Warn"
+	// UnknownFail fails the check when a constraint has unknown quota.
+	UnknownFail UnknownAction = "Fail"
+)
+
+// Threshold is the quota that must be left once a constraint is satisfied.
+// When both are set, the larger one applies.
+type Threshold struct {
+	// MinFreePercent is the percentage of the limit that must be left.
+	MinFreePercent float64 `json:"minFreePercent,omitempty"`
+
+	// MinFree is the amount of the quota that must be left.
+	MinFree int64 `json:"minFree,omitempty"`
+}
+
+// Override replaces the threshold of the policy for the constraints matching
+// its name and, when set, its region.
+type Override struct {
+	// Name is the name of the constraint, e.g. compute/cores on Azure or
+	// compute.googleapis.com/cpus on GCP.
+	Name string `json:"name"`
+
+	// Region is the region of the constraint. Empty matches all regions.
+	Region string `json:"region,omitempty"`
+
+	Threshold
+}
+
+// Policy defines when a constraint passes the check. The zero Policy only
+// fails the constraints exceeding the quota and warns about the unknown
+// ones.
+type Policy struct {
+	// Threshold applies to the constraints without an override.
+	Threshold
+
+	// Unknown is what to do with the constraints whose quota could not be
+	// found. Defaults to Warn.
+	Unknown UnknownAction `json:"unknown,omitempty"`
+
+	// Overrides replace the threshold for some constraints. The first
+	// matching override applies.
+	Overrides []Override `json:"overrides,omitempty"`
+}
+
+// Validate returns an error when the policy is not valid.
+func (p *Policy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if err := p.Threshold.validate(); err != nil {
+		return err
+	}
+	switch p.Unknown {
+	case "", UnknownWarn, UnknownFail:
+	default:
+		return errors.Errorf("invalid unknown action %q, must be %s or %s", p.Unknown, UnknownWarn, UnknownFail)
+	}
+	for i, o := range p.Overrides {
+		if o.Name == "" {
+			return errors.Errorf("override %d has no name", i)
+		}
+		if err := o.Threshold.validate(); err != nil {
+			return errors.Wrapf(err, "invalid override for %s", o.Name)
+		}
+	}
+	return nil
+}
+
+func (t Threshold) validate() error {
+	if t.MinFreePercent < 0 || t.MinFreePercent > 100 {
+		return errors.Errorf("minFreePercent must be between 0 and 100, got %v", t.MinFreePercent)
+	}
+	if t.MinFree < 0 {
+		return errors.Errorf("minFree must not be negative, got %d", t.MinFree)
+	}
+	return nil
+}
+
+// FailsUnknown returns whether the constraints with unknown quota fail the
+// check.
+func (p *Policy) FailsUnknown() bool {
+	return p != nil && p.Unknown == UnknownFail
+}
+
+// threshold returns the threshold applying to the constraint.
+func (p *Policy) threshold(c Constraint) Threshold {
+	if p == nil {
+		return Threshold{}
+	}
+	for _, o := range p.Overrides {
+		if !strings.EqualFold(o.Name, c.Name) {
+			continue
+		}
+		if o.Region != "" && !strings.EqualFold(o.Region, c.Region) {
+			continue
+		}
+		return o.Threshold
+	}
+	return p.Threshold
+}
+
+// required returns the amount of a quota with limit that must be left.
+func (t Threshold) required(limit int64) int64 {
+	required := int64(math.Ceil(t.MinFreePercent / 100 * float64(limit)))
+	if t.MinFree > required {
+		required = t.MinFree
+	}
+	return required
+}
@@ -0,0 +1,118 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	quotas := []Quota{
+		{Name: "cores", Region: "r1", InUse: 10, Limit: 100},
+		{Name: "cores", Region: "r2", InUse: 10, Limit: 100},
+		{Name: "instances", Region: "r1", InUse: 10, Limit: 100},
+		{Name: "roles", Unlimited: true},
+	}
+	cases := []struct {
+		name        string
+		policy      *Policy
+		constraints []Constraint
+
+		expected []ConstraintReportResult
+		message  string
+	}{{
+		name:        "nil policy",
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 80}},
+		expected:    []ConstraintReportResult{AvailableButLow},
+	}, {
+		name:        "enough headroom",
+		policy:      &Policy{Threshold: Threshold{MinFreePercent: 20}},
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 70}},
+		expected:    []ConstraintReportResult{AvailableButLow},
+	}, {
+		name:        "minimum free percent",
+		policy:      &Policy{Threshold: Threshold{MinFreePercent: 20}},
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 71}},
+		expected:    []ConstraintReportResult{NotAvailable},
+		message:     "only 19 would be leftover but the quota policy requires 20",
+	}, {
+		name:        "minimum free is larger",
+		policy:      &Policy{Threshold: Threshold{MinFreePercent: 20, MinFree: 30}},
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 61}},
+		expected:    []ConstraintReportResult{NotAvailable},
+		message:     "only 29 would be leftover but the quota policy requires 30",
+	}, {
+		name:        "unlimited quota",
+		policy:      &Policy{Threshold: Threshold{MinFree: 30}},
+		constraints: []Constraint{{Name: "roles", Count: 1000}},
+		expected:    []ConstraintReportResult{Available},
+	}, {
+		name: "override",
+		policy: &Policy{
+			Threshold: Threshold{MinFreePercent: 50},
+			Overrides: []Override{{Name: "CORES", Threshold: Threshold{MinFree: 5}}},
+		},
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 80}, {Name: "instances", Region: "r1", Count: 80}},
+		expected:    []ConstraintReportResult{AvailableButLow, NotAvailable},
+	}, {
+		name: "regional override",
+		policy: &Policy{
+			Threshold: Threshold{MinFreePercent: 50},
+			Overrides: []Override{{Name: "cores", Region: "r2"}},
+		},
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 80}, {Name: "cores", Region: "r2", Count: 80}},
+		expected:    []ConstraintReportResult{NotAvailable, AvailableButLow},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reports, _ := tc.policy.Check(quotas, tc.constraints)
+			results := make([]ConstraintReportResult, 0, len(reports))
+			for _, report := range reports {
+				results = append(results, report.Result)
+			}
+			assert.Equal(t, tc.expected, results)
+			if tc.message != "" {
+				assert.Equal(t, tc.message, reports[0].Message)
+			}
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy *Policy
+		err    string
+	}{{
+		name: "nil",
+	}, {
+		name:   "valid",
+		policy: &Policy{Threshold: Threshold{MinFreePercent: 20}, Unknown: UnknownFail, Overrides: []Override{{Name: "cores"}}},
+	}, {
+		name:   "percent above 100",
+		policy: &Policy{Threshold: Threshold{MinFreePercent: 120}},
+		err:    "minFreePercent must be between 0 and 100, got 120",
+	}, {
+		name:   "negative minimum",
+		policy: &Policy{Overrides: []Override{{Name: "cores", Threshold: Threshold{MinFree: -1}}}},
+		err:    "invalid override for cores: minFree must not be negative, got -1",
+	}, {
+		name:   "invalid unknown action",
+		policy: &Policy{Unknown: "Ignore"},
+		err:    `invalid unknown action "Ignore", must be Warn or Fail`,
+	}, {
+		name:   "override without name",
+		policy: &Policy{Overrides: []Override{{Region: "r1"}}},
+		err:    "override 0 has no name",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package quota
 
 import (
-	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -46,27 +45,48 @@ const (
 
 // ConstraintReport provides result for a given constraint.
 type ConstraintReport struct {
-	For     *Constraint
+	For *Constraint
+	// Quota is the quota matched by the constraint, nil when none was
+	// found.
+	Quota   *Quota
 	Result  ConstraintReportResult
 	Message string
 }
 
+// Headroom returns the amount of the quota left once the constraint is
+// satisfied. It is only meaningful when Quota is set and limited.
+func (r *ConstraintReport) Headroom() int64 {
+	if r.Quota == nil {
+		return 0
+	}
+	return r.Quota.Limit - r.Quota.InUse - r.For.Count
+}
+
 // Check returns whether the checks constraints are possible gives the quotas.
 // It returns an erros when any one of the constraint's result was `NotAvailable` or `Unknown`
 func Check(quotas []Quota, checks []Constraint) ([]ConstraintReport, error) {
+	var p *Policy
+	return p.Check(quotas, checks)
+}
+
+// Check returns whether the checks constraints are possible given the quotas
+// and the thresholds of the policy. A nil policy has no thresholds. It
+// returns an error when any one of the constraint's result was
+// `NotAvailable` or `Unknown`.
+func (p *Policy) Check(quotas []Quota, checks []Constraint) ([]ConstraintReport, error) {
 	reports := make([]ConstraintReport, 0, len(checks))
 
-	match := func(check Constraint) (Quota, bool) {
-		for _, q := range quotas {
+	match := func(check Constraint) (*Quota, bool) {
+		for idx, q := range quotas {
 			if !strings.EqualFold(check.Name, q.Name) {
 				continue
 			}
 			if !strings.EqualFold(q.Region, check.Region) {
 				continue
 			}
-			return q, true
+			return &quotas[idx], true
 		}
-		return Quota{}, false
+		return nil, false
 	}
 
 	for idx, check := range checks {
@@ -80,6 +100,7 @@ func Check(quotas []Quota, checks []Constraint) ([]ConstraintReport, error) {
 			reports = append(reports, report)
 			continue
 		}
+		report.Quota = matched
 
 		if matched.Unlimited {
 			report.Result = Available
@@ -104,6 +125,12 @@ func Check(quotas []Quota, checks []Constraint) ([]ConstraintReport, error) {
 			reports = append(reports, report)
 			continue
 		}
+		if required := p.threshold(check).required(matched.Limit); availAfterUse < required {
+			report.Result = NotAvailable
+			report.Message = fmt.Sprintf("only %d would be leftover but the quota policy requires %d", availAfterUse, required)
+			reports = append(reports, report)
+			continue
+		}
 		if availAfterUse <= headroom {
 			report.Result = AvailableButLow
 			report.Message = fmt.Sprintf("the required number of resources is available but only %d will be leftover", availAfterUse)
@@ -123,7 +150,7 @@ func Check(quotas []Quota, checks []Constraint) ([]ConstraintReport, error) {
 		}
 	}
 	if failed > 0 {
-		return reports, errors.New("%d checks failed")
+		return reports, fmt.Errorf("%d checks failed", failed)
 	}
 
 	return reports, nil
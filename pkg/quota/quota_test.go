@@ -1,28 +1,79 @@
 package quota
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestCheck(t *testing.T) {
+	quotas := []Quota{
+		{Service: "compute", Name: "cores", Region: "r1", InUse: 10, Limit: 100},
+		{Service: "compute", Name: "instances", Region: "r1", InUse: 80, Limit: 100},
+		{Service: "compute", Name: "addresses", Region: "r1", InUse: 95, Limit: 100},
+		{Service: "iam", Name: "roles", Unlimited: true},
+	}
 	cases := []struct {
-		name string
+		name        string
+		constraints []Constraint
+
+		expected []ConstraintReportResult
+		err      string
 	}{{
-		name: "missing quota",
+		name:        "missing quota",
+		constraints: []Constraint{{Name: "networks", Region: "r1", Count: 1}},
+		expected:    []ConstraintReportResult{Unknown},
+		err:         "1 checks failed",
 	}, {
-		name: "quota with low availability",
+		name:        "quota with low availability",
+		constraints: []Constraint{{Name: "instances", Region: "r1", Count: 5}},
+		expected:    []ConstraintReportResult{AvailableButLow},
 	}, {
-		name: "quota with no availability",
+		name:        "quota with no availability",
+		constraints: []Constraint{{Name: "addresses", Region: "r1", Count: 10}},
+		expected:    []ConstraintReportResult{NotAvailable},
+		err:         "1 checks failed",
 	}, {
-		name: "available quota",
+		name:        "available quota",
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 20}, {Name: "roles", Count: 1000}},
+		expected:    []ConstraintReportResult{Available, Available},
 	}, {
-		name: "available quota, quota with low availability",
+		name:        "available quota, quota with low availability",
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 20}, {Name: "instances", Region: "r1", Count: 5}},
+		expected:    []ConstraintReportResult{Available, AvailableButLow},
 	}, {
-		name: "available quota, quota with no availability",
+		name:        "available quota, quota with no availability",
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 20}, {Name: "cores", Region: "r2", Count: 1}, {Name: "instances", Region: "r1", Count: 101}},
+		expected:    []ConstraintReportResult{Available, Unknown, NotAvailable},
+		err:         "2 checks failed",
 	}, {
-		name: "available quota, missing quota",
+		name:        "available quota, missing quota",
+		constraints: []Constraint{{Name: "cores", Region: "r1", Count: 20}, {Name: "networks", Region: "r1", Count: 1}},
+		expected:    []ConstraintReportResult{Available, Unknown},
+		err:         "1 checks failed",
 	}}
 	for _, test := range cases {
 		t.Run(test.name, func(t *testing.T) {
-
+			reports, err := Check(quotas, test.constraints)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+			results := make([]ConstraintReportResult, 0, len(reports))
+			for _, report := range reports {
+				results = append(results, report.Result)
+			}
+			assert.Equal(t, test.expected, results)
 		})
 	}
 }
+
+func TestHeadroom(t *testing.T) {
+	reports, _ := Check(
+		[]Quota{{Name: "cores", Region: "r1", InUse: 10, Limit: 100}},
+		[]Constraint{{Name: "cores", Region: "r1", Count: 20}, {Name: "networks", Region: "r1", Count: 1}},
+	)
+	assert.Equal(t, int64(70), reports[0].Headroom())
+	assert.Equal(t, int64(0), reports[1].Headroom())
+}
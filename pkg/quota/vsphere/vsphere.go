@@ -0,0 +1,111 @@
+// Package vsphere computes the capacity quotas of a vSphere cluster and
+// datastore.
+package vsphere
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/openshift/installer/pkg/quota"
+	"github.com/openshift/installer/pkg/types/vsphere"
+)
+
+const (
+	// Datastore is the quota of the default datastore, in GiB.
+	Datastore = "vsphere/datastore"
+
+	// VCPUs is the quota of logical processors of the cluster hosts. The
+	// usage is derived from the CPU time used by the hosts.
+	VCPUs = "vsphere/vcpus"
+
+	// Memory is the quota of memory of the cluster hosts, in MiB.
+	Memory = "vsphere/memory"
+)
+
+const (
+	mib = 1024 * 1024
+	gib = 1024 * mib
+)
+
+// Load loads the free space of the default datastore and, when a cluster is
+// configured, the CPU and memory capacity of its hosts. The quotas have no
+// region.
+func Load(ctx context.Context, client *vim25.Client, platform *vsphere.Platform) ([]quota.Quota, error) {
+	finder := find.NewFinder(client, true)
+	dc, err := finder.Datacenter(ctx, platform.Datacenter)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find datacenter %s", platform.Datacenter)
+	}
+	finder.SetDatacenter(dc)
+
+	ds, err := finder.Datastore(ctx, platform.DefaultDatastore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find datastore %s", platform.DefaultDatastore)
+	}
+	var datastore mo.Datastore
+	if err := ds.Properties(ctx, ds.Reference(), []string{"summary"}, &datastore); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the summary of datastore %s", platform.DefaultDatastore)
+	}
+	ret := []quota.Quota{datastoreQuota(datastore.Summary)}
+
+	if platform.Cluster == "" {
+		return ret, nil
+	}
+	cluster, err := finder.ClusterComputeResource(ctx, platform.Cluster)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find cluster %s", platform.Cluster)
+	}
+	hostRefs, err := cluster.Hosts(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the hosts of cluster %s", platform.Cluster)
+	}
+	if len(hostRefs) == 0 {
+		return ret, nil
+	}
+	refs := make([]types.ManagedObjectReference, 0, len(hostRefs))
+	for _, host := range hostRefs {
+		refs = append(refs, host.Reference())
+	}
+	var hosts []mo.HostSystem
+	if err := property.DefaultCollector(client).Retrieve(ctx, refs, []string{"summary"}, &hosts); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the summary of the hosts of cluster %s", platform.Cluster)
+	}
+	summaries := make([]types.HostListSummary, 0, len(hosts))
+	for _, host := range hosts {
+		summaries = append(summaries, host.Summary)
+	}
+	return append(ret, hostQuotas(summaries)...), nil
+}
+
+func datastoreQuota(summary types.DatastoreSummary) quota.Quota {
+	return quota.Quota{
+		Service: "vsphere",
+		Name:    Datastore,
+		InUse:   (summary.Capacity - summary.FreeSpace) / gib,
+		Limit:   summary.Capacity / gib,
+	}
+}
+
+func hostQuotas(hosts []types.HostListSummary) []quota.Quota {
+	vcpus := quota.Quota{Service: "vsphere", Name: VCPUs}
+	memory := quota.Quota{Service: "vsphere", Name: Memory}
+	for _, host := range hosts {
+		if host.Hardware == nil {
+			continue
+		}
+		vcpus.Limit += int64(host.Hardware.NumCpuThreads)
+		if host.Hardware.CpuMhz > 0 {
+			vcpus.InUse += int64(math.Ceil(float64(host.QuickStats.OverallCpuUsage) / float64(host.Hardware.CpuMhz)))
+		}
+		memory.Limit += host.Hardware.MemorySize / mib
+		memory.InUse += int64(host.QuickStats.OverallMemoryUsage)
+	}
+	return []quota.Quota{vcpus, memory}
+}
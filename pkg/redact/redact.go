@@ -0,0 +1,118 @@
+// Package redact removes secrets from the installer log and log bundles.
+package redact
+
+import (
+	"encoding/base64"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Replacement replaces the secrets matched by a Rule.
+const Replacement = "REDACTED"
+
+// Rule matches one kind of secret.
+type Rule struct {
+	// Name describes the secrets matched.
+	Name string
+
+	// Pattern matches the secrets.
+	Pattern *regexp.Regexp
+
+	// Template is expanded in place of each match, as in
+	// regexp.Regexp.Expand.
+	Template string
+}
+
+// passwordKeys are the keys of install-config and credentials fields holding
+// secrets.
+const passwordKeys = `(?:password|passwd|[\w-]*_password|client_?secret|aws_secret_access_key|secret_?access_?key|token|access_?token|refresh_?token|id_?token)`
+
+// DefaultRules are the secrets always redacted. Quotes escaped by a
+// backslash are accepted, as they are when the secrets are part of a JSON
+// document quoted in a log line.
+var DefaultRules = []Rule{{
+	Name:     "pull secret auths",
+	Pattern:  regexp.MustCompile(`(\\?"auth\\?"\s*:\s*\\?")[^"\\]*(\\?")`),
+	Template: `${1}` + Replacement + `${2}`,
+}, {
+	Name:     "PEM private keys",
+	Pattern:  regexp.MustCompile(`(?s)-----BEGIN ([A-Z0-9 ]*)PRIVATE KEY-----.*?-----END [A-Z0-9 ]*PRIVATE KEY-----`),
+	Template: `-----BEGIN ${1}PRIVATE KEY-----` + Replacement + `-----END ${1}PRIVATE KEY-----`,
+}, {
+	// Within quotes escaped by a backslash, the escapes of the quoted
+	// document are themselves escaped.
+	Name:     "quoted password fields",
+	Pattern:  regexp.MustCompile(`(?i)((?:^|[^\w-])\\?"?` + passwordKeys + `\\?"?\s*[:=]\s*)(?:(")(?:[^"\\]|\\.)*"|(\\")(?:[^"\\]|\\\\\\[\\"]|\\\\[^\\"]|\\[^\\"])*\\"|'[^']*')`),
+	Template: `${1}${2}${3}` + Replacement + `${2}${3}`,
+}, {
+	Name:     "password fields",
+	Pattern:  regexp.MustCompile(`(?i)((?:^|[^\w-])"?` + passwordKeys + `"?\s*[:=][ \t]*)[^\s"'\\,}][^\s,}]*`),
+	Template: `${1}` + Replacement,
+}, {
+	Name:     "base64-encoded PEM private keys",
+	Pattern:  regexp.MustCompile(base64PEMKeys()),
+	Template: Replacement,
+}, {
+	Name:     "kubeconfig key data",
+	Pattern:  regexp.MustCompile(`((?:^|[^\w-])\\?"?[\w-]*key-data\\?"?\s*:\s*\\?"?)[A-Za-z0-9+/]+=*`),
+	Template: `${1}` + Replacement,
+}, {
+	Name:     "bearer tokens",
+	Pattern:  regexp.MustCompile(`(?i)(bearer\s+)[\w.~+/-]+=*`),
+	Template: `${1}` + Replacement,
+}}
+
+// base64PEMKeys returns a pattern matching the base64 encodings of PEM
+// private keys, such as the key data of kubeconfigs and secrets. They are
+// found by the encodings of their first line, so that encoded certificates
+// are kept.
+func base64PEMKeys() string {
+	var prefixes []string
+	for _, kind := range []string{"", "RSA ", "EC ", "DSA ", "OPENSSH ", "ENCRYPTED "} {
+		header := "-----BEGIN " + kind + "PRIVATE KEY-----"
+		// Only the groups of three bytes are encoded the same whatever
+		// follows them.
+		prefixes = append(prefixes, base64.StdEncoding.EncodeToString([]byte(header))[:len(header)/3*4])
+	}
+	return `(?:` + strings.Join(prefixes, "|") + `)[A-Za-z0-9+/]*=*`
+}
+
+// Redactor replaces secrets. A nil Redactor leaves its input unchanged.
+type Redactor struct {
+	rules []Rule
+}
+
+// New returns a Redactor for DefaultRules and the regular expressions in
+// patterns, whose whole matches are replaced.
+func New(patterns []string) (*Redactor, error) {
+	rules := append([]Rule{}, DefaultRules...)
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid redaction pattern %q", pattern)
+		}
+		rules = append(rules, Rule{Name: pattern, Pattern: re, Template: Replacement})
+	}
+	return &Redactor{rules: rules}, nil
+}
+
+// Bytes returns data with the secrets replaced.
+func (r *Redactor) Bytes(data []byte) []byte {
+	if r == nil {
+		return data
+	}
+	for _, rule := range r.rules {
+		data = rule.Pattern.ReplaceAll(data, []byte(rule.Template))
+	}
+	return data
+}
+
+// String returns s with the secrets replaced.
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+	return string(r.Bytes([]byte(s)))
+}
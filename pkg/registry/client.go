@@ -0,0 +1,311 @@
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	dockerref "github.com/containers/image/docker/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// ManifestMediaTypes are the media types of the manifests the client accepts.
+var ManifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// ErrNotFound is the cause of the errors of the requests for content the
+// registry does not have.
+var ErrNotFound = errors.New("not found")
+
+// Client queries registries with the credentials of a pull secret.
+type Client struct {
+	httpClient *http.Client
+
+	// auths are the base64-encoded user:password credentials by registry.
+	auths map[string]string
+
+	mu sync.Mutex
+	// tokens are the bearer tokens by registry and repository.
+	tokens map[string]string
+}
+
+// NewClient returns a client authenticating with the credentials of
+// pullSecret, and trusting the certificate authorities of trustBundle in
+// addition to the system roots.
+func NewClient(pullSecret, trustBundle string) (*Client, error) {
+	var secret struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(pullSecret), &secret); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the pull secret")
+	}
+	auths := make(map[string]string, len(secret.Auths))
+	for registry, auth := range secret.Auths {
+		auths[registry] = auth.Auth
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if trustBundle != "" && !roots.AppendCertsFromPEM([]byte(trustBundle)) {
+		return nil, errors.New("failed to parse the trust bundle")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+
+	return &Client{
+		httpClient: &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		auths:      auths,
+		tokens:     map[string]string{},
+	}, nil
+}
+
+// Manifest is a manifest served by a registry.
+type Manifest struct {
+	MediaType string
+	Digest    digest.Digest
+	Data      []byte
+}
+
+// Manifest returns the manifest of ref. The manifests of the references by
+// digest are verified against their digest.
+func (c *Client) Manifest(ctx context.Context, ref dockerref.Named) (*Manifest, error) {
+	header := http.Header{"Accept": []string{strings.Join(ManifestMediaTypes, ", ")}}
+	resp, err := c.do(ctx, http.MethodGet, ref, "manifests/"+manifestReference(ref), header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the manifest of %s", ref)
+	}
+
+	manifest := &Manifest{
+		MediaType: resp.Header.Get("Content-Type"),
+		Digest:    digest.FromBytes(data),
+		Data:      data,
+	}
+	if r, ok := ref.(dockerref.Digested); ok && r.Digest() != manifest.Digest {
+		return nil, errors.Errorf("the manifest of %s has digest %s", ref, manifest.Digest)
+	}
+	return manifest, nil
+}
+
+// ManifestExists returns whether the registry of ref has its manifest,
+// without downloading it.
+func (c *Client) ManifestExists(ctx context.Context, ref dockerref.Named) (bool, error) {
+	header := http.Header{"Accept": []string{strings.Join(ManifestMediaTypes, ", ")}}
+	resp, err := c.do(ctx, http.MethodHead, ref, "manifests/"+manifestReference(ref), header)
+	if err != nil {
+		if errors.Cause(err) == ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// Blob returns the blob of digest dgst in the repository of ref. The caller
+// closes it.
+func (c *Client) Blob(ctx context.Context, ref dockerref.Named, dgst digest.Digest) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, ref, "blobs/"+dgst.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// manifestReference returns the digest or else the tag of ref, defaulting to
+// latest.
+func manifestReference(ref dockerref.Named) string {
+	switch r := ref.(type) {
+	case dockerref.Digested:
+		return r.Digest().String()
+	case dockerref.Tagged:
+		return r.Tag()
+	}
+	return "latest"
+}
+
+// Get returns the body of address, fetched with the trust bundle of the
+// client but without credentials.
+func (c *Client) Get(ctx context.Context, address string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := statusError(req, resp); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// endpoint returns the host serving the registry of domain.
+func endpoint(domain string) string {
+	if domain == "docker.io" {
+		return "registry-1.docker.io"
+	}
+	return domain
+}
+
+// do sends a request for path under the repository of ref, authenticating
+// when the registry challenges it.
+func (c *Client) do(ctx context.Context, method string, ref dockerref.Named, path string, header http.Header) (*http.Response, error) {
+	domain, repository := dockerref.Domain(ref), dockerref.Path(ref)
+	address := fmt.Sprintf("https://%s/v2/%s/%s", endpoint(domain), repository, path)
+	tokenKey := domain + "/" + repository
+
+	send := func() (*http.Response, *http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, address, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		c.mu.Lock()
+		if authorization, ok := c.tokens[tokenKey]; ok {
+			req.Header.Set("Authorization", authorization)
+		}
+		c.mu.Unlock()
+		resp, err := c.httpClient.Do(req)
+		return resp, req, err
+	}
+
+	resp, req, err := send()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err := c.authorize(ctx, domain, repository, challenge)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to authenticate to %s", domain)
+		}
+		c.mu.Lock()
+		c.tokens[tokenKey] = authorization
+		c.mu.Unlock()
+		if resp, req, err = send(); err != nil {
+			return nil, err
+		}
+	}
+	if err := statusError(req, resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// authorize returns the Authorization header answering the challenge of the
+// registry of domain for the repository.
+func (c *Client) authorize(ctx context.Context, domain, repository, challenge string) (string, error) {
+	auth := c.auths[domain]
+	if auth == "" && domain == "docker.io" {
+		auth = c.auths["https://index.docker.io/v1/"]
+	}
+
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if auth == "" {
+			return "", errors.New("no credentials in the pull secret")
+		}
+		return "Basic " + auth, nil
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || params["realm"] == "" {
+			return "", errors.Errorf("invalid realm %q", params["realm"])
+		}
+		query := realm.Query()
+		if service := params["service"]; service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", fmt.Sprintf("repository:%s:pull", repository))
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", "Basic "+auth)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if err := statusError(req, resp); err != nil {
+			return "", err
+		}
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", errors.Wrap(err, "failed to decode the token")
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		if token.Token == "" {
+			return "", errors.New("no token in the response of the token service")
+		}
+		return "Bearer " + token.Token, nil
+	default:
+		return "", errors.Errorf("unsupported authentication challenge %q", challenge)
+	}
+}
+
+// parseChallenge returns the scheme and the parameters of a WWW-Authenticate
+// challenge such as Bearer realm="https://auth.example.com/token",service="registry".
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	if len(parts) == 2 {
+		for _, param := range strings.Split(parts[1], ",") {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 {
+				params[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+			}
+		}
+	}
+	return parts[0], params
+}
+
+// statusError returns an error for the responses that are not successful,
+// caused by ErrNotFound for the responses with status 404.
+func statusError(req *http.Request, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.Wrapf(ErrNotFound, "%s %s", req.Method, req.URL)
+	}
+	return errors.Errorf("%s %s: %s", req.Method, req.URL, resp.Status)
+}
@@ -0,0 +1,127 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/registry/registrytest"
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestLocations(t *testing.T) {
+	sources := []types.ImageContentSource{{
+		Source:  "quay.io/openshift-release-dev",
+		Mirrors: []string{"mirror.example.com:5000/ocp", "backup.example.com/ocp"},
+	}, {
+		Source:  "quay.io/other",
+		Mirrors: []string{"mirror.example.com:5000/other"},
+	}}
+	cases := []struct {
+		ref       string
+		locations []string
+	}{{
+		ref: "quay.io/openshift-release-dev/ocp-release:4.7.0-x86_64",
+		locations: []string{
+			"mirror.example.com:5000/ocp/ocp-release:4.7.0-x86_64",
+			"backup.example.com/ocp/ocp-release:4.7.0-x86_64",
+			"quay.io/openshift-release-dev/ocp-release:4.7.0-x86_64",
+		},
+	}, {
+		ref: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		locations: []string{
+			"mirror.example.com:5000/ocp/ocp-v4.0-art-dev@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			"backup.example.com/ocp/ocp-v4.0-art-dev@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			"quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		},
+	}, {
+		ref:       "quay.io/openshift-release-dev-other/ocp-release:4.7.0-x86_64",
+		locations: []string{"quay.io/openshift-release-dev-other/ocp-release:4.7.0-x86_64"},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.ref, func(t *testing.T) {
+			ref, err := dockerref.ParseNamed(tc.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			locations, err := Locations(ref, sources)
+			if !assert.NoError(t, err) {
+				return
+			}
+			var actual []string
+			for _, l := range locations {
+				actual = append(actual, l.String())
+			}
+			assert.Equal(t, tc.locations, actual)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	registry := registrytest.New()
+	defer registry.Close()
+	manifest := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json"}`)
+	dgst := registry.AddManifest("mirror/release", "4.7.0", manifest)
+
+	client, err := NewClient(registry.PullSecret, registry.CA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := []types.ImageContentSource{{
+		Source:  registry.Host + "/ocp/release",
+		Mirrors: []string{registry.Host + "/mirror/release"},
+	}}
+
+	ref, err := dockerref.ParseNamed(registry.Host + "/ocp/release:4.7.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, location, err := client.Resolve(context.Background(), ref, sources)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, dgst, resolved.Digest)
+	assert.Equal(t, "application/vnd.docker.distribution.manifest.v2+json", resolved.MediaType)
+	assert.Equal(t, registry.Host+"/mirror/release:4.7.0", location.String())
+
+	// Only the source is tried without image content sources.
+	_, _, err = client.Resolve(context.Background(), ref, nil)
+	assert.Regexp(t, `^failed to resolve .*/ocp/release:4.7.0: GET https://.*/v2/ocp/release/manifests/4.7.0: not found$`, err)
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+
+	// The repository itself is usually not reachable when it has mirrors.
+	unreachable, err := dockerref.ParseNamed("127.0.0.1:1/ocp/release:4.7.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = client.Resolve(context.Background(), unreachable, []types.ImageContentSource{{
+		Source:  "127.0.0.1:1/ocp/release",
+		Mirrors: []string{registry.Host + "/empty/release"},
+	}})
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+	_, _, err = client.Resolve(context.Background(), unreachable, nil)
+	assert.NotEqual(t, ErrNotFound, errors.Cause(err))
+
+	// A bad pull secret cannot get a token.
+	badClient, err := NewClient(`{"auths":{}}`, registry.CA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirror, err := dockerref.ParseNamed(registry.Host + "/mirror/release:4.7.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = badClient.Manifest(context.Background(), mirror)
+	assert.Regexp(t, `^failed to authenticate to .*: GET https://.*/token\?.*: 401 Unauthorized$`, err)
+
+	// Unknown digests are not found.
+	other, err := dockerref.WithDigest(mirror, "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Manifest(context.Background(), other)
+	assert.Equal(t, ErrNotFound, errors.Cause(err))
+}
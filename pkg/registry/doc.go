@@ -0,0 +1,3 @@
+// Package registry queries container registries through the Docker Registry
+// HTTP API V2, with the credentials of a pull secret.
+package registry
@@ -0,0 +1,93 @@
+package registry
+
+import (
+	"context"
+	"strings"
+
+	dockerref "github.com/containers/image/docker/reference"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// InSource returns whether ref is in the repository, or under the namespace,
+// of source.
+func InSource(ref dockerref.Named, source string) bool {
+	name := ref.Name()
+	return name == source || strings.HasPrefix(name, source+"/")
+}
+
+// MirrorOf returns the reference to ref, which is in source, in mirror. The
+// reference keeps the digest or tag of ref.
+func MirrorOf(ref dockerref.Named, source, mirror string) (dockerref.Named, error) {
+	location, err := dockerref.ParseNamed(mirror + strings.TrimPrefix(ref.Name(), source))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid mirror %s of %s", mirror, source)
+	}
+	switch r := ref.(type) {
+	case dockerref.Digested:
+		return dockerref.WithDigest(location, r.Digest())
+	case dockerref.Tagged:
+		return dockerref.WithTag(location, r.Tag())
+	}
+	return location, nil
+}
+
+// Locations returns the references to pull ref from: the references to its
+// mirrors in the image content sources, in order, then ref itself.
+func Locations(ref dockerref.Named, sources []types.ImageContentSource) ([]dockerref.Named, error) {
+	var locations []dockerref.Named
+	for _, source := range sources {
+		if !InSource(ref, source.Source) {
+			continue
+		}
+		for _, mirror := range source.Mirrors {
+			location, err := MirrorOf(ref, source.Source, mirror)
+			if err != nil {
+				return nil, err
+			}
+			locations = append(locations, location)
+		}
+	}
+	return append(locations, ref), nil
+}
+
+// Resolve returns the manifest of ref from the first of its locations
+// serving it, with that location. The error is caused by ErrNotFound when
+// the mirrors of ref, or ref itself when it has no mirrors, do not have it.
+func (c *Client) Resolve(ctx context.Context, ref dockerref.Named, sources []types.ImageContentSource) (*Manifest, dockerref.Named, error) {
+	locations, err := Locations(ref, sources)
+	if err != nil {
+		return nil, nil, err
+	}
+	var failures []string
+	notFound := true
+	for i, location := range locations {
+		manifest, err := c.Manifest(ctx, location)
+		if err == nil {
+			return manifest, location, nil
+		}
+		failures = append(failures, err.Error())
+		// The repository itself is usually not reachable when it has
+		// mirrors.
+		if (i < len(locations)-1 || len(locations) == 1) && errors.Cause(err) != ErrNotFound {
+			notFound = false
+		}
+	}
+	err = errors.Errorf("failed to resolve %s: %s", ref, strings.Join(failures, "; "))
+	if notFound {
+		err = &causedError{error: err, cause: ErrNotFound}
+	}
+	return nil, nil, err
+}
+
+// causedError is an error with the cause of its message.
+type causedError struct {
+	error
+	cause error
+}
+
+// Cause returns the cause of the error.
+func (e *causedError) Cause() error {
+	return e.cause
+}
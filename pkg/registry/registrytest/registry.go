@@ -0,0 +1,143 @@
+// Package registrytest serves fake registries for tests.
+package registrytest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+const (
+	user     = "user"
+	password = "password"
+	token    = "registrytest-token"
+)
+
+// Registry is a registry serving the manifests and blobs added to it over
+// TLS, to the clients holding a token from its token service. It also
+// serves the files added to it, without authentication.
+type Registry struct {
+	*httptest.Server
+
+	// Host is the host:port of the registry.
+	Host string
+
+	// CA is the PEM-encoded certificate of the registry, to trust.
+	CA string
+
+	// PullSecret holds the credentials for the token service.
+	PullSecret string
+
+	mu        sync.Mutex
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	files     map[string][]byte
+}
+
+// New starts a registry. The caller closes it.
+func New() *Registry {
+	r := &Registry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+		files:     map[string][]byte{},
+	}
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(r.serve))
+	r.Host = strings.TrimPrefix(r.Server.URL, "https://")
+	r.CA = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: r.Server.Certificate().Raw}))
+	r.PullSecret = fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, r.Host, base64.StdEncoding.EncodeToString([]byte(user+":"+password)))
+	return r
+}
+
+// AddManifest adds the manifest data to repository, under tag when it is
+// not empty, and returns its digest.
+func (r *Registry) AddManifest(repository, tag string, data []byte) digest.Digest {
+	dgst := digest.FromBytes(data)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.manifests[repository+"@"+dgst.String()] = data
+	if tag != "" {
+		r.manifests[repository+":"+tag] = data
+	}
+	return dgst
+}
+
+// AddBlob adds the blob data to repository and returns its digest.
+func (r *Registry) AddBlob(repository string, data []byte) digest.Digest {
+	dgst := digest.FromBytes(data)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.blobs[repository+"@"+dgst.String()] = data
+	return dgst
+}
+
+// AddFile serves data at path.
+func (r *Registry) AddFile(path string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files[path] = data
+}
+
+func (r *Registry) serve(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req.URL.Path == "/token" {
+		if u, p, ok := req.BasicAuth(); !ok || u != user || p != password {
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": token})
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if path == req.URL.Path {
+		if data, ok := r.files[req.URL.Path]; ok {
+			w.Write(data)
+			return
+		}
+		http.NotFound(w, req)
+		return
+	}
+
+	if req.Header.Get("Authorization") != "Bearer "+token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registrytest"`, r.Server.URL))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var data []byte
+	var ok bool
+	if i := strings.LastIndex(path, "/manifests/"); i >= 0 {
+		repository, reference := path[:i], path[i+len("/manifests/"):]
+		separator := ":"
+		if strings.Contains(reference, ":") {
+			separator = "@"
+		}
+		data, ok = r.manifests[repository+separator+reference]
+		if ok {
+			var manifest struct {
+				MediaType string `json:"mediaType"`
+			}
+			json.Unmarshal(data, &manifest)
+			w.Header().Set("Content-Type", manifest.MediaType)
+		}
+	} else if i := strings.LastIndex(path, "/blobs/"); i >= 0 {
+		data, ok = r.blobs[path[:i]+"@"+path[i+len("/blobs/"):]]
+	}
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+	if req.Method != http.MethodHead {
+		w.Write(data)
+	}
+}
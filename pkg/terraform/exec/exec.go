@@ -3,6 +3,7 @@ package exec
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -32,7 +33,7 @@ var commands = map[string]cmdFunc{
 	},
 }
 
-func runner(cmd string, dir string, args []string, stdout, stderr io.Writer) int {
+func runner(ctx context.Context, cmd string, dir string, args []string, stdout, stderr io.Writer) int {
 	lf := ioutil.Discard
 	if level := logging.LogLevel(); level != "" {
 		lf = &logutils.LevelFilter{
@@ -47,7 +48,7 @@ func runner(cmd string, dir string, args []string, stdout, stderr io.Writer) int
 	// Make sure we clean up any managed plugins at the end of this
 	defer plugin.CleanupClients()
 
-	sdCh, cancel := makeShutdownCh()
+	sdCh, cancel := makeShutdownCh(ctx)
 	defer cancel()
 
 	pluginDirs, err := globalPluginDirs(dir)
@@ -93,25 +94,34 @@ func runner(cmd string, dir string, args []string, stdout, stderr io.Writer) int
 }
 
 // Apply is wrapper around `terraform apply` subcommand.
-func Apply(datadir string, args []string, stdout, stderr io.Writer) int {
-	return runner("apply", datadir, args, stdout, stderr)
+// Cancelling ctx asks terraform to stop gracefully.
+func Apply(ctx context.Context, datadir string, args []string, stdout, stderr io.Writer) int {
+	return runner(ctx, "apply", datadir, args, stdout, stderr)
 }
 
 // Destroy is wrapper around `terraform destroy` subcommand.
-func Destroy(datadir string, args []string, stdout, stderr io.Writer) int {
-	return runner("destroy", datadir, args, stdout, stderr)
+// Cancelling ctx asks terraform to stop gracefully.
+func Destroy(ctx context.Context, datadir string, args []string, stdout, stderr io.Writer) int {
+	return runner(ctx, "destroy", datadir, args, stdout, stderr)
 }
 
 // Init is wrapper around `terraform init` subcommand.
-func Init(datadir string, args []string, stdout, stderr io.Writer) int {
-	return runner("init", datadir, args, stdout, stderr)
+// Cancelling ctx asks terraform to stop gracefully.
+func Init(ctx context.Context, datadir string, args []string, stdout, stderr io.Writer) int {
+	return runner(ctx, "init", datadir, args, stdout, stderr)
 }
 
 // makeShutdownCh creates an interrupt listener and returns a channel.
-// A message will be sent on the channel for every interrupt received.
-func makeShutdownCh() (<-chan struct{}, func()) {
+// A single message is sent on the channel for the first interrupt
+// received or when ctx is done, whichever comes first. One interrupt
+// both cancels the installer's context and reaches this listener, and
+// terraform treats a second message as a request to abort without
+// waiting for in-flight operations, so the listener stops after the
+// first one. Later interrupts get the default handling, which aborts.
+func makeShutdownCh(ctx context.Context) (<-chan struct{}, func()) {
 	resultCh := make(chan struct{})
 	signalCh := make(chan os.Signal, 4)
+	stopCh := make(chan struct{})
 
 	handle := []os.Signal{}
 	handle = append(handle, ignoreSignals...)
@@ -119,13 +129,24 @@ func makeShutdownCh() (<-chan struct{}, func()) {
 
 	signal.Notify(signalCh, handle...)
 	go func() {
-		for {
-			<-signalCh
-			resultCh <- struct{}{}
+		select {
+		case <-signalCh:
+		case <-ctx.Done():
+		case <-stopCh:
+			return
+		}
+		signal.Stop(signalCh)
+		select {
+		case resultCh <- struct{}{}:
+		case <-stopCh:
 		}
 	}()
 
-	return resultCh, func() { signal.Reset(handle...) }
+	return resultCh, func() {
+		// Stop rather than Reset, so handlers installed by the caller keep working.
+		signal.Stop(signalCh)
+		close(stopCh)
+	}
 }
 
 // suppressedUI suppresses the Ui's warnings from error to
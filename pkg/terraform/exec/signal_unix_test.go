@@ -0,0 +1,42 @@
+// +build !windows
+
+package exec
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShutdownChForwardsOnce(t *testing.T) {
+	// Keep SIGTERM from terminating the test once the listener stops.
+	testCh := make(chan os.Signal, 1)
+	signal.Notify(testCh, syscall.SIGTERM)
+	defer signal.Stop(testCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sdCh, stop := makeShutdownCh(ctx)
+	defer stop()
+
+	// The installer cancels its context on the signal it also forwards to
+	// terraform, so the signal may still be pending once ctx is done.
+	cancel()
+	select {
+	case <-sdCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no shutdown message")
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	<-testCh
+
+	select {
+	case <-sdCh:
+		t.Fatal("a second shutdown message aborts terraform")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,160 @@
+package terraform
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// OverridesDirName is the name of the directory, in both the asset
+// directory and the Terraform working directory, holding user-supplied
+// Terraform files.  Files at the top of the directory extend the root
+// module, files in a subdirectory extend the platform module with the
+// same name or define a new module.
+const OverridesDirName = "infrastructure"
+
+var outputSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{
+		Type:       "output",
+		LabelNames: []string{"name"},
+	}},
+}
+
+// isTerraformFile returns true if Terraform loads the named file as
+// part of a module.
+func isTerraformFile(name string) bool {
+	return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")
+}
+
+// copyOverrides copies the user-supplied Terraform files from the
+// overrides directory in dir next to the unpacked platform modules.  It
+// refuses to replace unpacked files and to redefine outputs of the
+// unpacked modules, because the installer relies on them.
+func copyOverrides(dir string) error {
+	src := filepath.Join(dir, OverridesDirName)
+	if _, err := os.Stat(src); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	outputs := map[string]map[string]bool{}
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isTerraformFile(info.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		dst := filepath.Join(dir, rel)
+		if existing, err := ioutil.ReadFile(dst); err == nil {
+			if bytes.Equal(existing, data) {
+				// Copied by an earlier run in the same directory.
+				return nil
+			}
+			return errors.Errorf("%s would replace an installer-managed Terraform file", filepath.Join(OverridesDirName, rel))
+		}
+
+		module := filepath.Dir(rel)
+		core, ok := outputs[module]
+		if !ok {
+			core, err = moduleOutputs(filepath.Join(dir, module))
+			if err != nil {
+				return errors.Wrapf(err, "failed to read outputs of Terraform module %q", module)
+			}
+			outputs[module] = core
+		}
+		defined, err := fileOutputs(rel, data)
+		if err != nil {
+			return err
+		}
+		for _, name := range defined {
+			if core[name] {
+				return errors.Errorf("%s must not redefine the output %q", filepath.Join(OverridesDirName, rel), name)
+			}
+		}
+
+		logrus.Debugf("Adding user-supplied Terraform file %s", rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(dst, data, 0666)
+	})
+}
+
+// moduleOutputs returns the names of the outputs defined by the
+// Terraform files directly in dir.  It returns an empty set if dir does
+// not exist, which is the case for modules added by the user.
+func moduleOutputs(dir string) (map[string]bool, error) {
+	outputs := map[string]bool{}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return outputs, nil
+		}
+		return nil, err
+	}
+	for _, info := range infos {
+		if info.IsDir() || !isTerraformFile(info.Name()) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		names, err := fileOutputs(info.Name(), data)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			outputs[name] = true
+		}
+	}
+	return outputs, nil
+}
+
+// fileOutputs returns the names of the outputs defined in a Terraform
+// file.
+func fileOutputs(filename string, data []byte) ([]string, error) {
+	parser := hclparse.NewParser()
+	var (
+		file  *hcl.File
+		diags hcl.Diagnostics
+	)
+	if strings.HasSuffix(filename, ".json") {
+		file, diags = parser.ParseJSON(data, filename)
+	} else {
+		file, diags = parser.ParseHCL(data, filename)
+	}
+	if diags.HasErrors() {
+		return nil, errors.Wrapf(diags, "failed to parse %s", filename)
+	}
+
+	content, _, diags := file.Body.PartialContent(outputSchema)
+	if diags.HasErrors() {
+		return nil, errors.Wrapf(diags, "failed to parse %s", filename)
+	}
+	names := make([]string, 0, len(content.Blocks))
+	for _, block := range content.Blocks {
+		names = append(names, block.Labels[0])
+	}
+	return names, nil
+}
@@ -0,0 +1,105 @@
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyOverrides(t *testing.T) {
+	cases := []struct {
+		name      string
+		overrides map[string]string
+		expected  []string
+		err       string
+	}{{
+		name: "no overrides",
+	}, {
+		name: "root and module overrides",
+		overrides: map[string]string{
+			"tags_override.tf":      `locals { tags = { team = "security" } }`,
+			"vpc/sg_override.tf":    `resource "aws_security_group_rule" "extra" {}`,
+			"audit/main.tf":         `output "audit_bucket" { value = "bucket" }`,
+			"vpc/extra.tf.json":     `{"output": {"extra_id": {"value": "id"}}}`,
+			"vpc/README.md":         `ignored`,
+			"tags_override.tf.orig": `ignored`,
+		},
+		expected: []string{
+			"tags_override.tf",
+			"vpc/sg_override.tf",
+			"audit/main.tf",
+			"vpc/extra.tf.json",
+		},
+	}, {
+		name: "redefined output",
+		overrides: map[string]string{
+			"vpc/outputs_override.tf": `output "vpc_id" { value = "vpc-1234" }`,
+		},
+		err: `^infrastructure/vpc/outputs_override\.tf must not redefine the output "vpc_id"$`,
+	}, {
+		name: "redefined output in JSON",
+		overrides: map[string]string{
+			"vpc/outputs_override.tf.json": `{"output": {"vpc_id": {"value": "vpc-1234"}}}`,
+		},
+		err: `^infrastructure/vpc/outputs_override\.tf\.json must not redefine the output "vpc_id"$`,
+	}, {
+		name: "replaced file",
+		overrides: map[string]string{
+			"vpc/outputs.tf": `output "other" { value = "other" }`,
+		},
+		err: `^infrastructure/vpc/outputs\.tf would replace an installer-managed Terraform file$`,
+	}, {
+		name: "invalid file",
+		overrides: map[string]string{
+			"broken_override.tf": `resource "aws_instance" {`,
+		},
+		err: `^failed to parse broken_override\.tf: `,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "openshift-install-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			files := map[string]string{
+				"main.tf":        `module "vpc" { source = "./vpc" }`,
+				"vpc/outputs.tf": `output "vpc_id" { value = "vpc" }`,
+			}
+			for name, data := range tc.overrides {
+				files[filepath.Join(OverridesDirName, name)] = data
+			}
+			for name, data := range files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(path, []byte(data), 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = copyOverrides(dir)
+			if tc.err != "" {
+				assert.Regexp(t, tc.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			for _, name := range tc.expected {
+				data, err := ioutil.ReadFile(filepath.Join(dir, name))
+				if assert.NoError(t, err) {
+					assert.Equal(t, tc.overrides[name], string(data))
+				}
+			}
+			_, err = os.Stat(filepath.Join(dir, "vpc", "README.md"))
+			assert.True(t, os.IsNotExist(err), "only Terraform files should be copied")
+
+			// Unpacking again into the same directory must not fail.
+			assert.NoError(t, copyOverrides(dir))
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/lineprinter"
+)
+
+// ResourceAction is the kind of change Terraform is making to a resource.
+type ResourceAction string
+
+const (
+	// ResourceCreate is used when Terraform creates a resource.
+	ResourceCreate ResourceAction = "create"
+	// ResourceModify is used when Terraform updates a resource in place.
+	ResourceModify ResourceAction = "modify"
+	// ResourceDestroy is used when Terraform destroys a resource.
+	ResourceDestroy ResourceAction = "destroy"
+)
+
+// ResourceEvent describes a single resource that completed during a
+// Terraform run.
+type ResourceEvent struct {
+	// Address is the Terraform address of the resource, e.g.
+	// module.vpc.aws_vpc.new_vpc[0].
+	Address string
+	// Action is the change Terraform made to the resource.
+	Action ResourceAction
+	// Duration is how long Terraform reported the change took.
+	Duration time.Duration
+}
+
+var (
+	planRE     = regexp.MustCompile(`^Plan: (\d+) to add, (\d+) to change, (\d+) to destroy\.`)
+	startRE    = regexp.MustCompile(`^(\S+): (Creating|Modifying|Destroying)\.\.\.`)
+	completeRE = regexp.MustCompile(`^(\S+): (Creation|Modifications|Destruction) complete after (\S+)`)
+)
+
+// defaultProgressInterval is the minimum time between two info-level
+// progress summaries.
+const defaultProgressInterval = 30 * time.Second
+
+// progress parses the output of 'terraform apply' and 'terraform
+// destroy', tracks the resources that are in flight, and periodically
+// logs a summary at info level.  Every line is still passed through to
+// the wrapped printer.
+type progress struct {
+	// WrappedPrint receives every line unchanged.
+	WrappedPrint lineprinter.Print
+	// Summary receives the periodic progress summaries.
+	Summary lineprinter.Print
+	// OnComplete, if set, is called for every resource that completes.
+	OnComplete func(ResourceEvent)
+	// Interval is the minimum time between two summaries.
+	Interval time.Duration
+
+	now func() time.Time
+
+	mu          sync.Mutex
+	total       int
+	completed   int
+	inFlight    map[string]time.Time
+	lastSummary time.Time
+}
+
+func newProgress(wrapped lineprinter.Print, onComplete func(ResourceEvent)) *progress {
+	return &progress{
+		WrappedPrint: wrapped,
+		Summary:      logrus.Info,
+		OnComplete:   onComplete,
+		Interval:     defaultProgressInterval,
+		now:          time.Now,
+		inFlight:     map[string]time.Time{},
+	}
+}
+
+// Print parses the line and passes it through to WrappedPrint.
+func (p *progress) Print(args ...interface{}) {
+	if len(args) > 0 {
+		if line, ok := args[len(args)-1].(string); ok {
+			p.parse(strings.TrimSpace(line))
+		}
+	}
+	p.WrappedPrint(args...)
+}
+
+func (p *progress) parse(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if m := planRE.FindStringSubmatch(line); m != nil {
+		p.total = 0
+		for _, s := range m[1:] {
+			n, _ := strconv.Atoi(s)
+			p.total += n
+		}
+		return
+	}
+
+	if m := startRE.FindStringSubmatch(line); m != nil {
+		p.inFlight[m[1]] = now
+		return
+	}
+
+	m := completeRE.FindStringSubmatch(line)
+	if m == nil {
+		if len(p.inFlight) > 0 {
+			p.maybeSummarize(now)
+		}
+		return
+	}
+
+	address := m[1]
+	delete(p.inFlight, address)
+	p.completed++
+	duration, err := time.ParseDuration(m[3])
+	if err != nil {
+		duration = 0
+	}
+	if p.OnComplete != nil {
+		p.OnComplete(ResourceEvent{
+			Address:  address,
+			Action:   completeActions[m[2]],
+			Duration: duration,
+		})
+	}
+	p.maybeSummarize(now)
+}
+
+var completeActions = map[string]ResourceAction{
+	"Creation":      ResourceCreate,
+	"Modifications": ResourceModify,
+	"Destruction":   ResourceDestroy,
+}
+
+// maybeSummarize logs a summary if at least Interval has passed since
+// the previous one.  It must be called with p.mu held.
+func (p *progress) maybeSummarize(now time.Time) {
+	if p.lastSummary.IsZero() {
+		// Don't summarize right away, give the first resources a chance to complete.
+		p.lastSummary = now
+		return
+	}
+	if now.Sub(p.lastSummary) < p.Interval {
+		return
+	}
+	p.lastSummary = now
+	p.Summary(p.summary(now))
+}
+
+// summary returns a human-friendly description of the current
+// progress.  It must be called with p.mu held.
+func (p *progress) summary(now time.Time) string {
+	msg := fmt.Sprintf("%d resources complete", p.completed)
+	if p.total > 0 {
+		msg = fmt.Sprintf("%d of %d resources complete", p.completed, p.total)
+	}
+	if len(p.inFlight) == 0 {
+		return msg
+	}
+
+	addresses := make([]string, 0, len(p.inFlight))
+	for address := range p.inFlight {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		ti, tj := p.inFlight[addresses[i]], p.inFlight[addresses[j]]
+		if ti.Equal(tj) {
+			return addresses[i] < addresses[j]
+		}
+		return ti.Before(tj)
+	})
+	slowest := addresses[0]
+	return fmt.Sprintf("%s, waiting on %s (%s elapsed)", msg, slowest, now.Sub(p.inFlight[slowest]).Round(time.Second))
+}
+
+// moduleTimings aggregates the completed resources into one timer stage per
+// top-level module and action, spanning from the start of the first
+// resource of the module to the completion of the last one, so that the
+// timer summary stays short and the resources created in parallel are not
+// added up.
+type moduleTimings struct {
+	// Record receives the duration of the stage every time it grows.
+	Record func(stage string, duration time.Duration)
+
+	now func() time.Time
+
+	mu    sync.Mutex
+	spans map[string]*moduleSpan
+}
+
+type moduleSpan struct {
+	start, end time.Time
+}
+
+func newModuleTimings(record func(string, time.Duration)) *moduleTimings {
+	return &moduleTimings{
+		Record: record,
+		now:    time.Now,
+		spans:  map[string]*moduleSpan{},
+	}
+}
+
+// Add extends the stage of the module of the resource to cover it.
+func (m *moduleTimings) Add(event ResourceEvent) {
+	stage := "Terraform " + resourceModule(event.Address)
+	if event.Action != ResourceCreate {
+		stage = fmt.Sprintf("%s (%s)", stage, event.Action)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	end := m.now()
+	start := end.Add(-event.Duration)
+	span, ok := m.spans[stage]
+	if !ok {
+		span = &moduleSpan{start: start, end: end}
+		m.spans[stage] = span
+	}
+	if start.Before(span.start) {
+		span.start = start
+	}
+	if end.After(span.end) {
+		span.end = end
+	}
+	m.Record(stage, span.end.Sub(span.start))
+}
+
+// resourceModule returns the top-level module of a resource address, e.g.
+// module.vpc for module.vpc.aws_vpc.new_vpc[0], or the root module.
+func resourceModule(address string) string {
+	parts := strings.SplitN(address, ".", 3)
+	if len(parts) < 3 || parts[0] != "module" {
+		return "root module"
+	}
+	name := parts[1]
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	return "module." + name
+}
@@ -0,0 +1,105 @@
+package terraform
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var (
+		passed    []string
+		summaries []string
+		events    []ResourceEvent
+	)
+	p := newProgress(func(args ...interface{}) {
+		passed = append(passed, fmt.Sprint(args...))
+	}, func(event ResourceEvent) {
+		events = append(events, event)
+	})
+	p.Summary = func(args ...interface{}) {
+		summaries = append(summaries, fmt.Sprint(args...))
+	}
+	p.now = func() time.Time { return now }
+
+	lines := []struct {
+		line    string
+		advance time.Duration
+	}{
+		{line: "Plan: 3 to add, 0 to change, 1 to destroy."},
+		{line: "aws_vpc.new_vpc[0]: Creating..."},
+		{line: "module.bootstrap.aws_instance.bootstrap: Creating..."},
+		{line: "aws_vpc.new_vpc[0]: Creation complete after 2s [id=vpc-1234]", advance: 2 * time.Second},
+		{line: "module.bootstrap.aws_instance.bootstrap: Still creating... [30s elapsed]", advance: 30 * time.Second},
+		{line: "aws_route53_record.api: Destroying... [id=Z1234_api]"},
+		{line: "aws_route53_record.api: Destruction complete after 1m5s", advance: 65 * time.Second},
+		{line: "module.bootstrap.aws_instance.bootstrap: Creation complete after 1m40s [id=i-1234]", advance: 5 * time.Second},
+	}
+	for _, l := range lines {
+		now = now.Add(l.advance)
+		p.Print(l.line)
+	}
+
+	expectedPassed := make([]string, 0, len(lines))
+	for _, l := range lines {
+		expectedPassed = append(expectedPassed, l.line)
+	}
+	assert.Equal(t, expectedPassed, passed)
+
+	assert.Equal(t, []ResourceEvent{{
+		Address:  "aws_vpc.new_vpc[0]",
+		Action:   ResourceCreate,
+		Duration: 2 * time.Second,
+	}, {
+		Address:  "aws_route53_record.api",
+		Action:   ResourceDestroy,
+		Duration: 65 * time.Second,
+	}, {
+		Address:  "module.bootstrap.aws_instance.bootstrap",
+		Action:   ResourceCreate,
+		Duration: 100 * time.Second,
+	}}, events)
+
+	assert.Equal(t, []string{
+		"1 of 4 resources complete, waiting on module.bootstrap.aws_instance.bootstrap (32s elapsed)",
+		"2 of 4 resources complete, waiting on module.bootstrap.aws_instance.bootstrap (1m37s elapsed)",
+	}, summaries)
+}
+
+func TestModuleTimings(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	stages := map[string]time.Duration{}
+	var order []string
+	m := newModuleTimings(func(stage string, duration time.Duration) {
+		if _, ok := stages[stage]; !ok {
+			order = append(order, stage)
+		}
+		stages[stage] = duration
+	})
+	m.now = func() time.Time { return now }
+
+	events := []struct {
+		event   ResourceEvent
+		advance time.Duration
+	}{
+		{event: ResourceEvent{Address: "module.vpc.aws_vpc.new_vpc[0]", Action: ResourceCreate, Duration: 2 * time.Second}, advance: 2 * time.Second},
+		{event: ResourceEvent{Address: "module.vpc.aws_subnet.private_subnet[0]", Action: ResourceCreate, Duration: 10 * time.Second}, advance: 10 * time.Second},
+		{event: ResourceEvent{Address: "module.vpc.aws_subnet.private_subnet[1]", Action: ResourceCreate, Duration: 9 * time.Second}},
+		{event: ResourceEvent{Address: "module.masters[0].aws_instance.master", Action: ResourceCreate, Duration: 40 * time.Second}, advance: 30 * time.Second},
+		{event: ResourceEvent{Address: "aws_route53_record.api", Action: ResourceDestroy, Duration: 5 * time.Second}, advance: 5 * time.Second},
+	}
+	for _, e := range events {
+		now = now.Add(e.advance)
+		m.Add(e.event)
+	}
+
+	assert.Equal(t, []string{"Terraform module.vpc", "Terraform module.masters", "Terraform root module (destroy)"}, order)
+	assert.Equal(t, map[string]time.Duration{
+		"Terraform module.vpc":            12 * time.Second,
+		"Terraform module.masters":        40 * time.Second,
+		"Terraform root module (destroy)": 5 * time.Second,
+	}, stages)
+}
@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -13,6 +14,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/lineprinter"
+	"github.com/openshift/installer/pkg/metrics/gatherer"
+	"github.com/openshift/installer/pkg/metrics/timer"
 	texec "github.com/openshift/installer/pkg/terraform/exec"
 	"github.com/openshift/installer/pkg/terraform/exec/plugins"
 )
@@ -29,8 +32,11 @@ const (
 // given directory and then runs 'terraform init' and 'terraform
 // apply'.  It returns the absolute path of the tfstate file, rooted
 // in the specified directory, along with any errors from Terraform.
-func Apply(dir string, platform string, extraArgs ...string) (path string, err error) {
-	err = unpackAndInit(dir, platform)
+// If ctx is done before Terraform completes, Terraform is stopped
+// gracefully, the state file is still returned and the error is
+// recognized by IsCancelled.
+func Apply(ctx context.Context, dir string, platform string, extraArgs ...string) (path string, err error) {
+	err = unpackAndInit(ctx, dir, platform)
 	if err != nil {
 		return "", err
 	}
@@ -45,13 +51,16 @@ func Apply(dir string, platform string, extraArgs ...string) (path string, err e
 	args = append(args, dir)
 	sf := filepath.Join(dir, StateFileName)
 
-	lpDebug := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Debug}).Print}
+	lpDebug := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: newProgress(logrus.Debug, resourceRecorder()).Print}).Print}
 	lpError := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Error}).Print}
 	defer lpDebug.Close()
 	defer lpError.Close()
 
 	errBuf := &bytes.Buffer{}
-	if exitCode := texec.Apply(dir, args, lpDebug, io.MultiWriter(errBuf, lpError)); exitCode != 0 {
+	if exitCode := texec.Apply(ctx, dir, args, lpDebug, io.MultiWriter(errBuf, lpError)); exitCode != 0 {
+		if ctx.Err() != nil {
+			return sf, errors.Wrap(ctx.Err(), "Terraform apply was interrupted")
+		}
 		return sf, errors.Wrap(Diagnose(errBuf.String()), "failed to apply Terraform")
 	}
 	return sf, nil
@@ -59,9 +68,10 @@ func Apply(dir string, platform string, extraArgs ...string) (path string, err e
 
 // Destroy unpacks the platform-specific Terraform modules into the
 // given directory and then runs 'terraform init' and 'terraform
-// destroy'.
-func Destroy(dir string, platform string, extraArgs ...string) (err error) {
-	err = unpackAndInit(dir, platform)
+// destroy'.  If ctx is done before Terraform completes, Terraform is
+// stopped gracefully and the error is recognized by IsCancelled.
+func Destroy(ctx context.Context, dir string, platform string, extraArgs ...string) (err error) {
+	err = unpackAndInit(ctx, dir, platform)
 	if err != nil {
 		return err
 	}
@@ -75,19 +85,40 @@ func Destroy(dir string, platform string, extraArgs ...string) (err error) {
 	args := append(defaultArgs, extraArgs...)
 	args = append(args, dir)
 
-	lpDebug := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Debug}).Print}
+	lpDebug := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: newProgress(logrus.Debug, resourceRecorder()).Print}).Print}
 	lpError := &lineprinter.LinePrinter{Print: (&lineprinter.Trimmer{WrappedPrint: logrus.Error}).Print}
 	defer lpDebug.Close()
 	defer lpError.Close()
 
-	if exitCode := texec.Destroy(dir, args, lpDebug, lpError); exitCode != 0 {
+	if exitCode := texec.Destroy(ctx, dir, args, lpDebug, lpError); exitCode != 0 {
+		if ctx.Err() != nil {
+			return errors.Wrap(ctx.Err(), "Terraform destroy was interrupted")
+		}
 		return errors.New("failed to destroy using Terraform")
 	}
 	return nil
 }
 
+// IsCancelled returns true if err was caused by the context passed to
+// Apply or Destroy being cancelled or reaching its deadline.
+func IsCancelled(err error) bool {
+	cause := errors.Cause(err)
+	return cause == context.Canceled || cause == context.DeadlineExceeded
+}
+
+// resourceRecorder returns a function adding the duration of the completed
+// resources of a run to the timer summary, per module.
+func resourceRecorder() func(ResourceEvent) {
+	timings := newModuleTimings(timer.AddDuration)
+	return func(event ResourceEvent) {
+		timings.Add(event)
+		gatherer.AddTerraformResource(string(event.Action))
+	}
+}
+
 // unpack unpacks the platform-specific Terraform modules into the
-// given directory.
+// given directory, along with any user-supplied Terraform files that
+// were placed in its OverridesDirName subdirectory.
 func unpack(dir string, platform string) (err error) {
 	err = data.Unpack(dir, platform)
 	if err != nil {
@@ -99,12 +130,17 @@ func unpack(dir string, platform string) (err error) {
 		return err
 	}
 
+	err = copyOverrides(dir)
+	if err != nil {
+		return errors.Wrap(err, "invalid user-supplied Terraform files")
+	}
+
 	return nil
 }
 
 // unpackAndInit unpacks the platform-specific Terraform modules into
 // the given directory and then runs 'terraform init'.
-func unpackAndInit(dir string, platform string) (err error) {
+func unpackAndInit(ctx context.Context, dir string, platform string) (err error) {
 	err = unpack(dir, platform)
 	if err != nil {
 		return errors.Wrap(err, "failed to unpack Terraform modules")
@@ -123,7 +159,10 @@ func unpackAndInit(dir string, platform string) (err error) {
 		"-get-plugins=false",
 	}
 	args = append(args, dir)
-	if exitCode := texec.Init(dir, args, lpDebug, lpError); exitCode != 0 {
+	if exitCode := texec.Init(ctx, dir, args, lpDebug, lpError); exitCode != 0 {
+		if ctx.Err() != nil {
+			return errors.Wrap(ctx.Err(), "Terraform init was interrupted")
+		}
 		return errors.New("failed to initialize Terraform")
 	}
 	return nil
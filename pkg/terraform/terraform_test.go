@@ -0,0 +1,37 @@
+package terraform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCancelled(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{{
+		name:     "cancelled",
+		err:      errors.Wrap(errors.Wrap(context.Canceled, "Terraform apply was interrupted"), "failed to create cluster"),
+		expected: true,
+	}, {
+		name:     "deadline exceeded",
+		err:      errors.Wrap(context.DeadlineExceeded, "Terraform destroy was interrupted"),
+		expected: true,
+	}, {
+		name:     "terraform failure",
+		err:      errors.Wrap(errors.New("failed to complete the change"), "failed to apply Terraform"),
+		expected: false,
+	}, {
+		name:     "no error",
+		expected: false,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsCancelled(tc.err))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package types
+
+// Authentication configures how the users log in to the cluster. When it is
+// set, the cluster has no kubeadmin user and the users log in with the
+// identity providers instead.
+type Authentication struct {
+	// IdentityProviders are the identity providers of the OAuth server of the
+	// cluster.
+	IdentityProviders []IdentityProvider `json:"identityProviders"`
+
+	// ClusterAdmins are the users and groups bound to the cluster-admin
+	// cluster role.
+	ClusterAdmins []ClusterAdmin `json:"clusterAdmins"`
+}
+
+// IdentityProvider is an identity provider of the OAuth server. Only one of
+// OpenID or HTPasswd should be set.
+type IdentityProvider struct {
+	// Name qualifies the identities of the identity provider. It must be
+	// unique.
+	Name string `json:"name"`
+
+	// OpenID authenticates the users with an OpenID Connect provider.
+	// +optional
+	OpenID *OpenIDIdentityProvider `json:"openID,omitempty"`
+
+	// HTPasswd authenticates the users with an htpasswd file.
+	// +optional
+	HTPasswd *HTPasswdIdentityProvider `json:"htpasswd,omitempty"`
+}
+
+// OpenIDIdentityProvider authenticates the users with an OpenID Connect
+// provider.
+type OpenIDIdentityProvider struct {
+	// Issuer is the URL the provider asserts as its issuer identifier. It
+	// must use the https scheme with no query or fragment.
+	Issuer string `json:"issuer"`
+
+	// ClientID is the ID of the OAuth client registered with the provider.
+	ClientID string `json:"clientID"`
+
+	// ClientSecret is the secret of the OAuth client.
+	ClientSecret string `json:"clientSecret"`
+
+	// CA is a PEM-encoded X.509 certificate bundle to verify the provider
+	// with. If empty, the system roots are used.
+	// +optional
+	CA string `json:"ca,omitempty"`
+
+	// ExtraScopes are the scopes requested in addition to the openid scope.
+	// +optional
+	ExtraScopes []string `json:"extraScopes,omitempty"`
+
+	// Claims maps the claims of the provider to the identities.
+	// +optional
+	Claims *OpenIDClaims `json:"claims,omitempty"`
+}
+
+// OpenIDClaims are the claims used for the identities of an OpenID Connect
+// provider.
+type OpenIDClaims struct {
+	// PreferredUsername are the claims used for the user name.
+	// When unset, the preferred_username claim is used.
+	// +optional
+	PreferredUsername []string `json:"preferredUsername,omitempty"`
+
+	// Name are the claims used for the display name.
+	// When unset, the name claim is used.
+	// +optional
+	Name []string `json:"name,omitempty"`
+
+	// Email are the claims used for the email address.
+	// When unset, the email claim is used.
+	// +optional
+	Email []string `json:"email,omitempty"`
+}
+
+// HTPasswdIdentityProvider authenticates the users with an htpasswd file.
+type HTPasswdIdentityProvider struct {
+	// File is the path of the htpasswd file. It is read when the manifests
+	// are generated.
+	File string `json:"file"`
+}
+
+// ClusterAdmin is a user or a group bound to the cluster-admin cluster role.
+// Only one of User or Group should be set.
+type ClusterAdmin struct {
+	// User is the name of a user.
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// Group is the name of a group.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
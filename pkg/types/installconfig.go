@@ -141,6 +141,11 @@ type InstallConfig struct {
 	// GCP: "Mint", "Passthrough", "Manual"
 	// +optional
 	CredentialsMode CredentialsMode `json:"credentialsMode,omitempty"`
+
+	// Authentication configures the identity providers the users log in with.
+	// If set, the installer does not create the kubeadmin user.
+	// +optional
+	Authentication *Authentication `json:"authentication,omitempty"`
 }
 
 // ClusterDomain returns the DNS domain that all records for a cluster must belong to.
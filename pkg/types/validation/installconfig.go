@@ -108,6 +108,9 @@ func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
 		allErrs = append(allErrs, field.NotSupported(field.NewPath("publish"), c.Publish, validPublishingStrategyValues))
 	}
 	allErrs = append(allErrs, validateCloudCredentialsMode(c.CredentialsMode, field.NewPath("credentialsMode"), c.Platform.Name())...)
+	if c.Authentication != nil {
+		allErrs = append(allErrs, validateAuthentication(c.Authentication, field.NewPath("authentication"))...)
+	}
 
 	return allErrs
 }
@@ -551,3 +554,67 @@ func validateCloudCredentialsMode(mode types.CredentialsMode, fldPath *field.Pat
 	}
 	return allErrs
 }
+
+func validateAuthentication(a *types.Authentication, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(a.IdentityProviders) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("identityProviders"), "at least one identity provider is required"))
+	}
+	names := sets.NewString()
+	for i, idp := range a.IdentityProviders {
+		idpPath := fldPath.Child("identityProviders").Index(i)
+		switch {
+		case idp.Name == "":
+			allErrs = append(allErrs, field.Required(idpPath.Child("name"), "name is required"))
+		case idp.Name == "." || idp.Name == ".." || strings.ContainsAny(idp.Name, "/%:"):
+			allErrs = append(allErrs, field.Invalid(idpPath.Child("name"), idp.Name, `must not be "." or ".." or contain "/", "%" or ":"`))
+		case names.Has(idp.Name):
+			allErrs = append(allErrs, field.Duplicate(idpPath.Child("name"), idp.Name))
+		}
+		names.Insert(idp.Name)
+
+		switch {
+		case idp.OpenID != nil && idp.HTPasswd != nil:
+			allErrs = append(allErrs, field.Invalid(idpPath, idp.Name, "only one of openID or htpasswd may be set"))
+		case idp.OpenID != nil:
+			allErrs = append(allErrs, validateOpenIDIdentityProvider(idp.OpenID, idpPath.Child("openID"))...)
+		case idp.HTPasswd != nil:
+			if idp.HTPasswd.File == "" {
+				allErrs = append(allErrs, field.Required(idpPath.Child("htpasswd", "file"), "the path of the htpasswd file is required"))
+			}
+		default:
+			allErrs = append(allErrs, field.Required(idpPath, "one of openID or htpasswd is required"))
+		}
+	}
+
+	if len(a.ClusterAdmins) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clusterAdmins"), "at least one cluster admin is required without the kubeadmin user"))
+	}
+	for i, admin := range a.ClusterAdmins {
+		if (admin.User == "") == (admin.Group == "") {
+			allErrs = append(allErrs, field.Required(fldPath.Child("clusterAdmins").Index(i), "exactly one of user or group is required"))
+		}
+	}
+	return allErrs
+}
+
+func validateOpenIDIdentityProvider(p *types.OpenIDIdentityProvider, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if err := validate.URIWithProtocol(p.Issuer, "https"); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("issuer"), p.Issuer, err.Error()))
+	} else if strings.ContainsAny(p.Issuer, "?#") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("issuer"), p.Issuer, "must not have a query or a fragment"))
+	}
+	if p.ClientID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clientID"), "clientID is required"))
+	}
+	if p.ClientSecret == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clientSecret"), "clientSecret is required"))
+	}
+	if p.CA != "" {
+		if err := validate.CABundle(p.CA); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ca"), p.CA, err.Error()))
+		}
+	}
+	return allErrs
+}
@@ -47,6 +47,25 @@ func validInstallConfig() *types.InstallConfig {
 	}
 }
 
+func validAuthentication() *types.Authentication {
+	return &types.Authentication{
+		IdentityProviders: []types.IdentityProvider{{
+			Name: "corp",
+			OpenID: &types.OpenIDIdentityProvider{
+				Issuer:       "https://sso.example.com/realms/corp",
+				ClientID:     "openshift",
+				ClientSecret: "secret",
+			},
+		}, {
+			Name: "local",
+			HTPasswd: &types.HTPasswdIdentityProvider{
+				File: "users.htpasswd",
+			},
+		}},
+		ClusterAdmins: []types.ClusterAdmin{{User: "alice"}, {Group: "platform-admins"}},
+	}
+}
+
 func validAWSPlatform() *aws.Platform {
 	return &aws.Platform{
 		Region: "us-east-1",
@@ -975,6 +994,106 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: `^credentialsMode: Unsupported value: "bad-mode": supported values: "Manual", "Mint", "Passthrough"$`,
 		},
+		{
+			name: "valid authentication",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				return c
+			}(),
+		},
+		{
+			name: "authentication without identity providers",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = &types.Authentication{}
+				return c
+			}(),
+			expectedError: `^\[authentication.identityProviders: Required value: at least one identity provider is required, authentication.clusterAdmins: Required value: at least one cluster admin is required without the kubeadmin user\]$`,
+		},
+		{
+			name: "duplicate identity provider",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				c.Authentication.IdentityProviders[1].Name = "corp"
+				return c
+			}(),
+			expectedError: `^authentication.identityProviders\[1\].name: Duplicate value: "corp"$`,
+		},
+		{
+			name: "invalid identity provider name",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				c.Authentication.IdentityProviders[0].Name = "corp:sso"
+				return c
+			}(),
+			expectedError: `^authentication.identityProviders\[0\].name: Invalid value: "corp:sso": must not be "." or ".." or contain "/", "%" or ":"$`,
+		},
+		{
+			name: "identity provider without type",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				c.Authentication.IdentityProviders[0].OpenID = nil
+				return c
+			}(),
+			expectedError: `^authentication.identityProviders\[0\]: Required value: one of openID or htpasswd is required$`,
+		},
+		{
+			name: "identity provider with two types",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				c.Authentication.IdentityProviders[0].HTPasswd = c.Authentication.IdentityProviders[1].HTPasswd
+				return c
+			}(),
+			expectedError: `^authentication.identityProviders\[0\]: Invalid value: "corp": only one of openID or htpasswd may be set$`,
+		},
+		{
+			name: "invalid openID identity provider",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				c.Authentication.IdentityProviders[0].OpenID = &types.OpenIDIdentityProvider{
+					Issuer: "https://sso.example.com/?realm=corp",
+					CA:     "not a certificate",
+				}
+				return c
+			}(),
+			expectedError: `^\[authentication.identityProviders\[0\].openID.issuer: Invalid value: "https://sso.example.com/\?realm=corp": must not have a query or a fragment, authentication.identityProviders\[0\].openID.clientID: Required value: clientID is required, authentication.identityProviders\[0\].openID.clientSecret: Required value: clientSecret is required, authentication.identityProviders\[0\].openID.ca: Invalid value: "not a certificate": .*\]$`,
+		},
+		{
+			name: "openID identity provider without https",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				c.Authentication.IdentityProviders[0].OpenID.Issuer = "http://sso.example.com"
+				return c
+			}(),
+			expectedError: `^authentication.identityProviders\[0\].openID.issuer: Invalid value: "http://sso.example.com": must use https protocol$`,
+		},
+		{
+			name: "htpasswd identity provider without file",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				c.Authentication.IdentityProviders[1].HTPasswd.File = ""
+				return c
+			}(),
+			expectedError: `^authentication.identityProviders\[1\].htpasswd.file: Required value: the path of the htpasswd file is required$`,
+		},
+		{
+			name: "cluster admin with user and group",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Authentication = validAuthentication()
+				c.Authentication.ClusterAdmins[0].Group = "admins"
+				return c
+			}(),
+			expectedError: `^authentication.clusterAdmins\[0\]: Required value: exactly one of user or group is required$`,
+		},
 		{
 			name: "allowed docker bridge with non-libvirt",
 			installConfig: func() *types.InstallConfig {